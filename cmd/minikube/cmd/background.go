@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// cacheSudo asks for the password of sudo now, as processes running in the background can not
+func cacheSudo(why string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	out.T(style.Permissions, why)
+	sudo := exec.Command("sudo", "-v")
+	sudo.Stdin, sudo.Stdout, sudo.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := sudo.Run(); err != nil {
+		out.WarningT("Unable to cache sudo credentials: {{.error}}", out.V{"error": err})
+	}
+}
+
+// startBackground runs minikube with args in the background, logging to logPath, and waits up to a minute for
+// running to report that the process with its pid runs
+func startBackground(args []string, env []string, logPath string, running func(pid int) bool) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, errors.Wrap(err, "finding the minikube binary")
+	}
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, errors.Wrap(err, "opening the log")
+	}
+	defer log.Close()
+
+	c := exec.Command(exe, args...)
+	c.Env = append(append(os.Environ(), env...), out.OverrideEnv+"=false")
+	c.Stdout, c.Stderr = log, log
+	klog.Infof("starting in the background: %s", c.Args)
+	if err := c.Start(); err != nil {
+		return 0, err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- c.Wait() }()
+
+	for deadline := time.Now().Add(time.Minute); time.Now().Before(deadline); {
+		select {
+		case err := <-exited:
+			return 0, fmt.Errorf("exited (%v), see its log: %s", err, logPath)
+		case <-time.After(250 * time.Millisecond):
+		}
+		if running(c.Process.Pid) {
+			return c.Process.Pid, nil
+		}
+	}
+	// The caller reports the failure, so the process must not linger unnoticed
+	if err := c.Process.Kill(); err != nil {
+		klog.Warningf("kill %d: %v", c.Process.Pid, err)
+	}
+	return 0, fmt.Errorf("did not start within a minute, see its log: %s", logPath)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	bundleKubernetesVersion string
+	bundleDriver            string
+	bundleContainerRuntime  string
+	bundleAddons            []string
+	bundleOutput            string
+	bundleSigningKey        string
+	bundlePublicKey         string
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and import bundles for starting clusters without network access",
+	Long: `Create and import signed bundles of everything minikube downloads to start a cluster: the VM boot image or base image, the preloaded images, the Kubernetes binaries and the images of addons.
+
+Create a bundle on a machine with network access, then import it on machines without, so that 'minikube start' does not need the network.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube bundle [create|import]")
+	},
+}
+
+// bundleCreateCmd represents the bundle create command
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a bundle for starting clusters without network access",
+	Long: `Create a bundle for starting clusters without network access, with the given driver, container runtime and Kubernetes version.
+
+The bundle is signed with the key given by --signing-key, which is generated on first use. Its public key, which is needed to import the bundle, is written beside it with a .pub extension.`,
+	Example: "minikube bundle create --driver=kvm2 --container-runtime=containerd --kubernetes-version=v1.19.2 --addons=metrics-server",
+	Run: func(cmd *cobra.Command, args []string) {
+		o := bundleOptions()
+		output := bundleOutput
+		if output == "" {
+			output = fmt.Sprintf("minikube-bundle-%s-%s-%s-%s.tar.gz", o.KubernetesVersion, o.Driver, o.ContainerRuntime, version.GetVersion())
+		}
+
+		if bundleSigningKey == "" {
+			bundleSigningKey = bundle.DefaultKeyPath()
+		}
+		key, err := bundle.LoadOrCreateKey(bundleSigningKey)
+		if err != nil {
+			exit.Error(reason.InetBundleCreate, "Unable to load the signing key", err)
+		}
+
+		m, paths, err := bundle.Collect(o)
+		if err != nil {
+			exit.Error(reason.InetBundleCreate, "Failed to download the contents of the bundle", err)
+		}
+
+		out.T(style.Copying, "Writing {{.count}} files to {{.path}} ...", out.V{"count": len(paths), "path": output})
+		f, err := os.Create(output)
+		if err != nil {
+			exit.Error(reason.InetBundleCreate, "Unable to create the bundle", err)
+		}
+		err = bundle.Write(f, m, localpath.MiniPath(), paths, key)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			if rerr := os.Remove(output); rerr != nil {
+				klog.Warningf("unable to remove %s: %v", output, rerr)
+			}
+			exit.Error(reason.InetBundleCreate, "Failed to write the bundle", err)
+		}
+
+		out.T(style.Success, "Created bundle {{.path}}", out.V{"path": output})
+		out.T(style.Tip, "To import it, copy it along with the public key {{.key}}, then run: 'minikube bundle import {{.path}} --public-key={{.key}}'", out.V{"path": output, "key": bundle.PublicKeyPath(bundleSigningKey)})
+	},
+}
+
+// bundleImportCmd represents the bundle import command
+var bundleImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import a bundle, so that clusters start without network access",
+	Long: `Import a bundle into the minikube cache, so that clusters start without network access.
+The bundle is only imported if it is signed with the key of --public-key, and its contents match their checksums.`,
+	Example: "minikube bundle import minikube-bundle.tar.gz --public-key=bundle.key.pub",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide the bundle to import")
+		}
+		if bundlePublicKey == "" {
+			bundlePublicKey = bundle.PublicKeyPath(bundle.DefaultKeyPath())
+		}
+		pub, err := bundle.ReadPublicKey(bundlePublicKey)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to read the public key of the bundle: {{.error}}. Please specify it with --public-key", out.V{"error": err})
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			exit.Message(reason.HostPathMissing, "Unable to open the bundle {{.path}}: {{.error}}", out.V{"path": args[0], "error": err})
+		}
+		defer f.Close()
+
+		out.T(style.Copying, "Importing bundle {{.path}} ...", out.V{"path": args[0]})
+		m, err := bundle.Import(f, localpath.MiniPath(), pub)
+		if err != nil {
+			exit.Error(reason.HostBundleImport, "Failed to import the bundle", err)
+		}
+		if len(m.Images) > 0 {
+			// Images in the cache config are loaded into the nodes on start
+			if err := cmdConfig.AddToConfigMap(cacheImageConfigKey, m.Images); err != nil {
+				exit.Error(reason.InternalAddConfig, "Failed to update config", err)
+			}
+		}
+
+		out.T(style.Success, "Imported bundle for Kubernetes {{.version}} with the {{.driver}} driver and {{.runtime}}, created by minikube {{.minikube}}", out.V{"version": m.KubernetesVersion, "driver": m.Driver, "runtime": m.ContainerRuntime, "minikube": m.MinikubeVersion})
+		if m.MinikubeVersion != version.GetVersion() {
+			out.WarningT("The bundle was created by minikube {{.bundle}}, but this is minikube {{.version}}, which may download other artifacts", out.V{"bundle": m.MinikubeVersion, "version": version.GetVersion()})
+		}
+		out.T(style.Tip, "To start a cluster from the bundle, run: 'minikube start --driver={{.driver}} --container-runtime={{.runtime}} --kubernetes-version={{.version}}'", out.V{"driver": m.Driver, "runtime": m.ContainerRuntime, "version": m.KubernetesVersion})
+	},
+}
+
+// bundleOptions validates the flags of 'bundle create'
+func bundleOptions() bundle.Options {
+	if bundleDriver == "" {
+		exit.Message(reason.Usage, "Please specify the driver of the clusters the bundle is for with --driver")
+	}
+	if !driver.Supported(bundleDriver) {
+		exit.Message(reason.Usage, "The driver {{.driver}} is not supported. Supported drivers: {{.drivers}}", out.V{"driver": bundleDriver, "drivers": strings.Join(driver.SupportedDrivers(), ", ")})
+	}
+	validRuntime := false
+	for _, r := range append(cruntime.ValidRuntimes(), constants.CRIO) {
+		if bundleContainerRuntime == r {
+			validRuntime = true
+		}
+	}
+	if !validRuntime {
+		exit.Message(reason.Usage, `Invalid Container Runtime: "{{.runtime}}". Valid runtimes are: {{.validOptions}}`, out.V{"runtime": bundleContainerRuntime, "validOptions": strings.Join(cruntime.ValidRuntimes(), ", ")})
+	}
+	v, err := util.ParseKubernetesVersion(bundleKubernetesVersion)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid Kubernetes version {{.version}}: {{.error}}", out.V{"version": bundleKubernetesVersion, "error": err})
+	}
+	return bundle.Options{
+		KubernetesVersion: version.VersionPrefix + v.String(),
+		Driver:            bundleDriver,
+		ContainerRuntime:  bundleContainerRuntime,
+		Addons:            bundleAddons,
+	}
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bundleKubernetesVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version of the clusters the bundle is for")
+	bundleCreateCmd.Flags().StringVar(&bundleDriver, "driver", "", "The driver of the clusters the bundle is for")
+	bundleCreateCmd.Flags().StringVar(&bundleContainerRuntime, "container-runtime", "docker", "The container runtime of the clusters the bundle is for")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleAddons, "addons", nil, "Addons whose images are bundled, so that they can be enabled")
+	bundleCreateCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "The file to write the bundle to (default \"minikube-bundle-<kubernetes-version>-<driver>-<container-runtime>-<minikube-version>.tar.gz\")")
+	bundleCreateCmd.Flags().StringVar(&bundleSigningKey, "signing-key", "", "The ed25519 private key to sign the bundle with, generated if missing (default \"$MINIKUBE_HOME/certs/bundle.key\")")
+	bundleImportCmd.Flags().StringVar(&bundlePublicKey, "public-key", "", "The public key of the key the bundle is signed with (default \"$MINIKUBE_HOME/certs/bundle.key.pub\")")
+
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleImportCmd)
+}
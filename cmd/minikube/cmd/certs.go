@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Inspect and rotate the certificates of a cluster",
+	Long: `Inspect and rotate the certificates of a running cluster: those of the API server, the aggregator proxy, etcd and the kubelets of every node.
+
+minikube renews the certificates it generates on start once they are within 30 days of expiring. The certificates generated by kubeadm and the kubelet are renewed by 'minikube certs rotate'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube certs [status|rotate]")
+	},
+}
+
+// certsStatusCmd represents the certs status command
+var certsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show when the certificates of a cluster expire",
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		defer co.API.Close()
+
+		certs, err := node.CertExpiries(co.API, co.Config)
+		if err != nil {
+			exit.Error(reason.CertsStatus, "Unable to read the certificates of the cluster", err)
+		}
+
+		now := time.Now()
+		expiring := false
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Node", "Certificate", "Subject", "Expires", "Remaining", "Status"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, n := range certs {
+			for _, c := range n.Certs {
+				status := certStatus(c.NotAfter, now)
+				if status != "OK" {
+					expiring = true
+				}
+				remaining := "-"
+				if c.NotAfter.After(now) {
+					remaining = units.HumanDuration(c.NotAfter.Sub(now))
+				}
+				table.Append([]string{n.Name, c.Name, c.Subject, c.NotAfter.Format(time.RFC3339), remaining, status})
+			}
+		}
+		table.Render()
+
+		if expiring {
+			out.T(style.Tip, "To renew the certificates of the cluster, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(co.Config.Name, "certs rotate")})
+		}
+	},
+}
+
+// certsRotateCmd represents the certs rotate command
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Renew the certificates of a cluster",
+	Long: `Renew the certificates of a running cluster, signed by its CA, and distribute them to every node.
+The control plane components and kubelets are restarted to load their new certificates, so the API server is briefly unavailable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		defer co.API.Close()
+
+		out.T(style.Restarting, "Rotating the certificates of {{.cluster}} ...", out.V{"cluster": co.Config.Name})
+		if err := node.RotateCerts(co.API, co.Config); err != nil {
+			exit.Error(reason.CertsRotate, "Failed to rotate the certificates", err)
+		}
+		out.T(style.Success, "Rotated the certificates of {{.cluster}}", out.V{"cluster": co.Config.Name})
+	},
+}
+
+// certStatus describes whether a certificate expiring at notAfter needs to be renewed
+func certStatus(notAfter time.Time, now time.Time) string {
+	switch {
+	case !now.Before(notAfter):
+		return "EXPIRED"
+	case now.Add(bootstrapper.CertRenewalWindow).After(notAfter):
+		return "expiring soon"
+	default:
+		return "OK"
+	}
+}
+
+func init() {
+	certsCmd.AddCommand(certsStatusCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCertStatus(t *testing.T) {
+	now := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		notAfter time.Time
+		expected string
+	}{
+		{now.Add(365 * 24 * time.Hour), "OK"},
+		{now.Add(10 * 24 * time.Hour), "expiring soon"},
+		{now, "EXPIRED"},
+		{now.Add(-time.Hour), "EXPIRED"},
+	}
+	for _, tc := range tests {
+		if got := certStatus(tc.notAfter, now); got != tc.expected {
+			t.Errorf("certStatus(%s) = %q, expected %q", tc.notAfter, got, tc.expected)
+		}
+	}
+}
@@ -188,7 +188,7 @@ var addonsConfigureCmd = &cobra.Command{
 				out.WarningT("ERROR creating `registry-creds-acr` secret")
 			}
 
-		case "metallb":
+		case "metallb", "loadbalancer-controller":
 			profile := ClusterFlagValue()
 			_, cfg := mustload.Partial(profile)
 
@@ -196,11 +196,12 @@ var addonsConfigureCmd = &cobra.Command{
 				return net.ParseIP(s) != nil
 			}
 
-			if cfg.KubernetesConfig.LoadBalancerStartIP == "" {
+			// minikube reserves a range when the loadbalancer-controller addon is enabled, which can be replaced
+			if cfg.KubernetesConfig.LoadBalancerStartIP == "" || addon == "loadbalancer-controller" {
 				cfg.KubernetesConfig.LoadBalancerStartIP = AskForStaticValidatedValue("-- Enter Load Balancer Start IP: ", validator)
 			}
 
-			if cfg.KubernetesConfig.LoadBalancerEndIP == "" {
+			if cfg.KubernetesConfig.LoadBalancerEndIP == "" || addon == "loadbalancer-controller" {
 				cfg.KubernetesConfig.LoadBalancerEndIP = AskForStaticValidatedValue("-- Enter Load Balancer End IP: ", validator)
 			}
 
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/minikube/pkg/minikube/clusterfile"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var exportOutput string
+
+var profileExportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export the definition of a profile",
+	Long:    "Export the definition of a profile as a cluster definition file, which 'minikube start --config' accepts. Secrets are exported as REDACTED, and must be supplied again by flags to 'minikube start'.",
+	Example: "minikube profile export -p dev > cluster.yaml\nminikube start --config cluster.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := viper.GetString(config.ProfileName)
+		cc, err := config.Load(name)
+		if err != nil {
+			if config.IsNotExist(err) {
+				exit.Message(reason.Usage, `There is no local cluster named "{{.cluster}}"`, out.V{"cluster": name})
+			}
+			exit.Error(reason.HostConfigLoad, "Error getting cluster config", err)
+		}
+
+		cf := clusterfile.FromClusterConfig(cc)
+		switch strings.ToLower(exportOutput) {
+		case "yaml":
+			data, err := yaml.Marshal(cf)
+			if err != nil {
+				exit.Error(reason.InternalYamlMarshal, "Unable to marshal cluster definition", err)
+			}
+			out.String("%s", data)
+		case "json":
+			data, err := json.MarshalIndent(cf, "", "  ")
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "Unable to marshal cluster definition", err)
+			}
+			out.String("%s\n", data)
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'yaml', 'json'", exportOutput))
+		}
+	},
+}
+
+func init() {
+	profileExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "yaml", "The output format. One of 'yaml', 'json'")
+	ProfileCmd.AddCommand(profileExportCmd)
+}
@@ -103,7 +103,7 @@ func profileStatus(p *config.Profile, api libmachine.API) string {
 		return "Unknown"
 	}
 
-	hostname, _, port, err := driver.ControlPlaneEndpoint(p.Config, &cp, host.DriverName)
+	hostname, _, port, err := driver.APIServerEndpoint(p.Config, &cp, host.DriverName)
 	if err != nil {
 		klog.Warningf("error loading profiles: %v", err)
 		return "Unknown"
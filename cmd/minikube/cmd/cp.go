@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var syncFiles bool
+
+// remotePath is a path which may be qualified with a node name, such as m02:/etc/hosts
+type remotePath struct {
+	node string
+	path string
+}
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp [node:]<source> [node:]<target>",
+	Short: "Copy files and directories between the host and a node",
+	Long: `Copy files and directories between the host and a node, preserving their permissions.
+
+Paths prefixed with a node name refer to that node. An unprefixed target refers to the node selected by --node, so that copying into the primary control plane only requires the path.`,
+	Example: `minikube cp a.txt /home/docker/a.txt
+minikube cp ./manifests m02:/etc/kubernetes/addons
+minikube cp minikube:/var/log/pods ./pods
+minikube cp --sync`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		if co.CP.Host.DriverName == driver.None {
+			exit.Message(reason.Usage, "'none' driver does not support 'minikube cp' command")
+		}
+
+		if syncFiles {
+			if len(args) != 0 {
+				exit.Message(reason.Usage, "--sync does not accept any arguments")
+			}
+			n := copyNode(co.Config, nodeName)
+			if err := machine.SyncLocalAssets(copyRunner(co, n)); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to sync files", err)
+			}
+			out.T(style.Copying, "Synced {{.path}} to {{.node}}", out.V{"path": localpath.MakeMiniPath("files"), "node": driver.MachineName(*co.Config, *n)})
+			return
+		}
+
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "Usage: minikube cp [node:]<source> [node:]<target>")
+		}
+		src := parseRemotePath(co.Config, args[0])
+		dst := parseRemotePath(co.Config, args[1])
+
+		if src.node != "" && dst.node != "" {
+			exit.Message(reason.Usage, "Copying between nodes is not supported, copy to the host first")
+		}
+
+		if src.node != "" {
+			n := copyNode(co.Config, src.node)
+			if err := machine.CopyFromNode(copyRunner(co, n), src.path, args[1]); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to copy file", err)
+			}
+			return
+		}
+
+		name := dst.node
+		if name == "" {
+			name = nodeName
+		}
+		n := copyNode(co.Config, name)
+		if err := machine.CopyToNode(copyRunner(co, n), args[0], dst.path); err != nil {
+			exit.Error(reason.GuestCopy, "Failed to copy file", err)
+		}
+	},
+}
+
+// parseRemotePath splits a path into its node and path components
+func parseRemotePath(cc *config.ClusterConfig, p string) remotePath {
+	i := strings.Index(p, ":")
+	if i <= 0 {
+		return remotePath{path: p}
+	}
+
+	// Only treat the prefix as a node if it names one, so that host paths such as C:\ keep working
+	name := p[:i]
+	if _, _, err := node.Retrieve(*cc, name); err != nil {
+		return remotePath{path: p}
+	}
+	return remotePath{node: name, path: p[i+1:]}
+}
+
+// copyNode returns the node to copy to or from, defaulting to the primary control plane
+func copyNode(cc *config.ClusterConfig, name string) *config.Node {
+	if name == "" {
+		cp, err := config.PrimaryControlPlane(cc)
+		if err != nil {
+			exit.Error(reason.GuestCpConfig, "Error getting primary control plane", err)
+		}
+		return &cp
+	}
+
+	n, _, err := node.Retrieve(*cc, name)
+	if err != nil {
+		exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": name})
+	}
+	return n
+}
+
+// copyRunner returns a command runner for a running node
+func copyRunner(co mustload.ClusterController, n *config.Node) command.Runner {
+	h, err := machine.LoadHost(co.API, driver.MachineName(*co.Config, *n))
+	if err != nil {
+		exit.Error(reason.GuestLoadHost, "Error getting host", err)
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+	}
+	return r
+}
+
+func init() {
+	cpCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to copy to when the target is not prefixed with a node name. Defaults to the primary control plane.")
+	cpCmd.Flags().BoolVar(&syncFiles, "sync", false, "Re-sync the files in $MINIKUBE_HOME/files and $MINIKUBE_HOME/addons into the node, as is done on start")
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseRemotePath(t *testing.T) {
+	cc := &config.ClusterConfig{
+		Name:  "minikube",
+		Nodes: []config.Node{{Name: "", ControlPlane: true}, {Name: "m02", Worker: true}},
+	}
+	tests := []struct {
+		arg  string
+		want remotePath
+	}{
+		{"/etc/hosts", remotePath{path: "/etc/hosts"}},
+		{"m02:/etc/hosts", remotePath{node: "m02", path: "/etc/hosts"}},
+		{"minikube:/var/log", remotePath{node: "minikube", path: "/var/log"}},
+		{`C:\Users\docker\a.txt`, remotePath{path: `C:\Users\docker\a.txt`}},
+		{"m03:/etc/hosts", remotePath{path: "m03:/etc/hosts"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.arg, func(t *testing.T) {
+			if got := parseRemotePath(cc, tc.arg); got != tc.want {
+				t.Errorf("parseRemotePath(%q) = %+v, want %+v", tc.arg, got, tc.want)
+			}
+		})
+	}
+}
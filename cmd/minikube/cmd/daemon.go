@@ -0,0 +1,421 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// exitCodeTrailer is the HTTP trailer holding the exit code of a streamed operation
+const exitCodeTrailer = "X-Minikube-Exit-Code"
+
+var daemonSocket string
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Serves minikube operations over a local socket",
+	Long: `Serves the operations of minikube over a Unix socket, as a REST API, so that tools can manage clusters without running and parsing the minikube CLI.
+
+Operations which change a profile run as minikube subprocesses, one at a time per profile, and stream their progress as lines of CloudEvents, like 'minikube start --output=json'. See https://minikube.sigs.k8s.io/docs/handbook/daemon/ for the API.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exe, err := os.Executable()
+		if err != nil {
+			exit.Error(reason.HostDaemon, "Unable to find the minikube binary", err)
+		}
+		if daemonSocket == "" {
+			daemonSocket = localpath.DaemonSocket()
+		}
+		l, err := listenDaemon(daemonSocket)
+		if err != nil {
+			exit.Error(reason.HostDaemon, "Unable to listen on the daemon socket", err)
+		}
+
+		srv := &http.Server{Handler: newDaemon(exe)}
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-c
+			klog.Infof("stopping the daemon")
+			if err := srv.Close(); err != nil {
+				klog.Warningf("close: %v", err)
+			}
+		}()
+
+		out.T(style.Ready, "Serving minikube on {{.socket}}", out.V{"socket": daemonSocket})
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			exit.Error(reason.HostDaemon, "Daemon failed", err)
+		}
+	},
+}
+
+// listenDaemon listens on a Unix socket, replacing the socket of a daemon which is no longer running
+func listenDaemon(path string) (net.Listener, error) {
+	if c, err := net.Dial("unix", path); err == nil {
+		c.Close()
+		return nil, errors.Errorf("another daemon is serving %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "remove stale socket")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "listen")
+	}
+	// Whoever can connect to the socket can manage the clusters of the user
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, errors.Wrap(err, "chmod")
+	}
+	return l, nil
+}
+
+// daemon serves the minikube operations of the daemon API
+type daemon struct {
+	// exe is the minikube binary which runs the operations changing profiles
+	exe string
+
+	mu sync.Mutex
+	// locks serialize the operations changing each profile
+	locks map[string]*sync.Mutex
+}
+
+func newDaemon(exe string) *daemon {
+	return &daemon{exe: exe, locks: map[string]*sync.Mutex{}}
+}
+
+// ServeHTTP routes the requests of the daemon API, all of which are under /v1/profiles
+func (d *daemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	klog.Infof("%s %s", r.Method, r.URL)
+	p := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(p) < 2 || p[0] != "v1" || p[1] != "profiles" {
+		daemonError(w, http.StatusNotFound, errors.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+	if len(p) == 2 {
+		if allowMethod(w, r, http.MethodGet) {
+			d.profiles(w)
+		}
+		return
+	}
+
+	name := p[2]
+	if !config.ProfileNameValid(name) {
+		daemonError(w, http.StatusBadRequest, errors.Errorf("invalid profile name %q", name))
+		return
+	}
+	op := strings.Join(p[3:], "/")
+	switch {
+	case op == "":
+		if allowMethod(w, r, http.MethodDelete) {
+			d.stream(w, name, "delete", "-p", name)
+		}
+	case op == "start":
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		args, err := startArgs(r.Body)
+		if err != nil {
+			daemonError(w, http.StatusBadRequest, err)
+			return
+		}
+		d.stream(w, name, append([]string{"start", "-p", name, "--output=json"}, args...)...)
+	case op == "stop":
+		if allowMethod(w, r, http.MethodPost) {
+			d.stream(w, name, "stop", "-p", name, "--output=json")
+		}
+	case op == "status":
+		if allowMethod(w, r, http.MethodGet) {
+			d.status(w, name)
+		}
+	case op == "addons":
+		if allowMethod(w, r, http.MethodGet) {
+			d.addons(w, name)
+		}
+	case len(p) == 6 && p[3] == "addons" && (p[5] == "enable" || p[5] == "disable"):
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		if _, ok := assets.Addons[p[4]]; !ok {
+			daemonError(w, http.StatusNotFound, errors.Errorf("unknown addon %q", p[4]))
+			return
+		}
+		d.stream(w, name, "addons", p[5], p[4], "-p", name)
+	case op == "services":
+		if allowMethod(w, r, http.MethodGet) {
+			d.services(w, name, r.URL.Query().Get("namespace"))
+		}
+	case op == "logs":
+		if allowMethod(w, r, http.MethodGet) {
+			d.logs(w, r, name)
+		}
+	default:
+		daemonError(w, http.StatusNotFound, errors.Errorf("unknown path %s", r.URL.Path))
+	}
+}
+
+// logs streams the logs of a cluster, or of one of its nodes, as text
+func (d *daemon) logs(w http.ResponseWriter, r *http.Request, name string) {
+	args := []string{"logs", "-p", name}
+	if n := r.URL.Query().Get("node"); n != "" {
+		args = append(args, "--node", n)
+	}
+	if n := r.URL.Query().Get("length"); n != "" {
+		if _, err := strconv.Atoi(n); err != nil {
+			daemonError(w, http.StatusBadRequest, errors.Errorf("invalid length %q", n))
+			return
+		}
+		args = append(args, "--length", n)
+	}
+	// Reading the logs changes nothing, so it does not wait for the operations changing the profile
+	d.run(w, "text/plain; charset=utf-8", false, args...)
+}
+
+// startArgs reads the extra flags of a start request, such as {"args": ["--driver=docker"]}
+func startArgs(body io.Reader) ([]string, error) {
+	var req struct {
+		Args []string `json:"args"`
+	}
+	if err := json.NewDecoder(body).Decode(&req); err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "decoding request")
+	}
+	for _, a := range req.Args {
+		f := strings.SplitN(a, "=", 2)[0]
+		if f == "-p" || f == "--profile" || f == "-o" || f == "--output" {
+			return nil, errors.Errorf("the %s flag is set by the daemon", f)
+		}
+	}
+	return req.Args, nil
+}
+
+// allowMethod answers a request with the wrong method for an operation, returning whether the method is right
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	daemonError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+	return false
+}
+
+// daemonError answers a request with an error, as {"error": "..."}
+func daemonError(w http.ResponseWriter, code int, err error) {
+	klog.Warningf("daemon: %d: %v", code, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}); err != nil {
+		klog.Warningf("write error: %v", err)
+	}
+}
+
+// daemonJSON answers a request with a JSON document
+func daemonJSON(w http.ResponseWriter, v interface{}) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, errors.Wrap(err, "marshal"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(bs); err != nil {
+		klog.Warningf("write: %v", err)
+	}
+}
+
+// profiles answers with the valid and invalid profiles, like 'minikube profile list --output=json'
+func (d *daemon) profiles(w http.ResponseWriter) {
+	valid, invalid, err := config.ListProfiles()
+	if err != nil && !config.IsNotExist(err) && !os.IsNotExist(err) {
+		daemonError(w, http.StatusInternalServerError, err)
+		return
+	}
+	daemonJSON(w, map[string]interface{}{"valid": valid, "invalid": invalid})
+}
+
+// status answers with the state of a cluster, like 'minikube status --output=json --layout=cluster'
+func (d *daemon) status(w http.ResponseWriter, name string) {
+	if _, err := d.profile(w, name); err != nil {
+		return
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer api.Close()
+	daemonJSON(w, loadClusterState(api, name))
+}
+
+// addons answers with whether each addon is enabled for a profile
+func (d *daemon) addons(w http.ResponseWriter, name string) {
+	cc, err := d.profile(w, name)
+	if err != nil {
+		return
+	}
+	enabled := map[string]bool{}
+	for n, a := range assets.Addons {
+		enabled[n] = a.IsEnabled(cc)
+	}
+	daemonJSON(w, enabled)
+}
+
+// services answers with the URLs of the services of a cluster, in one or every namespace
+func (d *daemon) services(w http.ResponseWriter, name string, namespace string) {
+	if _, err := d.profile(w, name); err != nil {
+		return
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer api.Close()
+
+	urls, err := service.GetServiceURLs(api, name, namespace, nil)
+	if err != nil {
+		daemonError(w, http.StatusServiceUnavailable, errors.Wrap(err, "services"))
+		return
+	}
+	daemonJSON(w, urls)
+}
+
+// profile loads the config of a profile, answering the request if it can not
+func (d *daemon) profile(w http.ResponseWriter, name string) (*config.ClusterConfig, error) {
+	cc, err := config.Load(name)
+	if err == nil {
+		return cc, nil
+	}
+	if config.IsNotExist(err) {
+		daemonError(w, http.StatusNotFound, errors.Errorf("no profile named %q", name))
+	} else {
+		daemonError(w, http.StatusInternalServerError, err)
+	}
+	return nil, err
+}
+
+// stream runs an operation changing a profile, streaming its progress as lines of CloudEvents. Operations on the same
+// profile are run one at a time, and their machines are protected from the other profiles by the machines lock,
+// like those of concurrent CLI commands.
+func (d *daemon) stream(w http.ResponseWriter, name string, args ...string) {
+	l := d.profileLock(name)
+	l.Lock()
+	defer l.Unlock()
+	d.run(w, "application/x-ndjson", true, args...)
+}
+
+// run runs minikube as a subprocess, streaming its output
+func (d *daemon) run(w http.ResponseWriter, contentType string, events bool, args ...string) {
+	c := exec.Command(d.exe, args...)
+	c.Env = append(os.Environ(), fmt.Sprintf("%s=false", out.OverrideEnv))
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+		return
+	}
+	klog.Infof("daemon: running %s", c.Args)
+	if err := c.Start(); err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Trailer", exitCodeTrailer)
+	w.WriteHeader(http.StatusOK)
+	f, _ := w.(http.Flusher)
+
+	// The operation runs to completion even if the client goes away, so that the profile is left consistent
+	s := bufio.NewScanner(stdout)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := s.Bytes()
+		if events && !json.Valid(line) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			if line, err = register.MarshalInfo(string(line)); err != nil {
+				klog.Warningf("marshal: %v", err)
+				continue
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+			klog.V(1).Infof("write: %v", err)
+			continue
+		}
+		if f != nil {
+			f.Flush()
+		}
+	}
+
+	code := 0
+	if err := c.Wait(); err != nil {
+		code = -1
+		if ee, ok := err.(*exec.ExitError); ok {
+			code = ee.ExitCode()
+		}
+		klog.Warningf("daemon: %s failed: %v\n%s", c.Args, err, stderr.String())
+	}
+	w.Header().Set(exitCodeTrailer, strconv.Itoa(code))
+}
+
+// profileLock returns the lock serializing the operations changing a profile
+func (d *daemon) profileLock(name string) *sync.Mutex {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	l, ok := d.locks[name]
+	if !ok {
+		l = &sync.Mutex{}
+		d.locks[name] = l
+	}
+	return l
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&daemonSocket, "socket", "", "The Unix socket to serve the API on. Defaults to daemon.sock in the minikube home directory.")
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestDaemonRoutes(t *testing.T) {
+	home, err := ioutil.TempDir("", "minikube-daemon")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, home)
+
+	d := newDaemon("false")
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{http.MethodGet, "/v1/profiles", "", http.StatusOK},
+		{http.MethodPost, "/v1/profiles", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v2/profiles", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/profiles/minikube/explode", "", http.StatusNotFound},
+		{http.MethodPost, "/v1/profiles/minikube/status", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/profiles/minikube/start", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/v1/profiles/minikube/start", `{"args": ["-p=other"]}`, http.StatusBadRequest},
+		{http.MethodPost, "/v1/profiles/minikube/start", `{"args": `, http.StatusBadRequest},
+		{http.MethodPost, "/v1/profiles/minikube/addons/explode/enable", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/profiles/minikube/addons", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/profiles/minikube/status", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/profiles/minikube/logs?length=all", "", http.StatusBadRequest},
+		{http.MethodGet, "/v1/profiles/-invalid/status", "", http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			d.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+			if w.Code != tc.want {
+				t.Errorf("%s %s = %d, want %d: %s", tc.method, tc.path, w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDaemonStream(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake minikube binary is a shell script")
+	}
+	dir, err := ioutil.TempDir("", "minikube-daemon")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	exe := filepath.Join(dir, "minikube")
+	script := `#!/bin/sh
+echo '{"specversion":"1.0","type":"io.k8s.sigs.minikube.step","data":{"name":"Initial Minikube Setup"}}'
+echo "args: $*"
+exit 3
+`
+	if err := ioutil.WriteFile(exe, []byte(script), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	d := newDaemon(exe)
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/profiles/p1/start", strings.NewReader(`{"args": ["--driver=none"]}`)))
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	if got := res.Trailer.Get(exitCodeTrailer); got != "3" {
+		t.Errorf("exit code trailer = %q, want %q", got, "3")
+	}
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), w.Body.String())
+	}
+	var ev struct {
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &ev); err != nil || ev.Type != "io.k8s.sigs.minikube.step" {
+		t.Errorf("first line = %s, want the step event of the operation (err=%v)", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatalf("second line is not an event: %v", err)
+	}
+	if want := "args: start -p p1 --output=json --driver=none"; ev.Type != "io.k8s.sigs.minikube.info" || ev.Data["message"] != want {
+		t.Errorf("second line = %+v, want an info event with message %q", ev, want)
+	}
+}
+
+func TestDaemonLogsDoNotWait(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake minikube binary is a shell script")
+	}
+	dir, err := ioutil.TempDir("", "minikube-daemon")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	exe := filepath.Join(dir, "minikube")
+	if err := ioutil.WriteFile(exe, []byte("#!/bin/sh\necho \"args: $*\"\n"), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	d := newDaemon(exe)
+	// An operation changing the profile is running
+	l := d.profileLock("p1")
+	l.Lock()
+	defer l.Unlock()
+
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		d.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/profiles/p1/logs?length=10", nil))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("logs waited for the operation changing the profile")
+	}
+	if want := "args: logs -p p1 --length 10"; strings.TrimSpace(w.Body.String()) != want {
+		t.Errorf("logs = %q, want %q", w.Body.String(), want)
+	}
+}
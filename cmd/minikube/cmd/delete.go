@@ -40,12 +40,14 @@ import (
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hostdns"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
@@ -321,15 +323,32 @@ func deleteProfile(profile *config.Profile) error {
 		}
 	}
 
+	if err == nil && driver.IsSSH(cc.Driver) {
+		// The machines of the ssh driver outlive the cluster, so Kubernetes is uninstalled from each of them
+		for _, n := range cc.Nodes {
+			if err := uninstallKubernetes(api, *cc, n, viper.GetString(cmdcfg.Bootstrapper)); err != nil {
+				out.WarningT("Unable to uninstall Kubernetes from {{.name}}: {{.error}}", out.V{"name": driver.MachineName(*cc, n), "error": err})
+			}
+		}
+	}
+
 	if err := killMountProcess(); err != nil {
 		out.FailureT("Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
 
+	if err := hostdns.Cleanup(profile.Name); err != nil {
+		out.FailureT("Failed to remove the DNS configuration of the host: {{.error}}", out.V{"error": err})
+	}
+
 	deleteHosts(api, cc)
 
 	// In case DeleteHost didn't complete the job.
 	deleteProfileDirectory(profile.Name)
 
+	if err := snapshot.DeleteAll(profile.Name); err != nil {
+		klog.Warningf("failed to remove snapshots of %s: %v", profile.Name, err)
+	}
+
 	if err := deleteConfig(profile.Name); err != nil {
 		return err
 	}
@@ -426,7 +445,7 @@ func uninstallKubernetes(api libmachine.API, cc config.ClusterConfig, n config.N
 		return DeletionError{Err: fmt.Errorf("unable to get bootstrapper: %v", err), Errtype: Fatal}
 	}
 
-	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r})
+	cr, err := cruntime.ForCluster(cc.KubernetesConfig, r)
 	if err != nil {
 		return DeletionError{Err: fmt.Errorf("unable to get runtime: %v", err), Errtype: Fatal}
 	}
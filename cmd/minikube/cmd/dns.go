@@ -0,0 +1,303 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	networking "k8s.io/api/networking/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hostdns"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var dnsMode string
+
+// dnsCmd represents the dns command
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Resolve the names of a cluster and the hosts of its Ingresses on the host",
+	Long: `Resolve <profile>.test, the names within it, and the hosts of the Ingresses of a cluster on the host, without configuring a resolver by hand.
+
+minikube runs a DNS server in the background, which watches the Ingresses of the cluster, and configures the host to use it: with systemd-resolved 246 or later on Linux, and with the resolver on macOS. On Windows, or with --mode=hosts, the names are written to the hosts file, which can not resolve names within <profile>.test or wildcard hosts. Linux hosts without systemd-resolved 246 are not supported, other than by the hosts file.
+
+Configuring the host requires root privileges, which the DNS server does not have, so the configuration of the host is only updated by 'minikube dns enable': once Ingress hosts outside of <profile>.test are added, or with --mode=hosts, run it again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube dns [enable|disable|status]")
+	},
+}
+
+// dnsEnableCmd represents the dns enable command
+var dnsEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Resolve the names of a cluster on the host, until 'minikube dns disable'",
+	Long: `Resolve the names of a cluster on the host, until 'minikube dns disable'.
+
+Running it again while the names are resolved updates the configuration of the host, for Ingress hosts added since.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		if st, err := hostdns.LoadState(cname); err == nil && st != nil {
+			out.T(style.Running, "DNS is already enabled for {{.profile}} (pid {{.pid}}), updating the configuration of the host ...", out.V{"profile": cname, "pid": st.Pid})
+			if err := configureDNS(cname, co, st); err != nil {
+				exit.Error(reason.HostDNS, "Unable to configure the DNS of the host", err)
+			}
+			return
+		}
+
+		mode := dnsMode
+		if mode == "auto" {
+			detected, err := hostdns.DetectMode()
+			if err != nil {
+				exit.Message(reason.HostDNS, "Unable to resolve the names of the cluster on this host: {{.error}}", out.V{"error": err})
+			}
+			mode = detected
+		}
+		if _, err := hostdns.NewConfigurator(mode, cname, 0); err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+		if err := hostdns.Check(mode); err != nil {
+			exit.Message(reason.HostDNS, "Unable to resolve the names of the cluster on this host: {{.error}}", out.V{"error": err})
+		}
+
+		logPath := hostdns.LogPath(cname)
+		// The DNS server records its state once it runs
+		pid, err := startBackground([]string{"dns", "serve", "-p", cname, "--mode=" + mode}, nil, logPath, func(pid int) bool {
+			st, err := hostdns.LoadState(cname)
+			return err == nil && st != nil && st.Pid == pid
+		})
+		if err != nil {
+			exit.Error(reason.HostDNS, "Unable to start the DNS server", err)
+		}
+		st, err := hostdns.LoadState(cname)
+		if err != nil || st == nil {
+			exit.Error(reason.HostDNS, "Unable to read the state of the DNS server", err)
+		}
+		if err := configureDNS(cname, co, st); err != nil {
+			if err := hostdns.Cleanup(cname); err != nil {
+				klog.Warningf("unable to clean up: %v", err)
+			}
+			exit.Error(reason.HostDNS, "Unable to configure the DNS of the host", err)
+		}
+		out.T(style.Running, "Resolving {{.domain}} and the hosts of Ingresses with {{.mode}} (pid {{.pid}}), logging to {{.log}}", out.V{"domain": cname + ".test", "mode": mode, "pid": pid, "log": logPath})
+		out.T(style.Tip, "To stop resolving them, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cname, "dns disable")})
+	},
+}
+
+// dnsServeCmd represents the dns serve command, run in the background by 'minikube dns enable'
+var dnsServeCmd = &cobra.Command{
+	Use:    "serve",
+	Short:  "Resolve the names of a cluster on the host, in the foreground",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		serveDNS(ClusterFlagValue(), dnsMode)
+	},
+}
+
+// dnsDisableCmd represents the dns disable command
+var dnsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop resolving the names of a cluster on the host, removing the configuration of the host",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		out.T(style.Stopping, "Removing the DNS configuration of {{.profile}} ...", out.V{"profile": cname})
+		if err := hostdns.Cleanup(cname); err != nil {
+			exit.Error(reason.HostDNS, "Unable to remove the DNS configuration of the host", err)
+		}
+		out.T(style.Stopped, "Stopped resolving the names of {{.profile}} on the host", out.V{"profile": cname})
+	},
+}
+
+// dnsStatusCmd represents the dns status command
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the names of a cluster resolved on the host",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		st, err := hostdns.LoadState(cname)
+		if err != nil {
+			exit.Error(reason.HostDNS, "Unable to read the state of the DNS server", err)
+		}
+		if st == nil {
+			out.T(style.Stopped, "DNS is not enabled for {{.profile}}", out.V{"profile": cname})
+			return
+		}
+		out.T(style.Running, "Resolving the names of {{.profile}} with {{.mode}} (pid {{.pid}}, since {{.time}})", out.V{"profile": cname, "mode": st.Mode, "pid": st.Pid, "time": st.Started.Format(time.RFC3339)})
+
+		records := dnsRecords(cname, mustload.Running(cname))
+		hosts := records.Hosts()
+		var names []string
+		for n := range hosts {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "IP"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, n := range names {
+			name := n
+			if n == records.Domain && st.Mode != hostdns.ModeHosts {
+				name = n + ", *." + n
+			}
+			table.Append([]string{name, hosts[n].String()})
+		}
+		table.Render()
+
+		c, err := hostdns.NewConfigurator(st.Mode, cname, st.Port)
+		if err != nil {
+			exit.Error(reason.HostDNS, "Unable to read the state of the DNS server", err)
+		}
+		if !c.Configured(records) {
+			out.WarningT("The configuration of the host is out of date, so some of these names do not resolve. To update it, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cname, "dns enable")})
+		}
+	},
+}
+
+// dnsTarget returns the IP the names of a cluster resolve to on the host, and whether the Ingresses which have an
+// address of their own resolve to it
+func dnsTarget(co mustload.ClusterController) (net.IP, bool) {
+	if driver.NeedsPortForward(co.Config.Driver) {
+		// the ports of the cluster are only reachable through 'minikube tunnel'
+		return net.ParseIP("127.0.0.1"), false
+	}
+	return net.ParseIP(co.CP.Node.IP), true
+}
+
+// dnsRecords returns the names of a cluster resolved on the host, for its current Ingresses
+func dnsRecords(cname string, co mustload.ClusterController) *hostdns.Records {
+	ip, useStatus := dnsTarget(co)
+	records := hostdns.NewRecords(cname, ip)
+	client, err := kapi.Client(cname)
+	if err != nil {
+		exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
+	}
+	ings, err := client.NetworkingV1beta1().Ingresses("").List(meta.ListOptions{})
+	if err != nil {
+		exit.Error(reason.HostDNS, "Unable to list the Ingresses", err)
+	}
+	var items []*networking.Ingress
+	for i := range ings.Items {
+		items = append(items, &ings.Items[i])
+	}
+	records.SetHosts(hostdns.IngressHosts(items, ip, useStatus))
+	return records
+}
+
+// configureDNS configures the host to use the running DNS server of a cluster, for its current Ingresses. It runs in
+// the foreground, as configuring the host requires root privileges, which the DNS server does not have.
+func configureDNS(cname string, co mustload.ClusterController, st *hostdns.State) error {
+	c, err := hostdns.NewConfigurator(st.Mode, cname, st.Port)
+	if err != nil {
+		return err
+	}
+	records := dnsRecords(cname, co)
+	if c.Configured(records) {
+		return nil
+	}
+	cacheSudo("minikube needs sudo to configure the DNS of the host, which may ask for your password now.")
+	return c.Apply(records)
+}
+
+// serveDNS resolves the names of a cluster for the host until it is interrupted, following the changes of its
+// Ingresses. It runs without root privileges: 'minikube dns enable' configures the host, and 'minikube dns disable'
+// removes the configuration.
+func serveDNS(cname string, mode string) {
+	co := mustload.Running(cname)
+	// The DNS server outlives the terminal it was started from
+	signal.Ignore(syscall.SIGHUP)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+
+	ip, useStatus := dnsTarget(co)
+	records := hostdns.NewRecords(cname, ip)
+
+	port := 0
+	if mode != hostdns.ModeHosts {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			exit.Error(reason.HostDNS, "Unable to listen for DNS queries", err)
+		}
+		defer conn.Close()
+		port = conn.LocalAddr().(*net.UDPAddr).Port
+		go func() {
+			if err := hostdns.Serve(conn, records); err != nil {
+				klog.Infof("DNS server stopped: %v", err)
+			}
+		}()
+	}
+
+	c, err := hostdns.NewConfigurator(mode, cname, port)
+	if err != nil {
+		exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+	}
+	client, err := kapi.Client(cname)
+	if err != nil {
+		exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
+	}
+
+	st := hostdns.State{Pid: os.Getpid(), Started: time.Now(), Mode: mode, Port: port, IP: ip.String()}
+	if err := hostdns.SaveState(cname, st); err != nil {
+		exit.Error(reason.HostDNS, "Unable to save the state of the DNS server", err)
+	}
+	defer func() {
+		if err := hostdns.RemoveState(cname); err != nil {
+			klog.Warningf("unable to remove the state of the DNS server: %v", err)
+		}
+	}()
+
+	stop := make(chan struct{})
+	hostdns.WatchIngresses(client, ip, useStatus, stop, func(hosts map[string]net.IP) {
+		if !records.SetHosts(hosts) {
+			return
+		}
+		klog.Infof("Ingress hosts changed: %v", hosts)
+		if !c.Configured(records) {
+			out.WarningT("The configuration of the host is out of date for the Ingress hosts {{.hosts}}. To update it, run: '{{.command}}'", out.V{"hosts": hosts, "command": mustload.ExampleCmd(cname, "dns enable")})
+		}
+	})
+	out.T(style.Running, "Resolving {{.domain}} and the hosts of Ingresses to {{.ip}} with {{.mode}}", out.V{"domain": records.Domain, "ip": ip, "mode": mode})
+
+	<-sig
+	close(stop)
+	out.T(style.Stopped, "Stopped resolving the names of {{.profile}} on the host", out.V{"profile": cname})
+}
+
+func init() {
+	dnsEnableCmd.Flags().StringVar(&dnsMode, "mode", "auto", "How the host resolves the names: "+strings.Join(hostdns.Modes, ", ")+", or auto to detect it")
+	dnsServeCmd.Flags().StringVar(&dnsMode, "mode", hostdns.ModeHosts, "How the host resolves the names: "+strings.Join(hostdns.Modes, ", "))
+	dnsCmd.AddCommand(dnsEnableCmd)
+	dnsCmd.AddCommand(dnsServeCmd)
+	dnsCmd.AddCommand(dnsDisableCmd)
+	dnsCmd.AddCommand(dnsStatusCmd)
+}
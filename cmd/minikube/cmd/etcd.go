@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/blang/semver"
+	units "github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/etcd"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// etcdMinimumVersion is the oldest Kubernetes release whose etcd defaults to the v3 API in etcdctl
+var etcdMinimumVersion = semver.MustParse("1.17.0")
+
+// etcdCmd represents the etcd command
+var etcdCmd = &cobra.Command{
+	Use:   "etcd",
+	Short: "Back up, restore and inspect the etcd of a cluster",
+	Long: `Back up, restore and inspect the etcd of a running cluster.
+
+etcdctl is run within the etcd pod of each control plane, with the certificates of the cluster.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube etcd [backup|restore|status]")
+	},
+}
+
+// etcdBackupCmd represents the etcd backup command
+var etcdBackupCmd = &cobra.Command{
+	Use:     "backup FILE",
+	Short:   "Save a snapshot of etcd to a file on the host",
+	Example: "minikube etcd backup etcd.db",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide the file to save the snapshot to")
+		}
+		co, members := etcdMembers()
+		defer co.API.Close()
+
+		f, err := os.Create(args[0])
+		if err != nil {
+			exit.Error(reason.EtcdBackup, "Unable to create the snapshot file", err)
+		}
+		out.T(style.Caching, "Saving a snapshot of etcd on {{.name}} to {{.path}} ...", out.V{"name": members[0].Machine, "path": args[0]})
+		err = etcd.SaveSnapshot(members[0], f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			if rerr := os.Remove(args[0]); rerr != nil {
+				klog.Warningf("unable to remove %s: %v", args[0], rerr)
+			}
+			exit.Error(reason.EtcdBackup, "Failed to back up etcd", err)
+		}
+		out.T(style.Success, "Saved a snapshot of etcd to {{.path}}", out.V{"path": args[0]})
+	},
+}
+
+// etcdRestoreCmd represents the etcd restore command
+var etcdRestoreCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Replace the data of etcd with a snapshot from the host",
+	Long: `Replace the data of etcd on every control plane with a snapshot from the host, saved by 'minikube etcd backup' or 'etcdctl snapshot save'.
+Everything written to the cluster since the snapshot was taken is lost. The control plane is restarted.`,
+	Example: "minikube etcd restore etcd.db",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide the snapshot file to restore")
+		}
+		if _, err := os.Stat(args[0]); err != nil {
+			exit.Message(reason.HostPathMissing, "Unable to read the snapshot file {{.path}}: {{.error}}", out.V{"path": args[0], "error": err})
+		}
+		co, members := etcdMembers()
+		defer co.API.Close()
+
+		out.T(style.Resetting, "Restoring etcd of {{.cluster}} from {{.path}} ...", out.V{"cluster": co.Config.Name, "path": args[0]})
+		if err := etcd.RestoreSnapshot(members, args[0]); err != nil {
+			exit.Error(reason.EtcdRestore, "Failed to restore etcd", err)
+		}
+
+		out.T(style.Waiting, "Waiting for etcd to start ...")
+		healthy := func() error {
+			_, err := etcd.MemberStatus(members[0])
+			return err
+		}
+		if err := retry.Expo(healthy, time.Second, 3*time.Minute); err != nil {
+			exit.Error(reason.EtcdRestore, "etcd did not start after the restore", err)
+		}
+		out.T(style.Success, "Restored etcd of {{.cluster}} from {{.path}}", out.V{"cluster": co.Config.Name, "path": args[0]})
+	},
+}
+
+// etcdStatusCmd represents the etcd status command
+var etcdStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the etcd members of a cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		co, members := etcdMembers()
+		defer co.API.Close()
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Node", "Member ID", "Version", "DB Size", "Leader", "Raft Term", "Raft Index", "Revision"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, m := range members {
+			st, err := etcd.MemberStatus(m)
+			if err != nil {
+				exit.Error(reason.EtcdStatus, "Unable to get the status of etcd", err)
+			}
+			table.Append([]string{
+				m.Machine,
+				fmt.Sprintf("%x", st.Status.Header.MemberID),
+				st.Status.Version,
+				units.BytesSize(float64(st.Status.DBSize)),
+				strconv.FormatBool(st.IsLeader()),
+				strconv.FormatUint(st.Status.RaftTerm, 10),
+				strconv.FormatUint(st.Status.RaftIndex, 10),
+				strconv.FormatInt(st.Status.Header.Revision, 10),
+			})
+		}
+		table.Render()
+	},
+}
+
+// etcdMembers loads a running cluster and its etcd members, exiting if its etcd can not be managed
+func etcdMembers() (mustload.ClusterController, []etcd.Member) {
+	co := mustload.Running(ClusterFlagValue())
+
+	ver, err := util.ParseKubernetesVersion(co.Config.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		exit.Error(reason.InternalSemverParse, "Unable to parse the version of the cluster", err)
+	}
+	if ver.LT(etcdMinimumVersion) {
+		exit.Message(reason.KubernetesTooOld, "Managing etcd requires Kubernetes v{{.minimum}} or newer, but {{.cluster}} runs {{.version}}", out.V{"minimum": etcdMinimumVersion, "cluster": co.Config.Name, "version": co.Config.KubernetesConfig.KubernetesVersion})
+	}
+
+	members, err := etcd.Members(co.API, *co.Config)
+	if err != nil {
+		exit.Error(reason.GuestCpConfig, "Unable to find the etcd members", err)
+	}
+	return co, members
+}
+
+func init() {
+	etcdCmd.AddCommand(etcdBackupCmd)
+	etcdCmd.AddCommand(etcdRestoreCmd)
+	etcdCmd.AddCommand(etcdStatusCmd)
+}
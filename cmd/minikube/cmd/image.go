@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+const defaultImageListFormat = "{{.Image}}\n"
+
+var (
+	imageListFormat string
+	buildTag        string
+	buildFile       string
+	buildArgs       []string
+)
+
+// ImageListTemplate represents the image list template
+type ImageListTemplate struct {
+	Image string
+}
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage images",
+	Long:  "Load, list, remove, pull or build images in the container runtime of every node of the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube image [load|ls|rm|pull|build]")
+	},
+}
+
+// loadImageCmd represents the image load command
+var loadImageCmd = &cobra.Command{
+	Use:     "load IMAGE | ARCHIVE",
+	Short:   "Load an image into minikube",
+	Long:    "Load an image from the local image store or from an image archive into the container runtime of every node of the cluster",
+	Example: "minikube image load busybox:latest\nminikube image load ./busybox.tar",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image or an image archive to load")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.DoLoadImages(co.API, co.Config, args); err != nil {
+			exit.Error(reason.GuestImageLoad, "Failed to load image", err)
+		}
+		out.T(style.Success, "Loaded {{.images}}", out.V{"images": strings.Join(args, ", ")})
+	},
+}
+
+// listImageCmd represents the image ls command
+var listImageCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List images",
+	Long:    "List the images in the container runtime of every running node of the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		images, err := machine.ListImages(co.API, co.Config)
+		if err != nil {
+			exit.Error(reason.GuestImageList, "Failed to list images", err)
+		}
+		if err := imageList(images); err != nil {
+			exit.Error(reason.GuestImageList, "Failed to list images", err)
+		}
+	},
+}
+
+// removeImageCmd represents the image rm command
+var removeImageCmd = &cobra.Command{
+	Use:     "rm IMAGE [IMAGE...]",
+	Aliases: []string{"remove", "unload"},
+	Short:   "Remove one or more images",
+	Long:    "Remove one or more images from the container runtime of every running node of the cluster",
+	Example: "minikube image rm busybox:latest",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to remove")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.RemoveImages(co.API, co.Config, args); err != nil {
+			exit.Error(reason.GuestImageRemove, "Failed to remove image", err)
+		}
+		out.T(style.Deleted, "Removed {{.images}}", out.V{"images": strings.Join(args, ", ")})
+	},
+}
+
+// pullImageCmd represents the image pull command
+var pullImageCmd = &cobra.Command{
+	Use:     "pull IMAGE [IMAGE...]",
+	Short:   "Pull one or more images",
+	Long:    "Pull one or more images from a registry into the container runtime of every running node of the cluster",
+	Example: "minikube image pull busybox:latest",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to pull")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.PullImages(co.API, co.Config, args); err != nil {
+			exit.Error(reason.GuestImagePull, "Failed to pull image", err)
+		}
+		out.T(style.Pulling, "Pulled {{.images}}", out.V{"images": strings.Join(args, ", ")})
+	},
+}
+
+// buildImageCmd represents the image build command
+var buildImageCmd = &cobra.Command{
+	Use:     "build PATH",
+	Short:   "Build an image",
+	Long:    "Build an image from a local build context directory in the container runtime of every running node of the cluster",
+	Example: "minikube image build -t my-image:latest .",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide exactly one build context directory")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		opts := cruntime.BuildImageOptions{
+			Dockerfile: buildFile,
+			Tag:        buildTag,
+			BuildArgs:  buildArgs,
+		}
+		if err := machine.BuildImage(co.API, co.Config, args[0], opts); err != nil {
+			exit.Error(reason.GuestImageBuild, "Failed to build image", err)
+		}
+		out.T(style.Success, "Successfully built image from {{.path}}", out.V{"path": args[0]})
+	},
+}
+
+// imageList returns a formatted list of images found within the cluster
+func imageList(images []string) error {
+	tmpl, err := template.New("list").Parse(imageListFormat)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		if err := tmpl.Execute(os.Stdout, ImageListTemplate{image}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	listImageCmd.Flags().StringVar(&imageListFormat, "format", defaultImageListFormat,
+		`Go template format string for the image list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
+For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#ImageListTemplate`)
+	buildImageCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Tag to apply to the new image (optional)")
+	buildImageCmd.Flags().StringVarP(&buildFile, "file", "f", "", "Path to the Dockerfile, relative to the build context (default \"Dockerfile\")")
+	buildImageCmd.Flags().StringSliceVar(&buildArgs, "build-arg", nil, "Set build-time variables, in the KEY=VALUE format")
+
+	imageCmd.AddCommand(loadImageCmd)
+	imageCmd.AddCommand(listImageCmd)
+	imageCmd.AddCommand(removeImageCmd)
+	imageCmd.AddCommand(pullImageCmd)
+	imageCmd.AddCommand(buildImageCmd)
+}
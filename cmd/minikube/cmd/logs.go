@@ -17,18 +17,29 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/logs"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 )
 
 const (
@@ -44,6 +55,16 @@ var (
 	numberOfLines int
 	// showProblems only shows lines that match known issues
 	showProblems bool
+	// showAudit shows the audit log of the API server, filtered by auditFilter
+	showAudit   bool
+	auditFilter logs.AuditFilter
+	// logComponents, logsSince, logsGrep and logsOutput select and format the records of components
+	logComponents []string
+	logsSince     time.Duration
+	logsGrep      string
+	logsOutput    string
+	// logsBundle is the file to write a diagnostic bundle of every node to
+	logsBundle string
 )
 
 // logsCmd represents the logs command
@@ -52,6 +73,11 @@ var logsCmd = &cobra.Command{
 	Short: "Returns logs to debug a local Kubernetes cluster",
 	Long:  `Gets the logs of the running instance, used for debugging minikube, not user code.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if logsBundle != "" {
+			writeBundle()
+			return
+		}
+
 		co := mustload.Running(ClusterFlagValue())
 
 		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *co.Config, co.CP.Runner)
@@ -59,7 +85,16 @@ var logsCmd = &cobra.Command{
 			exit.Error(reason.InternalBootstrapper, "Error getting cluster bootstrapper", err)
 		}
 
-		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner})
+		if showAudit {
+			outputAudit(co.Config, co.CP.Runner)
+			return
+		}
+		if len(logComponents) > 0 || logsSince > 0 || logsGrep != "" || logsOutput != "text" {
+			outputRecords(co.API, co.Config)
+			return
+		}
+
+		cr, err := cruntime.ForCluster(co.Config.KubernetesConfig, co.CP.Runner)
 		if err != nil {
 			exit.Error(reason.InternalNewRuntime, "Unable to get runtime", err)
 		}
@@ -85,9 +120,102 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// writeBundle writes a diagnostic bundle of every node to --bundle. Unlike the other modes, it works with stopped nodes and a broken API server.
+func writeBundle() {
+	api, cc := mustload.Partial(ClusterFlagValue())
+	defer api.Close()
+
+	out.T(style.Copying, "Collecting the diagnostics of {{.cluster}} into {{.path}} ...", out.V{"cluster": cc.Name, "path": logsBundle})
+	f, err := os.Create(logsBundle)
+	if err != nil {
+		exit.Error(reason.HostLogBundle, "Unable to create the bundle", err)
+	}
+	err = node.DiagnosticBundle(api, cc, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		if rerr := os.Remove(logsBundle); rerr != nil {
+			klog.Warningf("unable to remove %s: %v", logsBundle, rerr)
+		}
+		exit.Error(reason.HostLogBundle, "Failed to write the bundle", err)
+	}
+	out.T(style.Success, "Wrote {{.path}}. Please attach it to the issue you file, after checking that it holds nothing you would rather not share.", out.V{"path": logsBundle})
+}
+
+// outputAudit shows or follows the audit log of the API server
+func outputAudit(cc *config.ClusterConfig, runner command.Runner) {
+	if cc.KubernetesConfig.AuditPolicy == "" {
+		exit.Message(reason.Usage, "Audit logging is not enabled. To enable it, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cc.Name, "start --audit-policy=metadata")})
+	}
+	if followLogs {
+		if err := logs.FollowAudit(runner, numberOfLines, auditFilter); err != nil {
+			exit.Error(reason.AuditLog, "Unable to follow the audit log", err)
+		}
+		return
+	}
+	if err := logs.OutputAudit(runner, numberOfLines, auditFilter); err != nil {
+		exit.Error(reason.AuditLog, "Unable to read the audit log", err)
+	}
+}
+
+// outputRecords shows the records of the components of every node, or of --node, merged in chronological order
+func outputRecords(api libmachine.API, cc *config.ClusterConfig) {
+	if logsOutput != "text" && logsOutput != "json" {
+		exit.Message(reason.Usage, "Sorry, please set the --output flag to one of the following valid options: [text,json]")
+	}
+	for _, c := range logComponents {
+		valid := false
+		for _, v := range logs.Components() {
+			if c == v {
+				valid = true
+			}
+		}
+		if !valid {
+			exit.Message(reason.Usage, "Invalid component {{.component}}. Valid components are: {{.components}}", out.V{"component": c, "components": strings.Join(logs.Components(), ", ")})
+		}
+	}
+	o := logs.RecordOptions{Components: logComponents, Lines: numberOfLines, Since: logsSince}
+	if logsGrep != "" {
+		re, err := regexp.Compile(logsGrep)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid --grep expression: {{.error}}", out.V{"error": err})
+		}
+		o.Grep = re
+	}
+
+	records, err := node.LogRecords(api, cc, nodeName, o)
+	for _, r := range records {
+		if logsOutput == "json" {
+			b, err := json.Marshal(r)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "Failed to marshal log record", err)
+			}
+			out.String("%s\n", b)
+			continue
+		}
+		out.String("%s\n", r)
+	}
+	if err != nil {
+		out.Ln("")
+		// Avoid exit.Error, since it outputs the issue URL
+		out.WarningT("{{.error}}", out.V{"error": err})
+		os.Exit(reason.ExSvcError)
+	}
+}
+
 func init() {
 	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
 	logsCmd.Flags().BoolVar(&showProblems, "problems", false, "Show only log entries which point to known problems")
 	logsCmd.Flags().IntVarP(&numberOfLines, "length", "n", 60, "Number of lines back to go within the log")
 	logsCmd.Flags().StringVar(&nodeName, "node", "", "The node to get logs from. Defaults to the primary control plane.")
+	logsCmd.Flags().StringSliceVar(&logComponents, "component", nil, fmt.Sprintf("Show the logs of these components of every node, parsed and merged in chronological order (%s)", strings.Join(logs.Components(), ", ")))
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Show the logs of the components of every node written within this duration, such as 10m, rather than the last lines")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Show only the logs of the components of every node whose message matches this regular expression")
+	logsCmd.Flags().StringVarP(&logsOutput, "output", "o", "text", "Format of the logs of the components of every node. Options include: [text,json]")
+	logsCmd.Flags().StringVar(&logsBundle, "bundle", "", "Write the journals, container logs, configs and events of every node, along with host info, to this gzipped tarball for bug reports. Secrets in the cluster config are redacted")
+	logsCmd.Flags().BoolVar(&showAudit, "audit", false, "Show the audit log of the API server, enabled by 'minikube start --audit-policy'")
+	logsCmd.Flags().StringVar(&auditFilter.User, "audit-user", "", "Show only the audit events of this user, with --audit")
+	logsCmd.Flags().StringVar(&auditFilter.Verb, "audit-verb", "", "Show only the audit events of this verb, such as get or delete, with --audit")
+	logsCmd.Flags().StringVar(&auditFilter.Resource, "audit-resource", "", "Show only the audit events of this resource, such as pods, pods/log or deployments.apps, with --audit")
 }
@@ -17,9 +17,26 @@ limitations under the License.
 package cmd
 
 import (
+	"time"
+
+	"github.com/docker/machine/libmachine"
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	// nodeForce stops or deletes a node even if it could not be drained
+	nodeForce bool
+	// nodeDrainTimeout is how long to wait for the pods of a node to be evicted
+	nodeDrainTimeout time.Duration
 )
 
 // nodeCmd represents the set of node subcommands
@@ -28,6 +45,37 @@ var nodeCmd = &cobra.Command{
 	Short: "Add, remove, or list additional nodes",
 	Long:  "Operations on nodes",
 	Run: func(cmd *cobra.Command, args []string) {
-		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|delete|list]")
+		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|restart|delete|list|fail|heal]")
 	},
 }
+
+// drainNode drains a node before it is stopped or deleted. The primary control plane is not drained, as its pods have
+// nowhere to go, and neither are nodes of a cluster whose API server is not running.
+func drainNode(api libmachine.API, cc *config.ClusterConfig, n config.Node) {
+	primary, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		exit.Error(reason.GuestCpConfig, "Error getting primary control plane", err)
+	}
+	if n.Name == primary.Name {
+		return
+	}
+	if !machine.IsRunning(api, driver.MachineName(*cc, n)) || !machine.IsRunning(api, driver.MachineName(*cc, primary)) {
+		klog.Infof("not draining %s, as the node or the control plane is not running", n.Name)
+		return
+	}
+
+	name := driver.MachineName(*cc, n)
+	out.T(style.Waiting, "Draining node {{.name}} ...", out.V{"name": name})
+	if err := node.Drain(*cc, n, nodeForce, nodeDrainTimeout); err != nil {
+		if !nodeForce {
+			exit.Error(reason.GuestNodeDrain, "Failed to drain node. To proceed regardless, use --force", err)
+		}
+		out.WarningT("Failed to drain node {{.name}}, proceeding as --force is set: {{.error}}", out.V{"name": name, "error": err})
+	}
+}
+
+// addDrainFlags adds the flags of commands which drain a node
+func addDrainFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&nodeForce, "force", false, "Proceed even if the node can not be drained, deleting the pods which are not managed by a controller")
+	cmd.Flags().DurationVar(&nodeDrainTimeout, "timeout", 5*time.Minute, "How long to wait for the pods of the node to be evicted")
+}
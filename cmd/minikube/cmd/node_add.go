@@ -17,8 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/clusterfile"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
@@ -27,11 +33,21 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
+	pkgutil "k8s.io/minikube/pkg/util"
 )
 
 var (
 	cp     bool
 	worker bool
+	// nodeSSH is how the ssh driver reaches the existing machine of the node
+	nodeSSH config.SSHConfig
+	// the resources, labels and taints of the node, which may differ from those of the other nodes
+	nodeCPUs     int
+	nodeMemory   string
+	nodeDiskSize string
+	nodeLabels   []string
+	nodeTaints   []string
+	nodePool     string
 )
 
 var nodeAddCmd = &cobra.Command{
@@ -43,7 +59,7 @@ var nodeAddCmd = &cobra.Command{
 		cc := co.Config
 
 		if driver.BareMetal(cc.Driver) {
-			out.FailureT("none driver does not support multi-node clusters")
+			exit.Message(reason.DrvUnsupportedMulti, "The none driver does not support multi-node clusters. To join existing machines into a cluster, use the ssh driver: https://minikube.sigs.k8s.io/docs/drivers/ssh/")
 		}
 
 		name := node.Name(len(cc.Nodes) + 1)
@@ -57,11 +73,18 @@ var nodeAddCmd = &cobra.Command{
 			ControlPlane:      cp,
 			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		}
+		if cp {
+			n.Port = cc.KubernetesConfig.NodePort
+		}
+		if driver.IsSSH(cc.Driver) {
+			n.SSH = nodeSSHConfig(cmd, cc)
+		}
+		nodePoolConfig(&n, cc.Driver)
 
 		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
 		if len(cc.Nodes) == 1 {
 			warnAboutMultiNode()
-			if viper.GetString(memory) == "" {
+			if viper.GetString(memory) == "" && n.Memory == 0 {
 				cc.Memory = 2200
 			}
 		}
@@ -81,11 +104,187 @@ var nodeAddCmd = &cobra.Command{
 	},
 }
 
+// nodeSSHConfig returns how the ssh driver reaches the existing machine of the node to add.
+// Unless given, the SSH user, key and port are those of the primary control plane.
+func nodeSSHConfig(cmd *cobra.Command, cc *config.ClusterConfig) config.SSHConfig {
+	primary, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		exit.Error(reason.GuestCpConfig, "Error getting primary control plane", err)
+	}
+	c := nodeSSH
+	if !cmd.Flags().Changed(sshUser) {
+		c.User = primary.SSH.User
+	}
+	if !cmd.Flags().Changed(sshKey) {
+		c.Key = primary.SSH.Key
+	}
+	if !cmd.Flags().Changed(sshPort) {
+		c.Port = primary.SSH.Port
+	}
+	for _, n := range cc.Nodes {
+		if n.SSH.IPAddress == c.IPAddress {
+			exit.Message(reason.Usage, "The machine {{.ip}} is already node {{.name}} of the cluster", out.V{"ip": c.IPAddress, "name": n.Name})
+		}
+	}
+	return sshNodeConfig(c.IPAddress, c.User, c.Key, c.Port)
+}
+
+// nodePoolConfig applies the resources, labels and taints of the node to add
+func nodePoolConfig(n *config.Node, drvName string) {
+	labels, err := parseNodeLabels(nodeLabels)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid --labels: {{.error}}", out.V{"error": err})
+	}
+	applyNodePool(n, drvName, clusterfile.NodePool{
+		Name:     nodePool,
+		CPUs:     nodeCPUs,
+		Memory:   nodeMemory,
+		DiskSize: nodeDiskSize,
+		Labels:   labels,
+		Taints:   nodeTaints,
+	})
+}
+
+// applyNodePool applies the resources, labels and taints of a node pool to a node
+func applyNodePool(n *config.Node, drvName string, np clusterfile.NodePool) {
+	if np.CPUs != 0 {
+		if np.CPUs < minimumCPUS {
+			exit.Message(reason.RsrcInsufficientCores, "Requested cpu count {{.requested_cpus}} is less than the minimum allowed of {{.minimum_cpus}}", out.V{"requested_cpus": np.CPUs, "minimum_cpus": minimumCPUS})
+		}
+		n.CPUs = np.CPUs
+	}
+	if np.Memory != "" {
+		mem, err := pkgutil.CalculateSizeInMB(np.Memory)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse memory '{{.memory}}': {{.error}}", out.V{"memory": np.Memory, "error": err})
+		}
+		validateRequestedMemorySize(mem, drvName)
+		n.Memory = mem
+	}
+	if np.DiskSize != "" {
+		disk, err := pkgutil.CalculateSizeInMB(np.DiskSize)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": np.DiskSize, "error": err})
+		}
+		if disk < minimumDiskSize {
+			exit.Message(reason.RsrcInsufficientStorage, "Requested disk size {{.requested_size}} is less than minimum of {{.minimum_size}}", out.V{"requested_size": disk, "minimum_size": minimumDiskSize})
+		}
+		n.DiskSize = disk
+	}
+
+	for k, v := range np.Labels {
+		if err := validateLabel(k, v); err != nil {
+			exit.Message(reason.Usage, "Invalid labels: {{.error}}", out.V{"error": err})
+		}
+	}
+	n.Labels = np.Labels
+	if err := validateTaints(np.Taints); err != nil {
+		exit.Message(reason.Usage, "Invalid taints: {{.error}}", out.V{"error": err})
+	}
+	n.Taints = np.Taints
+	if np.Name != "" {
+		if errs := validation.IsValidLabelValue(np.Name); len(errs) > 0 {
+			exit.Message(reason.Usage, "Invalid node pool {{.pool}}: {{.error}}", out.V{"pool": np.Name, "error": strings.Join(errs, ", ")})
+		}
+		n.Pool = np.Name
+	}
+}
+
+// poolNodes returns the worker nodes of node pools, named after the nodes the cluster already has
+func poolNodes(cc *config.ClusterConfig, existing int, pools []clusterfile.NodePool) []config.Node {
+	var nodes []config.Node
+	for _, np := range pools {
+		for i := 0; i < np.Count; i++ {
+			n := config.Node{
+				Name:              node.Name(existing + len(nodes) + 1),
+				Worker:            true,
+				KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+			}
+			applyNodePool(&n, cc.Driver, np)
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// parseNodeLabels parses labels given as key=value
+func parseNodeLabels(ss []string) (map[string]string, error) {
+	if len(ss) == 0 {
+		return nil, nil
+	}
+	labels := map[string]string{}
+	for _, s := range ss {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%q is not of the form key=value", s)
+		}
+		if err := validateLabel(kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels, nil
+}
+
+// validateTaints validates taints given as key[=value]:effect
+func validateTaints(ss []string) error {
+	for _, s := range ss {
+		i := strings.LastIndex(s, ":")
+		if i < 0 {
+			return fmt.Errorf("%q is not of the form key[=value]:effect", s)
+		}
+		switch effect := s[i+1:]; effect {
+		case "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return fmt.Errorf("%q has the invalid effect %q, expected NoSchedule, PreferNoSchedule or NoExecute", s, effect)
+		}
+		kv := strings.SplitN(s[:i], "=", 2)
+		v := ""
+		if len(kv) == 2 {
+			v = kv[1]
+		}
+		if err := validateLabel(kv[0], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLabel validates a label the kubelet registers its node with
+func validateLabel(k, v string) error {
+	if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+		return fmt.Errorf("invalid key %q: %s", k, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+		return fmt.Errorf("invalid value %q of %s: %s", v, k, strings.Join(errs, ", "))
+	}
+	// Kubelets may only set the labels of the kubernetes.io and k8s.io namespaces they are allowed to
+	if i := strings.Index(k, "/"); i > 0 {
+		ns := k[:i]
+		restricted := ns == "kubernetes.io" || ns == "k8s.io" || strings.HasSuffix(ns, ".kubernetes.io") || strings.HasSuffix(ns, ".k8s.io")
+		allowed := ns == "node.kubernetes.io" || ns == "kubelet.kubernetes.io" || strings.HasSuffix(ns, ".node.kubernetes.io") || strings.HasSuffix(ns, ".kubelet.kubernetes.io")
+		if restricted && !allowed {
+			return fmt.Errorf("%s is in the %s namespace, which kubelets may not register labels in", k, ns)
+		}
+	}
+	return nil
+}
+
 func init() {
 	// TODO(https://github.com/kubernetes/minikube/issues/7366): We should figure out which minikube start flags to actually import
 	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker.")
 	nodeAddCmd.Flags().BoolVar(&worker, "worker", true, "If true, the added node will be marked for work. Defaults to true.")
 	nodeAddCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	nodeAddCmd.Flags().StringVar(&nodeSSH.IPAddress, sshIPAddress, "", "IP address of the existing machine to adopt as the node (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSH.User, sshUser, "root", "SSH user of the existing machine. Defaults to that of the control plane (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSH.Key, sshKey, "", "SSH private key of the existing machine. Defaults to that of the control plane (ssh driver only)")
+	nodeAddCmd.Flags().IntVar(&nodeSSH.Port, sshPort, 22, "SSH port of the existing machine. Defaults to that of the control plane (ssh driver only)")
+	nodeAddCmd.Flags().IntVar(&nodeCPUs, cpus, 0, "Number of CPUs allocated to the node. Defaults to those of the cluster")
+	nodeAddCmd.Flags().StringVar(&nodeMemory, memory, "", "Amount of RAM allocated to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to that of the cluster")
+	nodeAddCmd.Flags().StringVar(&nodeDiskSize, humanReadableDiskSize, "", "Disk size allocated to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to that of the cluster")
+	nodeAddCmd.Flags().StringSliceVar(&nodeLabels, "labels", nil, "Labels the node registers with, as key=value pairs")
+	nodeAddCmd.Flags().StringSliceVar(&nodeTaints, "taints", nil, "Taints the node registers with, as key[=value]:effect, where effect is NoSchedule, PreferNoSchedule or NoExecute")
+	nodeAddCmd.Flags().StringVar(&nodePool, "pool", "", "The node pool of the node, which it is labelled with as "+bsutil.PoolLabel)
 
 	nodeCmd.AddCommand(nodeAddCmd)
 }
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseNodeLabels(t *testing.T) {
+	var tests = []struct {
+		labels   []string
+		expected map[string]string
+		valid    bool
+	}{
+		{nil, nil, true},
+		{[]string{"zone=b", "example.com/gpu=true"}, map[string]string{"zone": "b", "example.com/gpu": "true"}, true},
+		{[]string{"node.kubernetes.io/instance-type=large"}, map[string]string{"node.kubernetes.io/instance-type": "large"}, true},
+		{[]string{"empty="}, map[string]string{"empty": ""}, true},
+		{[]string{"zone"}, nil, false},
+		{[]string{"-zone=b"}, nil, false},
+		{[]string{"zone=not valid"}, nil, false},
+		{[]string{"node-role.kubernetes.io/gpu=true"}, nil, false},
+		{[]string{"minikube.k8s.io/pool=gpu"}, nil, false},
+	}
+	for _, tc := range tests {
+		got, err := parseNodeLabels(tc.labels)
+		if (err == nil) != tc.valid {
+			t.Errorf("parseNodeLabels(%v) = %v, expected valid: %v", tc.labels, err, tc.valid)
+			continue
+		}
+		if diff := cmp.Diff(tc.expected, got); diff != "" {
+			t.Errorf("parseNodeLabels(%v) diff (-want +got):\n%s", tc.labels, diff)
+		}
+	}
+}
+
+func TestValidateTaints(t *testing.T) {
+	var tests = []struct {
+		taint string
+		valid bool
+	}{
+		{"dedicated=gpu:NoSchedule", true},
+		{"spot:PreferNoSchedule", true},
+		{"example.com/maintenance=true:NoExecute", true},
+		{"dedicated=gpu", false},
+		{"dedicated=gpu:Never", false},
+		{":NoSchedule", false},
+		{"dedicated=not valid:NoSchedule", false},
+	}
+	for _, tc := range tests {
+		if err := validateTaints([]string{tc.taint}); (err == nil) != tc.valid {
+			t.Errorf("validateTaints(%q) = %v, expected valid: %v", tc.taint, err, tc.valid)
+		}
+	}
+}
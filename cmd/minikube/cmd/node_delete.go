@@ -30,7 +30,7 @@ import (
 var nodeDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Deletes a node from a cluster.",
-	Long:  "Deletes a node from a cluster.",
+	Long:  "Deletes a node from a cluster. The node is drained first, so that its pods are rescheduled on the other nodes.",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			exit.Message(reason.Usage, "Usage: minikube node delete [name]")
@@ -38,9 +38,14 @@ var nodeDeleteCmd = &cobra.Command{
 		name := args[0]
 
 		co := mustload.Healthy(ClusterFlagValue())
-		out.T(style.DeletingHost, "Deleting node {{.name}} from cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
+		n, _, err := node.Retrieve(*co.Config, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+		drainNode(co.API, co.Config, *n)
 
-		n, err := node.Delete(*co.Config, name)
+		out.T(style.DeletingHost, "Deleting node {{.name}} from cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
+		n, err = node.Delete(*co.Config, name, nodeForce)
 		if err != nil {
 			exit.Error(reason.GuestNodeDelete, "deleting node", err)
 		}
@@ -55,5 +60,6 @@ var nodeDeleteCmd = &cobra.Command{
 }
 
 func init() {
+	addDrainFlags(nodeDeleteCmd)
 	nodeCmd.AddCommand(nodeDeleteCmd)
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var failMode string
+
+var nodeFailCmd = &cobra.Command{
+	Use:   "fail",
+	Short: "Simulates the failure of a node.",
+	Long: `Simulates the failure of a node, for resilience testing, until it is healed with 'minikube node heal'. The modes of failure are:
+
+  kill:             kill the machine of the node, as if it crashed
+  freeze:           pause the machine of the node, as if it hung
+  partition:        drop the traffic between the node and the other nodes
+  latency=<delay>:  delay the traffic of the node, such as latency=200ms
+  loss=<share>:     drop a share below 100% of the traffic of the node, such as loss=5%
+
+The partition, latency and loss modes are not supported by the none and ssh drivers, as they would change the network of the host.`,
+	Example: "minikube node fail m02 --mode=partition",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node fail [name] --mode=kill|freeze|partition|latency=<delay>|loss=<share>")
+		}
+		f, err := node.ParseFailure(failMode)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid --mode: {{.error}}", out.V{"error": err})
+		}
+
+		name := args[0]
+		api, cc := mustload.Partial(ClusterFlagValue())
+		n, _, err := node.Retrieve(*cc, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+
+		machineName := driver.MachineName(*cc, *n)
+		if err := node.Fail(api, cc, n, f); err != nil {
+			exit.Error(reason.GuestNodeFail, "Failed to simulate the failure of the node", err)
+		}
+		out.T(style.Warning, "Node {{.name}} fails with {{.mode}}", out.V{"name": machineName, "mode": f})
+		out.T(style.Tip, "To heal it, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cc.Name, "node heal "+name)})
+	},
+}
+
+func init() {
+	nodeFailCmd.Flags().StringVar(&failMode, "mode", node.FailKill, "How the node fails: kill, freeze, partition, latency=<delay> or loss=<share>")
+	nodeCmd.AddCommand(nodeFailCmd)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var nodeHealCmd = &cobra.Command{
+	Use:   "heal",
+	Short: "Heals a node from a simulated failure.",
+	Long:  "Heals a node from the failure simulated by 'minikube node fail'. Nodes which were killed are started again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node heal [name]")
+		}
+
+		name := args[0]
+		api, cc := mustload.Partial(ClusterFlagValue())
+		n, _, err := node.Retrieve(*cc, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+		if n.Failure == "" {
+			out.T(style.Check, "{{.name}} has no simulated failure", out.V{"name": name})
+			return
+		}
+
+		machineName := driver.MachineName(*cc, *n)
+		failure := n.Failure
+		if err := node.Heal(api, cc, n); err != nil {
+			exit.Error(reason.GuestNodeFail, "Failed to heal the node", err)
+		}
+		if failure == node.FailKill {
+			startNode(cmd, cc, n)
+		}
+		out.T(style.Happy, "Healed node {{.name}} from {{.mode}}", out.V{"name": machineName, "mode": failure})
+	},
+}
+
+func init() {
+	nodeHealCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	nodeCmd.AddCommand(nodeHealCmd)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var nodeRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restarts a node in a cluster.",
+	Long:  "Restarts a node in a cluster: the node is drained, stopped, started again and uncordoned, as during node maintenance.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node restart [name]")
+		}
+
+		name := args[0]
+		api, cc := mustload.Partial(ClusterFlagValue())
+
+		n, _, err := node.Retrieve(*cc, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+		primary, err := config.PrimaryControlPlane(cc)
+		if err != nil {
+			exit.Error(reason.GuestCpConfig, "Error getting primary control plane", err)
+		}
+		if n.Name == primary.Name {
+			exit.Message(reason.Usage, "The primary control plane can not be restarted on its own. To restart the cluster, run: 'minikube stop' and 'minikube start'")
+		}
+
+		machineName := driver.MachineName(*cc, *n)
+		drainNode(api, cc, *n)
+
+		out.T(style.Restarting, "Restarting node {{.name}} ...", out.V{"name": machineName})
+		if err := machine.StopHost(api, machineName); err != nil {
+			exit.Error(reason.GuestStopTimeout, "Failed to stop node", err)
+		}
+		startNode(cmd, cc, n)
+		out.T(style.Happy, "Successfully restarted node {{.name}}!", out.V{"name": machineName})
+	},
+}
+
+func init() {
+	addDrainFlags(nodeRestartCmd)
+	nodeRestartCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	nodeCmd.AddCommand(nodeRestartCmd)
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -54,31 +56,41 @@ var nodeStartCmd = &cobra.Command{
 			os.Exit(0)
 		}
 
-		r, p, m, h, err := node.Provision(cc, n, false, viper.GetBool(deleteOnFailure))
-		if err != nil {
-			exit.Error(reason.GuestNodeProvision, "provisioning host for node", err)
-		}
+		startNode(cmd, cc, n)
+		out.T(style.Happy, "Successfully started node {{.name}}!", out.V{"name": machineName})
+	},
+}
 
-		s := node.Starter{
-			Runner:         r,
-			PreExists:      p,
-			MachineAPI:     m,
-			Host:           h,
-			Cfg:            cc,
-			Node:           n,
-			ExistingAddons: nil,
-		}
+// startNode starts a stopped node, and allows pods to be scheduled on it again
+func startNode(cmd *cobra.Command, cc *config.ClusterConfig, n *config.Node) {
+	r, p, m, h, err := node.Provision(cc, n, false, viper.GetBool(deleteOnFailure))
+	if err != nil {
+		exit.Error(reason.GuestNodeProvision, "provisioning host for node", err)
+	}
+
+	s := node.Starter{
+		Runner:         r,
+		PreExists:      p,
+		MachineAPI:     m,
+		Host:           h,
+		Cfg:            cc,
+		Node:           n,
+		ExistingAddons: nil,
+	}
 
-		_, err = node.Start(s, false)
+	_, err = node.Start(s, false)
+	if err != nil {
+		_, err := maybeDeleteAndRetry(cmd, *cc, *n, nil, err)
 		if err != nil {
-			_, err := maybeDeleteAndRetry(cmd, *cc, *n, nil, err)
-			if err != nil {
-				node.ExitIfFatal(err)
-				exit.Error(reason.GuestNodeStart, "failed to start node", err)
-			}
+			node.ExitIfFatal(err)
+			exit.Error(reason.GuestNodeStart, "failed to start node", err)
 		}
-		out.T(style.Happy, "Successfully started node {{.name}}!", out.V{"name": machineName})
-	},
+	}
+
+	// The node is cordoned if it was drained when stopped
+	if err := node.Uncordon(*cc, *n); err != nil {
+		klog.Warningf("unable to uncordon %s: %v", n.Name, err)
+	}
 }
 
 func init() {
@@ -31,7 +31,7 @@ import (
 var nodeStopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stops a node in a cluster.",
-	Long:  "Stops a node in a cluster.",
+	Long:  "Stops a node in a cluster. The node is drained first, so that its pods are rescheduled on the other nodes, and stays cordoned until it is started again.",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			exit.Message(reason.Usage, "Usage: minikube node stop [name]")
@@ -46,6 +46,7 @@ var nodeStopCmd = &cobra.Command{
 		}
 
 		machineName := driver.MachineName(*cc, *n)
+		drainNode(api, cc, *n)
 
 		err = machine.StopHost(api, machineName)
 		if err != nil {
@@ -56,5 +57,6 @@ var nodeStopCmd = &cobra.Command{
 }
 
 func init() {
+	addDrainFlags(nodeStopCmd)
 	nodeCmd.AddCommand(nodeStopCmd)
 }
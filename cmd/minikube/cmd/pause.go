@@ -82,7 +82,7 @@ func runPause(cmd *cobra.Command, args []string) {
 			exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
 		}
 
-		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: r})
+		cr, err := cruntime.ForCluster(co.Config.KubernetesConfig, r)
 		if err != nil {
 			exit.Error(reason.InternalNewRuntime, "Failed runtime", err)
 		}
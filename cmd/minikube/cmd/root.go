@@ -162,6 +162,8 @@ func init() {
 				dockerEnvCmd,
 				podmanEnvCmd,
 				cacheCmd,
+				imageCmd,
+				bundleCmd,
 			},
 		},
 		{
@@ -170,6 +172,10 @@ func init() {
 				configCmd.AddonsCmd,
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
+				snapshotCmd,
+				upgradeCmd,
+				etcdCmd,
+				certsCmd,
 				updateContextCmd,
 			},
 		},
@@ -178,6 +184,7 @@ func init() {
 			Commands: []*cobra.Command{
 				serviceCmd,
 				tunnelCmd,
+				dnsCmd,
 			},
 		},
 		{
@@ -185,8 +192,10 @@ func init() {
 			Commands: []*cobra.Command{
 				mountCmd,
 				sshCmd,
+				cpCmd,
 				kubectlCmd,
 				nodeCmd,
+				daemonCmd,
 			},
 		},
 		{
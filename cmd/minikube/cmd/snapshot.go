@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore snapshots of a stopped cluster",
+	Long: `Save and restore named snapshots of a stopped cluster, so that it can be brought back to a known state without rebuilding it.
+
+Snapshots are supported by the docker, podman and kvm2 drivers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube snapshot [save|restore|list]")
+	},
+}
+
+// saveSnapshotCmd represents the snapshot save command
+var saveSnapshotCmd = &cobra.Command{
+	Use:     "save NAME",
+	Short:   "Save a snapshot of a stopped cluster",
+	Example: "minikube stop && minikube snapshot save seeded",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a name for the snapshot")
+		}
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+
+		out.T(style.Caching, "Saving snapshot {{.name}} of {{.cluster}} ...", out.V{"name": args[0], "cluster": cc.Name})
+		s, err := snapshot.Save(api, cc, args[0])
+		if err != nil {
+			exit.Error(reason.GuestSnapshotSave, "Failed to save snapshot", err)
+		}
+		out.T(style.Success, "Saved snapshot {{.name}} of {{.cluster}}", out.V{"name": s.Name, "cluster": cc.Name})
+	},
+}
+
+// restoreSnapshotCmd represents the snapshot restore command
+var restoreSnapshotCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restore a stopped cluster to a snapshot",
+	Long: `Restore a stopped cluster to a snapshot, including its configuration.
+Nodes added after the snapshot was taken are deleted. The cluster resumes from the snapshot on the next 'minikube start'.`,
+	Example: "minikube stop && minikube snapshot restore seeded && minikube start",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide the name of the snapshot to restore")
+		}
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+
+		out.T(style.Resetting, "Restoring {{.cluster}} to snapshot {{.name}} ...", out.V{"name": args[0], "cluster": cc.Name})
+		s, err := snapshot.Restore(api, cc, args[0])
+		if err != nil {
+			exit.Error(reason.GuestSnapshotRestore, "Failed to restore snapshot", err)
+		}
+		out.T(style.Success, "Restored {{.cluster}} to snapshot {{.name}} taken {{.created}}", out.V{"name": s.Name, "cluster": cc.Name, "created": s.Created.Format(time.RFC1123)})
+		out.T(style.Tip, "To resume the cluster, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cc.Name, "start")})
+	},
+}
+
+// listSnapshotCmd represents the snapshot list command
+var listSnapshotCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the snapshots of a cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		snapshots, err := snapshot.List(cname)
+		if err != nil {
+			exit.Error(reason.GuestSnapshotList, "Failed to list snapshots", err)
+		}
+		if len(snapshots) == 0 {
+			out.T(style.Empty, "There are no snapshots of {{.cluster}}. You can save one using 'minikube snapshot save'.", out.V{"cluster": cname})
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Driver", "Version", "Nodes", "Created"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, s := range snapshots {
+			table.Append([]string{s.Name, s.Driver, s.KubernetesVersion, strconv.Itoa(len(s.Machines)), s.Created.Format(time.RFC1123)})
+		}
+		table.Render()
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(saveSnapshotCmd)
+	snapshotCmd.AddCommand(restoreSnapshotCmd)
+	snapshotCmd.AddCommand(listSnapshotCmd)
+}
@@ -42,6 +42,7 @@ import (
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/auth"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -59,6 +60,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/minikube/style"
 
 	"k8s.io/minikube/pkg/minikube/registry"
@@ -126,6 +128,10 @@ func platform() string {
 
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
+	if path := viper.GetString(clusterFile); path != "" {
+		applyClusterFile(cmd, path)
+	}
+
 	register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
 
 	out.SetJSON(viper.GetString(startOutput) == "json")
@@ -169,6 +175,19 @@ func runStart(cmd *cobra.Command, args []string) {
 
 	if existing != nil {
 		upgradeExistingConfig(existing)
+
+		// Starting a cluster cancels any stop that was scheduled for it
+		if existing.ScheduledStop != nil && schedule.Remaining(existing) == 0 {
+			// the stop already happened: treat it like a manual one
+			if err := schedule.Expire(existing); err != nil {
+				klog.Warningf("unable to clear scheduled stop: %v", err)
+			}
+		} else if existing.ScheduledStop != nil {
+			if err := schedule.KillExisting([]string{existing.Name}); err != nil {
+				klog.Warningf("unable to cancel scheduled stop: %v", err)
+			}
+			existing.ScheduledStop = nil
+		}
 	}
 
 	validateSpecifiedDriver(existing)
@@ -278,6 +297,9 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 
 	validateFlags(cmd, driverName)
 	validateUser(driverName)
+	if driver.IsSSH(driverName) && existing == nil && (viper.GetInt(nodes) > 1 || viper.GetBool(haMode)) {
+		exit.Message(reason.Usage, "The ssh driver adopts one existing machine per node. To add machines to the cluster, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(ClusterFlagValue(), "node add --ssh-ip-address=<ip>")})
+	}
 	if driverName == oci.Docker {
 		validateDockerStorageDriver(driverName)
 	}
@@ -347,14 +369,17 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 	}
 
 	numNodes := viper.GetInt(nodes)
-	if existing != nil {
+	var pools []config.Node
+	if existing == nil {
+		pools = poolNodes(starter.Cfg, numNodes, clusterFilePools)
+	} else {
 		if numNodes > 1 {
 			// We ignore the --nodes parameter if we're restarting an existing cluster
 			out.WarningT(`The cluster {{.cluster}} already exists which means the --nodes parameter will be ignored. Use "minikube node add" to add nodes to an existing cluster.`, out.V{"cluster": existing.Name})
 		}
 		numNodes = len(existing.Nodes)
 	}
-	if numNodes > 1 {
+	if numNodes > 1 || len(pools) > 0 {
 		if driver.BareMetal(starter.Cfg.Driver) {
 			exit.Message(reason.DrvUnsupportedMulti, "The none driver is not compatible with multi-node clusters.")
 		} else {
@@ -363,29 +388,34 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 				out.Ln("")
 				warnAboutMultiNode()
 
+				var others []config.Node
 				for i := 1; i < numNodes; i++ {
 					nodeName := node.Name(i + 1)
 					n := config.Node{
 						Name:              nodeName,
 						Worker:            true,
-						ControlPlane:      false,
+						ControlPlane:      starter.Cfg.HA && i < haControlPlanes,
 						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
 					}
-					out.Ln("") // extra newline for clarity on the command line
-					err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
-					if err != nil {
-						return nil, errors.Wrap(err, "adding node")
+					if n.ControlPlane {
+						n.Port = starter.Node.Port
 					}
+					others = append(others, n)
+				}
+				others = append(others, pools...)
+				if err := node.AddNodes(starter.Cfg, others, viper.GetInt(parallelNodes), viper.GetBool(deleteOnFailure)); err != nil {
+					return nil, errors.Wrap(err, "adding nodes")
 				}
 			} else {
+				var others []config.Node
 				for _, n := range existing.Nodes {
-					if !n.ControlPlane {
-						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
-						if err != nil {
-							return nil, errors.Wrap(err, "adding node")
-						}
+					if n.Name != starter.Node.Name {
+						others = append(others, n)
 					}
 				}
+				if err := node.RestartNodes(starter.Cfg, others, viper.GetInt(parallelNodes), viper.GetBool(deleteOnFailure)); err != nil {
+					return nil, errors.Wrap(err, "restarting nodes")
+				}
 			}
 		}
 	}
@@ -438,6 +468,12 @@ func showKubectlInfo(kcs *kubeconfig.Settings, k8sVersion string, machineName st
 		} else {
 			out.T(style.Ready, `Done! kubectl is now configured to use "{{.name}}" by default`, out.V{"name": machineName})
 		}
+		if kcs.AuthUser != "" {
+			out.T(style.Tip, "To connect to this cluster with its authentication mode, use:  --context={{.name}}", out.V{"name": kcs.AuthUser})
+			if kcs.AuthInfo.Exec != nil {
+				out.T(style.Tip, "Logging in with OpenID Connect requires kubelogin: https://github.com/int128/kubelogin")
+			}
+		}
 	}()
 
 	path, err := exec.LookPath("kubectl")
@@ -485,9 +521,14 @@ func maybeDeleteAndRetry(cmd *cobra.Command, existing config.ClusterConfig, n co
 
 		// Re-generate the cluster config, just in case the failure was related to an old config format
 		cc := updateExistingConfigFromFlags(cmd, &existing)
+		cp, err := config.PrimaryControlPlane(&cc)
+		if err != nil {
+			return nil, err
+		}
 		var kubeconfig *kubeconfig.Settings
 		for _, n := range cc.Nodes {
-			r, p, m, h, err := node.Provision(&cc, &n, n.ControlPlane, false)
+			primary := n.Name == cp.Name
+			r, p, m, h, err := node.Provision(&cc, &n, primary, false)
 			s := node.Starter{
 				Runner:         r,
 				PreExists:      p,
@@ -502,8 +543,8 @@ func maybeDeleteAndRetry(cmd *cobra.Command, existing config.ClusterConfig, n co
 				return nil, err
 			}
 
-			k, err := node.Start(s, n.ControlPlane)
-			if n.ControlPlane {
+			k, err := node.Start(s, primary)
+			if primary {
 				kubeconfig = k
 			}
 			if err != nil {
@@ -556,7 +597,7 @@ func selectDriver(existing *config.ClusterConfig) (registry.DriverState, []regis
 	}
 
 	// Default to looking at the new driver parameter
-	if d := viper.GetString("driver"); d != "" {
+	if d := viper.GetString(driverFlag); d != "" {
 		if vmd := viper.GetString("vm-driver"); vmd != "" {
 			// Output a warning
 			warning := `Both driver={{.driver}} and vm-driver={{.vmd}} have been set.
@@ -644,7 +685,7 @@ func validateSpecifiedDriver(existing *config.ClusterConfig) {
 	}
 
 	var requested string
-	if d := viper.GetString("driver"); d != "" {
+	if d := viper.GetString(driverFlag); d != "" {
 		requested = d
 	} else if d := viper.GetString("vm-driver"); d != "" {
 		requested = d
@@ -974,7 +1015,7 @@ func validateCPUCount(drvName string) {
 	} else if drvName == oci.Docker && runtime.GOOS == "windows" {
 		exitIfNotForced(reason.RsrcInsufficientWindowsDockerCores, "Docker Desktop has less than 2 CPUs configured, but Kubernetes requires at least 2 to be available")
 	} else {
-		exitIfNotForced(reason.RsrcInsufficientCores, "{{.driver_name}} has less than 2 CPUs available, but Kubernetes requires at least 2 to be available", out.V{"driver_name": driver.FullName(viper.GetString("driver"))})
+		exitIfNotForced(reason.RsrcInsufficientCores, "{{.driver_name}} has less than 2 CPUs available, but Kubernetes requires at least 2 to be available", out.V{"driver_name": driver.FullName(viper.GetString(driverFlag))})
 	}
 }
 
@@ -1032,6 +1073,10 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		if !validRuntime {
 			exit.Message(reason.Usage, `Invalid Container Runtime: "{{.runtime}}". Valid runtimes are: {{.validOptions}}`, out.V{"runtime": runtime, "validOptions": strings.Join(cruntime.ValidRuntimes(), ", ")})
 		}
+
+		if runtime == "cri" && viper.GetString(criSocket) == "" {
+			exit.Message(reason.Usage, "The cri container runtime requires the socket it is served on, for example --cri-socket=/run/containerd/containerd.sock")
+		}
 	}
 
 	if driver.BareMetal(drvName) {
@@ -1074,6 +1119,50 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		}
 	}
 
+	if viper.GetBool(haMode) {
+		if driver.BareMetal(drvName) {
+			exit.Message(reason.DrvUnsupportedMulti, "The none driver is not compatible with highly available clusters.")
+		}
+		if driver.NeedsPortForward(drvName) {
+			out.WarningT("The host can not reach the virtual IP of the {{.driver}} driver, so kubectl talks to the first control plane node and loses the cluster while that node is down", out.V{"driver": drvName})
+		}
+		// Joining control planes requires 'kubeadm init phase upload-certs'
+		version, _ := util.ParseKubernetesVersion(getKubernetesVersion(nil))
+		if version.LT(semver.MustParse("1.15.0")) {
+			exit.Message(reason.Usage, "Sorry, highly available clusters require Kubernetes v1.15.0 or newer, got {{.k8sVersion}}", out.V{"k8sVersion": version.String()})
+		}
+		if viper.GetInt(nodes) < haControlPlanes {
+			viper.Set(nodes, haControlPlanes)
+		}
+	}
+
+	if cmd.Flags().Changed(runtimeClasses) {
+		version, _ := util.ParseKubernetesVersion(getKubernetesVersion(nil))
+		if version.LT(semver.MustParse("1.14.0")) {
+			exit.Message(reason.Usage, "Sorry, runtime classes require Kubernetes v1.14.0 or newer, got {{.k8sVersion}}", out.V{"k8sVersion": version.String()})
+		}
+		getRuntimeClasses()
+	}
+
+	if cmd.Flags().Changed(caCert) || cmd.Flags().Changed(caKey) {
+		if viper.GetString(caCert) == "" || viper.GetString(caKey) == "" {
+			exit.Message(reason.Usage, "Please specify both the CA certificate and its key with --ca-cert and --ca-key")
+		}
+		if err := util.ValidateCA(viper.GetString(caCert), viper.GetString(caKey)); err != nil {
+			exit.Message(reason.Usage, "Invalid CA: {{.error}}", out.V{"error": err})
+		}
+	}
+
+	if p := viper.GetString(auditPolicy); p != "" && !bsutil.IsAuditPreset(p) {
+		if _, err := os.Stat(p); err != nil {
+			exit.Message(reason.Usage, "The audit policy {{.policy}} is neither a file nor one of the presets {{.presets}}", out.V{"policy": p, "presets": strings.Join(bsutil.AuditPresets(), ", ")})
+		}
+	}
+
+	if cmd.Flags().Changed(authMode) {
+		validateAuthFlags()
+	}
+
 	if s := viper.GetString(startOutput); s != "text" && s != "json" {
 		exit.Message(reason.Usage, "Sorry, please set the --output flag to one of the following valid options: [text,json]")
 	}
@@ -1081,6 +1170,44 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 	validateRegistryMirror()
 }
 
+// validateAuthFlags validates the flags of --auth-mode
+func validateAuthFlags() {
+	mode := viper.GetString(authMode)
+	valid := false
+	for _, m := range auth.Modes() {
+		if mode == m {
+			valid = true
+		}
+	}
+	if !valid {
+		exit.Message(reason.Usage, "Invalid authentication mode {{.mode}}. Valid modes are: {{.modes}}", out.V{"mode": mode, "modes": strings.Join(auth.Modes(), ", ")})
+	}
+
+	for _, flag := range []string{oidcCAFile, authTokenFile, authWebhookConfig} {
+		if p := viper.GetString(flag); p != "" {
+			if _, err := os.Stat(p); err != nil {
+				exit.Message(reason.HostPathMissing, "Unable to read --{{.flag}}: {{.error}}", out.V{"flag": flag, "error": err})
+			}
+		}
+	}
+
+	switch mode {
+	case auth.ModeOIDC:
+		issuer := viper.GetString(oidcIssuerURL)
+		if issuer == "" {
+			out.T(style.Notice, "No --oidc-issuer-url was given, so the {{.addon}} addon will be enabled as the OpenID Connect issuer", out.V{"addon": auth.DexAddon})
+			return
+		}
+		if u, err := url.Parse(issuer); err != nil || u.Scheme != "https" {
+			exit.Message(reason.Usage, "The OpenID Connect issuer {{.url}} must be an https URL", out.V{"url": issuer})
+		}
+	case auth.ModeWebhook:
+		if viper.GetString(authWebhookConfig) == "" {
+			exit.Message(reason.Usage, "Please specify the kubeconfig file of the authentication webhook with --auth-webhook-config")
+		}
+	}
+}
+
 // This function validates if the --registry-mirror
 // args match the format of http://localhost
 func validateRegistryMirror() {
@@ -1126,6 +1253,9 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 		ControlPlane:      true,
 		Worker:            true,
 	}
+	if driver.IsSSH(cc.Driver) {
+		cp.SSH = sshNodeConfig(viper.GetString(sshIPAddress), viper.GetString(sshUser), viper.GetString(sshKey), viper.GetInt(sshPort))
+	}
 	cc.Nodes = []config.Node{cp}
 	return cc, cp, nil
 }
@@ -1207,6 +1337,9 @@ func validateKubernetesVersion(old *config.ClusterConfig) {
 			out.V{"prefix": version.VersionPrefix, "new": nvs, "old": ovs, "profile": profileArg, "suggestedName": suggestedName})
 
 	}
+	if nvs.GT(ovs) {
+		out.T(style.Tip, "To upgrade a running cluster one node at a time, with an etcd backup to roll back to, use: '{{.command}}'", out.V{"command": mustload.ExampleCmd(old.Name, "upgrade --kubernetes-version="+version.VersionPrefix+nvs.String())})
+	}
 	if defaultVersion.GT(nvs) {
 		out.T(style.New, "Kubernetes {{.new}} is now available. If you would like to upgrade, specify: --kubernetes-version={{.prefix}}{{.new}}", out.V{"prefix": version.VersionPrefix, "new": defaultVersion})
 	}
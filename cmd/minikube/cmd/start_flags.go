@@ -18,6 +18,9 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,8 +30,10 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/auth"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/clusterfile"
 	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -55,6 +60,7 @@ const (
 	hostOnlyCIDR            = "host-only-cidr"
 	containerRuntime        = "container-runtime"
 	criSocket               = "cri-socket"
+	runtimeClasses          = "runtime-class"
 	networkPlugin           = "network-plugin"
 	enableDefaultCNI        = "enable-default-cni"
 	cniFlag                 = "cni"
@@ -73,6 +79,19 @@ const (
 	featureGates            = "feature-gates"
 	apiServerName           = "apiserver-name"
 	apiServerPort           = "apiserver-port"
+	caCert                  = "ca-cert"
+	caKey                   = "ca-key"
+	authMode                = "auth-mode"
+	oidcIssuerURL           = "oidc-issuer-url"
+	oidcClientID            = "oidc-client-id"
+	oidcClientSecret        = "oidc-client-secret"
+	oidcUsernameClaim       = "oidc-username-claim"
+	oidcGroupsClaim         = "oidc-groups-claim"
+	oidcCAFile              = "oidc-ca-file"
+	authTokenFile           = "auth-token-file"
+	authWebhookConfig       = "auth-webhook-config"
+	authToken               = "auth-token"
+	auditPolicy             = "audit-policy"
 	dnsDomain               = "dns-domain"
 	serviceCIDR             = "service-cluster-ip-range"
 	imageRepository         = "image-repository"
@@ -82,6 +101,10 @@ const (
 	cacheImages             = "cache-images"
 	uuid                    = "uuid"
 	vpnkitSock              = "hyperkit-vpnkit-sock"
+	sshIPAddress            = "ssh-ip-address"
+	sshUser                 = "ssh-user"
+	sshKey                  = "ssh-key"
+	sshPort                 = "ssh-port"
 	vsockPorts              = "hyperkit-vsock-ports"
 	embedCerts              = "embed-certs"
 	noVTXCheck              = "no-vtx-check"
@@ -98,16 +121,27 @@ const (
 	minRecommendedMem       = 1907 // 2GB In MiB: Warn at no lower than existing configurations
 	minimumCPUS             = 2
 	minimumDiskSize         = 2000
+	haControlPlanes         = 3
 	autoUpdate              = "auto-update-drivers"
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	nodes                   = "nodes"
+	parallelNodes           = "parallel-nodes"
+	haMode                  = "ha"
 	preload                 = "preload"
 	deleteOnFailure         = "delete-on-failure"
 	forceSystemd            = "force-systemd"
 	kicBaseImage            = "base-image"
 	startOutput             = "output"
 	ports                   = "ports"
+	clusterFile             = "config"
+	driverFlag              = "driver"
+	addonsFlag              = "addons"
+	extraConfig             = "extra-config"
+	apiServerNamesFlag      = "apiserver-names"
+	apiServerIPsFlag        = "apiserver-ips"
+	registryMirrorFlag      = "registry-mirror"
+	insecureRegistryFlag    = "insecure-registry"
 )
 
 // initMinikubeFlags includes commandline flags for minikube.
@@ -133,8 +167,9 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(containerRuntime, "docker", fmt.Sprintf("The container runtime to be used (%s).", strings.Join(cruntime.ValidRuntimes(), ", ")))
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube.")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":/minikube-host", "The argument to pass the minikube mount command on start.")
-	startCmd.Flags().StringArrayVar(&config.AddonList, "addons", nil, "Enable addons. see `minikube addons list` for a list of valid addon names.")
-	startCmd.Flags().String(criSocket, "", "The cri socket path to be used.")
+	startCmd.Flags().StringArrayVar(&config.AddonList, addonsFlag, nil, "Enable addons. see `minikube addons list` for a list of valid addon names.")
+	startCmd.Flags().String(criSocket, "", "The cri socket path to be used. Required by --container-runtime=cri, which drives the runtime serving this socket with crictl.")
+	startCmd.Flags().StringSlice(runtimeClasses, nil, "Register a RuntimeClass that pods can select with runtimeClassName, in the NAME=HANDLER format. The handler must be configured in the container runtime.")
 	startCmd.Flags().String(networkPlugin, "", "Kubelet network plug-in to use (default: auto)")
 	startCmd.Flags().Bool(enableDefaultCNI, false, "DEPRECATED: Replaced by --cni=bridge")
 	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, cilium, flannel, kindnet, or path to a CNI manifest (default: auto)")
@@ -144,16 +179,19 @@ func initMinikubeFlags() {
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().Int(parallelNodes, 4, "The number of worker nodes to provision at the same time.")
+	startCmd.Flags().Bool(haMode, false, "Create a highly available cluster with 3 control plane nodes and stacked etcd, with the API servers fronted by a virtual IP. (experimental)")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use sytemd as cgroup manager. Currently available for docker and crio. Defaults to false.")
 	startCmd.Flags().StringP(startOutput, "o", "text", "Format to print stdout in. Options include: [text,json]")
+	startCmd.Flags().String(clusterFile, "", fmt.Sprintf("Path to a cluster definition file in YAML or JSON format (apiVersion: %s, kind: %s), as written by 'minikube profile export'. Flags override values from the file.", clusterfile.APIVersion, clusterfile.Kind))
 }
 
 // initKubernetesFlags inits the commandline flags for Kubernetes related options
 func initKubernetesFlags() {
 	startCmd.Flags().String(kubernetesVersion, "", fmt.Sprintf("The Kubernetes version that the minikube VM will use (ex: v1.2.3, 'stable' for %s, 'latest' for %s). Defaults to 'stable'.", constants.DefaultKubernetesVersion, constants.NewestKubernetesVersion))
-	startCmd.Flags().Var(&config.ExtraOptions, "extra-config",
+	startCmd.Flags().Var(&config.ExtraOptions, extraConfig,
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
 		Valid components are: kubelet, kubeadm, apiserver, controller-manager, etcd, proxy, scheduler
@@ -162,13 +200,26 @@ func initKubernetesFlags() {
 	startCmd.Flags().String(dnsDomain, constants.ClusterDNSDomain, "The cluster dns domain name used in the Kubernetes cluster")
 	startCmd.Flags().Int(apiServerPort, constants.APIServerPort, "The apiserver listening port")
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The authoritative apiserver hostname for apiserver certificates and connectivity. This can be used if you want to make the apiserver available from outside the machine")
-	startCmd.Flags().StringArrayVar(&apiServerNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
-	startCmd.Flags().IPSliceVar(&apiServerIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().StringArrayVar(&apiServerNames, apiServerNamesFlag, nil, "A set of apiserver names which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().IPSliceVar(&apiServerIPs, apiServerIPsFlag, nil, "A set of apiserver IP Addresses which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().String(caCert, "", "A CA certificate to sign the certificates of the cluster with, instead of the minikube CA. Requires --ca-key")
+	startCmd.Flags().String(caKey, "", "The private key of --ca-cert. It is copied to the control plane nodes, which sign certificates with it")
+	startCmd.Flags().String(authMode, "", fmt.Sprintf("An authentication mode of the API server besides client certificates, added to the kubeconfig as the <profile>-<mode> context (%s)", strings.Join(auth.Modes(), ", ")))
+	startCmd.Flags().String(oidcIssuerURL, "", "The https URL of the OpenID Connect issuer of --auth-mode=oidc. If unset, the dex addon is enabled and used as the issuer")
+	startCmd.Flags().String(oidcClientID, "minikube", "The OpenID Connect client ID of the cluster, which ID tokens must be issued for, with --auth-mode=oidc")
+	startCmd.Flags().String(oidcClientSecret, "", "The OpenID Connect client secret used to log in by the kubeconfig user, with --auth-mode=oidc")
+	startCmd.Flags().String(oidcUsernameClaim, "email", "The claim of ID tokens used as the user name, with --auth-mode=oidc")
+	startCmd.Flags().String(oidcGroupsClaim, "groups", "The claim of ID tokens used as the groups of the user, with --auth-mode=oidc")
+	startCmd.Flags().String(oidcCAFile, "", "The CA certificate which signed the serving certificate of the OpenID Connect issuer, with --auth-mode=oidc")
+	startCmd.Flags().String(authTokenFile, "", "A static token file, as accepted by the --token-auth-file flag of the API server, with --auth-mode=token. If unset, a token is generated")
+	startCmd.Flags().String(authWebhookConfig, "", "A kubeconfig file of the token authentication webhook, with --auth-mode=webhook")
+	startCmd.Flags().String(auditPolicy, "", fmt.Sprintf("Enable the audit log of the API server, with an audit policy file or one of the presets (%s). Show it with 'minikube logs --audit'", strings.Join(bsutil.AuditPresets(), ", ")))
+	startCmd.Flags().String(authToken, "", "The bearer token of the kubeconfig user, with --auth-mode=token or --auth-mode=webhook")
 }
 
 // initDriverFlags inits the commandline flags for vm drivers
 func initDriverFlags() {
-	startCmd.Flags().String("driver", "", fmt.Sprintf("Driver is one of: %v (defaults to auto-detect)", driver.DisplaySupportedDrivers()))
+	startCmd.Flags().String(driverFlag, "", fmt.Sprintf("Driver is one of: %v (defaults to auto-detect)", driver.DisplaySupportedDrivers()))
 	startCmd.Flags().String("vm-driver", "", "DEPRECATED, use `driver` instead.")
 	startCmd.Flags().Bool(disableDriverMounts, false, "Disables the filesystem mounts provided by the hypervisors")
 	startCmd.Flags().Bool("vm", false, "Filter to use only VM Drivers")
@@ -201,12 +252,18 @@ func initDriverFlags() {
 
 	// docker & podman
 	startCmd.Flags().StringSlice(ports, []string{}, "List of ports that should be exposed (docker and podman driver only)")
+
+	// ssh
+	startCmd.Flags().String(sshIPAddress, "", "IP address of the existing machine to adopt as the control plane (ssh driver only)")
+	startCmd.Flags().String(sshUser, "root", "SSH user of the existing machine, which must be able to run sudo without a password (ssh driver only)")
+	startCmd.Flags().String(sshKey, "", "SSH private key of the existing machine. Defaults to the first of ~/.ssh/id_rsa, ~/.ssh/id_ecdsa and ~/.ssh/id_ed25519 which exists (ssh driver only)")
+	startCmd.Flags().Int(sshPort, 22, "SSH port of the existing machine (ssh driver only)")
 }
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
 func initNetworkingFlags() {
-	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
-	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
+	startCmd.Flags().StringSliceVar(&insecureRegistry, insecureRegistryFlag, nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
+	startCmd.Flags().StringSliceVar(&registryMirror, registryMirrorFlag, nil, "Registry mirrors to pass to the Docker daemon")
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
 	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
@@ -316,16 +373,22 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			NatNicType:              viper.GetString(natNicType),
 			StartHostTimeout:        viper.GetDuration(waitTimeout),
 			ExposedPorts:            viper.GetStringSlice(ports),
+			HA:                      viper.GetBool(haMode),
 			KubernetesConfig: config.KubernetesConfig{
 				KubernetesVersion:      k8sVersion,
 				ClusterName:            ClusterFlagValue(),
 				APIServerName:          viper.GetString(apiServerName),
 				APIServerNames:         apiServerNames,
 				APIServerIPs:           apiServerIPs,
+				CACertPath:             absPath(viper.GetString(caCert)),
+				CAKeyPath:              absPath(viper.GetString(caKey)),
+				Auth:                   authConfig(cmd, config.AuthConfig{}),
+				AuditPolicy:            auditPolicyPath(),
 				DNSDomain:              viper.GetString(dnsDomain),
 				FeatureGates:           viper.GetString(featureGates),
 				ContainerRuntime:       viper.GetString(containerRuntime),
 				CRISocket:              viper.GetString(criSocket),
+				RuntimeClasses:         getRuntimeClasses(),
 				NetworkPlugin:          viper.GetString(networkPlugin),
 				ServiceCIDR:            viper.GetString(serviceCIDR),
 				ImageRepository:        repository,
@@ -370,6 +433,17 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 	return createNode(cc, kubeNodeName, existing)
 }
 
+// sshNodeConfig returns how the ssh driver reaches the existing machine of a node
+func sshNodeConfig(ip string, user string, key string, port int) config.SSHConfig {
+	if ip == "" {
+		exit.Message(reason.Usage, "The ssh driver requires the IP address of the existing machine to adopt: --{{.flag}}", out.V{"flag": sshIPAddress})
+	}
+	if net.ParseIP(ip) == nil {
+		exit.Message(reason.Usage, "Invalid IP address {{.ip}} of the existing machine to adopt", out.V{"ip": ip})
+	}
+	return config.SSHConfig{IPAddress: ip, User: user, Key: absPath(key), Port: port}
+}
+
 // upgradeExistingConfig upgrades legacy configuration files
 func upgradeExistingConfig(cc *config.ClusterConfig) {
 	if cc == nil {
@@ -460,6 +534,10 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		}
 	}
 
+	if cmd.Flags().Changed(haMode) && viper.GetBool(haMode) != existing.HA {
+		out.WarningT("You cannot change high availability for an existing minikube cluster. Please first delete the cluster.")
+	}
+
 	if cmd.Flags().Changed(vpnkitSock) {
 		cc.HyperkitVpnKitSock = viper.GetString(vpnkitSock)
 	}
@@ -544,14 +622,28 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.KubernetesConfig.APIServerName = viper.GetString(apiServerName)
 	}
 
-	if cmd.Flags().Changed("apiserver-names") {
-		cc.KubernetesConfig.APIServerNames = viper.GetStringSlice("apiserver-names")
+	if cmd.Flags().Changed(apiServerNamesFlag) {
+		cc.KubernetesConfig.APIServerNames = viper.GetStringSlice(apiServerNamesFlag)
 	}
 
 	if cmd.Flags().Changed(apiServerPort) {
 		cc.KubernetesConfig.NodePort = viper.GetInt(apiServerPort)
 	}
 
+	if cmd.Flags().Changed(caCert) && absPath(viper.GetString(caCert)) != existing.KubernetesConfig.CACertPath {
+		exit.Message(reason.Usage, "The CA of an existing cluster can not be changed. To sign the cluster with another CA, delete it with 'minikube delete -p {{.profile}}' first", out.V{"profile": existing.Name})
+	}
+
+	if cmd.Flags().Changed(caKey) {
+		cc.KubernetesConfig.CAKeyPath = absPath(viper.GetString(caKey))
+	}
+
+	cc.KubernetesConfig.Auth = authConfig(cmd, existing.KubernetesConfig.Auth)
+
+	if cmd.Flags().Changed(auditPolicy) {
+		cc.KubernetesConfig.AuditPolicy = auditPolicyPath()
+	}
+
 	if cmd.Flags().Changed(vsockPorts) {
 		cc.ExposedPorts = viper.GetStringSlice(ports)
 	}
@@ -580,6 +672,10 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.KubernetesConfig.CRISocket = viper.GetString(criSocket)
 	}
 
+	if cmd.Flags().Changed(runtimeClasses) {
+		cc.KubernetesConfig.RuntimeClasses = getRuntimeClasses()
+	}
+
 	if cmd.Flags().Changed(networkPlugin) {
 		cc.KubernetesConfig.NetworkPlugin = viper.GetString(networkPlugin)
 	}
@@ -663,3 +759,176 @@ func interpretWaitFlag(cmd cobra.Command) map[string]bool {
 	klog.Infof("Waiting for components: %+v", waitComponents)
 	return waitComponents
 }
+
+// getRuntimeClasses returns the runtime classes requested by the --runtime-class flag
+func getRuntimeClasses() map[string]string {
+	classes, err := cruntime.ParseRuntimeClasses(viper.GetStringSlice(runtimeClasses))
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid --runtime-class: {{.error}}", out.V{"error": err})
+	}
+	if len(classes) == 0 {
+		return nil
+	}
+	return classes
+}
+
+// authConfig returns the authentication mode configured by flags. Flags which were not given keep the values of an existing cluster.
+func authConfig(cmd *cobra.Command, existing config.AuthConfig) config.AuthConfig {
+	a := existing
+	isNew := existing.Mode == ""
+	set := func(flag string, value *string, path bool) {
+		if !isNew && !cmd.Flags().Changed(flag) {
+			return
+		}
+		*value = viper.GetString(flag)
+		if path {
+			*value = absPath(*value)
+		}
+	}
+	set(authMode, &a.Mode, false)
+	set(oidcIssuerURL, &a.OIDCIssuerURL, false)
+	set(oidcClientID, &a.OIDCClientID, false)
+	set(oidcClientSecret, &a.OIDCClientSecret, false)
+	set(oidcUsernameClaim, &a.OIDCUsernameClaim, false)
+	set(oidcGroupsClaim, &a.OIDCGroupsClaim, false)
+	set(oidcCAFile, &a.OIDCCAFile, true)
+	set(authTokenFile, &a.TokenFile, true)
+	set(authWebhookConfig, &a.WebhookConfigFile, true)
+	set(authToken, &a.Token, false)
+
+	if a.Mode == "" {
+		return config.AuthConfig{}
+	}
+	if cmd.Flags().Changed(oidcIssuerURL) {
+		a.LocalDex = false
+	}
+	return a
+}
+
+// auditPolicyPath returns the audit policy preset of --audit-policy, or the absolute path of its policy file
+func auditPolicyPath() string {
+	p := viper.GetString(auditPolicy)
+	if bsutil.IsAuditPreset(p) {
+		return p
+	}
+	return absPath(p)
+}
+
+// absPath returns the absolute path of a file given as a flag, so that it does not depend on the working directory of later commands
+func absPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid path {{.path}}: {{.error}}", out.V{"path": p, "error": err})
+	}
+	return abs
+}
+
+// clusterFilePools are the node pools of the cluster definition file, which are added when the cluster is created
+var clusterFilePools []clusterfile.NodePool
+
+// fileFlag is a flag set from a cluster definition file
+type fileFlag struct {
+	name   string
+	values []string
+}
+
+// applyClusterFile sets the flags which were not given on the command line from a cluster definition file
+func applyClusterFile(cmd *cobra.Command, path string) {
+	cf, err := clusterfile.Read(path)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid cluster definition {{.path}}: {{.error}}", out.V{"path": path, "error": err})
+	}
+
+	s := cf.Spec
+	flags := []fileFlag{
+		{config.ProfileName, nonEmpty(cf.Metadata.Name)},
+		{driverFlag, nonEmpty(s.Driver)},
+		{kubernetesVersion, nonEmpty(s.KubernetesVersion)},
+		{containerRuntime, nonEmpty(s.ContainerRuntime)},
+		{criSocket, nonEmpty(s.CRISocket)},
+		{runtimeClasses, s.RuntimeClassStrings()},
+		{cpus, nonZero(s.CPUs)},
+		{memory, nonEmpty(s.Memory)},
+		{humanReadableDiskSize, nonEmpty(s.DiskSize)},
+		{nodes, nonZero(s.Nodes)},
+		{haMode, isTrue(s.HA)},
+		{cniFlag, nonEmpty(s.CNI)},
+		{apiServerName, nonEmpty(s.APIServerName)},
+		{apiServerNamesFlag, s.APIServerNames},
+		{apiServerIPsFlag, s.APIServerIPs},
+		{apiServerPort, nonZero(s.APIServerPort)},
+		{dnsDomain, nonEmpty(s.DNSDomain)},
+		{serviceCIDR, nonEmpty(s.ServiceCIDR)},
+		{imageRepository, nonEmpty(s.ImageRepository)},
+		{registryMirrorFlag, s.RegistryMirrors},
+		{insecureRegistryFlag, s.InsecureRegistries},
+		{featureGates, nonEmpty(s.FeatureGatesString())},
+		{extraConfig, s.ExtraConfigStrings()},
+		{addonsFlag, s.Addons},
+	}
+	flags = append(flags, fileFlag{caCert, nonEmpty(s.CACert)}, fileFlag{caKey, nonEmpty(s.CAKey)}, fileFlag{auditPolicy, nonEmpty(s.AuditPolicy)})
+	if a := s.Auth; a != nil {
+		flags = append(flags,
+			fileFlag{authMode, nonEmpty(a.Mode)},
+			fileFlag{oidcIssuerURL, nonEmpty(a.OIDCIssuerURL)},
+			fileFlag{oidcClientID, nonEmpty(a.OIDCClientID)},
+			fileFlag{oidcClientSecret, nonEmpty(a.OIDCClientSecret)},
+			fileFlag{oidcUsernameClaim, nonEmpty(a.OIDCUsernameClaim)},
+			fileFlag{oidcGroupsClaim, nonEmpty(a.OIDCGroupsClaim)},
+			fileFlag{oidcCAFile, nonEmpty(a.OIDCCAFile)},
+			fileFlag{authTokenFile, nonEmpty(a.TokenFile)},
+			fileFlag{authWebhookConfig, nonEmpty(a.WebhookConfig)},
+			fileFlag{authToken, nonEmpty(a.Token)},
+		)
+	}
+	clusterFilePools = s.NodePools
+	if len(s.Mounts) > 0 {
+		m := s.Mounts[0]
+		flags = append(flags, fileFlag{createMount, isTrue(true)}, fileFlag{mountString, []string{m.HostPath + ":" + m.GuestPath}})
+	}
+
+	for _, f := range flags {
+		if len(f.values) == 0 {
+			continue
+		}
+		if f.values[0] == clusterfile.Redacted && !cmd.Flags().Changed(f.name) {
+			exit.Message(reason.Usage, "The cluster definition {{.path}} does not store secrets, supply --{{.flag}} again", out.V{"path": path, "flag": f.name})
+		}
+		if cmd.Flags().Changed(f.name) {
+			klog.Infof("--%s overrides the value from %s", f.name, path)
+			continue
+		}
+		for _, value := range f.values {
+			if err := cmd.Flags().Set(f.name, value); err != nil {
+				exit.Message(reason.Usage, "Invalid cluster definition {{.path}}: {{.flag}}: {{.error}}", out.V{"path": path, "flag": f.name, "error": err})
+			}
+		}
+	}
+}
+
+// nonEmpty returns the values of a string flag, unless it is unset
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// nonZero returns the values of a numeric flag, unless it is unset
+func nonZero(value int) []string {
+	if value == 0 {
+		return nil
+	}
+	return []string{strconv.Itoa(value)}
+}
+
+// isTrue returns the values of a boolean flag, unless it is unset
+func isTrue(value bool) []string {
+	if !value {
+		return nil
+	}
+	return []string{"true"}
+}
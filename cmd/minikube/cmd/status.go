@@ -34,6 +34,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -46,13 +47,16 @@ import (
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/version"
 )
 
 var (
-	statusFormat string
-	output       string
-	layout       string
+	statusFormat  string
+	output        string
+	layout        string
+	watchStatus   bool
+	watchInterval time.Duration
 )
 
 const (
@@ -133,6 +137,9 @@ type Status struct {
 	APIServer  string
 	Kubeconfig string
 	Worker     bool
+	TimeToStop string `json:",omitempty"`
+	// Detail describes why the node is degraded, such as its disk usage or the pressure on it
+	Detail string `json:",omitempty"`
 }
 
 // ClusterState holds a cluster state representation
@@ -140,6 +147,7 @@ type ClusterState struct {
 	BaseState
 
 	BinaryVersion string
+	TimeToStop    string `json:",omitempty"`
 	Components    map[string]BaseState
 	Nodes         []NodeState
 }
@@ -160,7 +168,7 @@ type BaseState struct {
 	// Name is a human-readable name for the status code
 	StatusName string
 	// StatusDetail is long human-readable string describing why this particular status code was chosen
-	StatusDetail string `json:",omitempty"` // Not yet implemented
+	StatusDetail string `json:",omitempty"`
 
 	// Step is which workflow step the object is at.
 	Step string `json:",omitempty"`
@@ -178,12 +186,21 @@ host: {{.Host}}
 kubelet: {{.Kubelet}}
 apiserver: {{.APIServer}}
 kubeconfig: {{.Kubeconfig}}
+{{- if .TimeToStop }}
+timeToStop: {{.TimeToStop}}
+{{- end }}
+{{- if .Detail }}
+detail: {{.Detail}}
+{{- end }}
 
 `
 	workerStatusFormat = `{{.Name}}
 type: Worker
 host: {{.Host}}
 kubelet: {{.Kubelet}}
+{{- if .Detail }}
+detail: {{.Detail}}
+{{- end }}
 
 `
 )
@@ -203,6 +220,19 @@ var statusCmd = &cobra.Command{
 		cname := ClusterFlagValue()
 		api, cc := mustload.Partial(cname)
 
+		if watchStatus {
+			if nodeName != "" || statusFormat != defaultStatusFormat {
+				exit.Message(reason.Usage, "Cannot use --watch with the --node or --format options")
+			}
+			if o := strings.ToLower(output); o != "text" && o != "json" {
+				exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'text', 'json'", output))
+			}
+			if err := watchClusterStatus(api, cname, watchInterval, os.Stdout); err != nil {
+				exit.Error(reason.InternalStatusJSON, "status watch failure", err)
+			}
+			return
+		}
+
 		var statuses []*Status
 
 		if nodeName != "" || statusFormat != defaultStatusFormat && len(cc.Nodes) > 1 {
@@ -217,21 +247,10 @@ var statusCmd = &cobra.Command{
 			}
 			statuses = append(statuses, st)
 		} else {
-			for _, n := range cc.Nodes {
-				machineName := driver.MachineName(*cc, n)
-				klog.Infof("checking status of %s ...", machineName)
-				st, err := nodeStatus(api, *cc, n)
-				klog.Infof("%s status: %+v", machineName, st)
-
-				if err != nil {
-					klog.Errorf("status error: %v", err)
-				}
-				if st.Host == Nonexistent {
-					klog.Errorf("The %q host does not exist!", machineName)
-				}
-				statuses = append(statuses, st)
-			}
+			statuses = clusterStatuses(api, *cc)
 		}
+		expireScheduledStop(cc, statuses)
+		nodeConditions(cname, statuses)
 
 		switch strings.ToLower(output) {
 		case "text":
@@ -259,6 +278,74 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// clusterStatuses looks up the status of every node of a cluster
+func clusterStatuses(api libmachine.API, cc config.ClusterConfig) []*Status {
+	var statuses []*Status
+	for _, n := range cc.Nodes {
+		machineName := driver.MachineName(cc, n)
+		klog.Infof("checking status of %s ...", machineName)
+		st, err := nodeStatus(api, cc, n)
+		klog.Infof("%s status: %+v", machineName, st)
+
+		if err != nil {
+			klog.Errorf("status error: %v", err)
+		}
+		if st.Host == Nonexistent {
+			klog.Errorf("The %q host does not exist!", machineName)
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// nodeConditions adds the unwanted conditions of nodes, such as memory or disk pressure, to their status details
+func nodeConditions(cname string, statuses []*Status) {
+	running := false
+	for _, st := range statuses {
+		if st.APIServer == state.Running.String() && st.Kubeconfig == Configured {
+			running = true
+		}
+	}
+	if !running {
+		return
+	}
+
+	cs, err := kapi.Client(cname)
+	if err != nil {
+		klog.Warningf("unable to get client: %v", err)
+		return
+	}
+	conds, err := kverify.NodeConditions(cs)
+	if err != nil {
+		klog.Warningf("unable to get node conditions: %v", err)
+		return
+	}
+	for _, st := range statuses {
+		for _, c := range conds[st.Name] {
+			d := fmt.Sprintf("%s: %s", c.Type, c.Message)
+			if st.Detail != "" {
+				d = st.Detail + "; " + d
+			}
+			st.Detail = d
+		}
+	}
+}
+
+// expireScheduledStop clears the scheduled stop of a cluster which it has already stopped
+func expireScheduledStop(cc *config.ClusterConfig, statuses []*Status) {
+	if cc.ScheduledStop == nil {
+		return
+	}
+	for _, st := range statuses {
+		if st.Host != state.Stopped.String() {
+			return
+		}
+	}
+	if err := schedule.Expire(cc); err != nil {
+		klog.Warningf("unable to clear scheduled stop for %s: %v", cc.Name, err)
+	}
+}
+
 // exitCode calcluates the appropriate exit code given a set of status messages
 func exitCode(statuses []*Status) int {
 	c := 0
@@ -322,6 +409,8 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 	if !controlPlane {
 		st.Kubeconfig = Irrelevant
 		st.APIServer = Irrelevant
+	} else if left := schedule.Remaining(&cc); left > 0 {
+		st.TimeToStop = left.String()
 	}
 
 	host, err := machine.LoadHost(api, name)
@@ -343,6 +432,9 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 	}
 	if p >= 99 {
 		st.Host = codeNames[InsufficientStorage]
+		st.Detail = codeDetails[InsufficientStorage]
+	} else if p >= 85 {
+		st.Detail = fmt.Sprintf("/var is %d%% full", p)
 	}
 
 	stk := kverify.ServiceStatus(cr, "kubelet")
@@ -353,18 +445,24 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		return st, nil
 	}
 
-	hostname, _, port, err := driver.ControlPlaneEndpoint(&cc, &n, host.DriverName)
+	// The kubeconfig points at the virtual IP of highly available clusters, rather than at this node
+	kcHostname, _, kcPort, err := driver.APIServerEndpoint(&cc, &n, host.DriverName)
 	if err != nil {
 		klog.Errorf("forwarded endpoint: %v", err)
 		st.Kubeconfig = Misconfigured
 	} else {
-		err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port)
+		err := kubeconfig.VerifyEndpoint(cc.Name, kcHostname, kcPort)
 		if err != nil {
 			klog.Errorf("kubeconfig endpoint: %v", err)
 			st.Kubeconfig = Misconfigured
 		}
 	}
 
+	hostname, _, port, err := driver.ControlPlaneEndpoint(&cc, &n, host.DriverName)
+	if err != nil {
+		klog.Errorf("forwarded endpoint: %v", err)
+	}
+
 	sta, err := kverify.APIServerStatus(cr, hostname, port)
 	klog.Infof("%s apiserver status = %s (err=%v)", name, stk, err)
 
@@ -387,6 +485,8 @@ For the list accessible variables for the template, see the struct values here:
 	statusCmd.Flags().StringVarP(&layout, "layout", "l", "nodes",
 		`output layout (EXPERIMENTAL, JSON only): 'nodes' or 'cluster'`)
 	statusCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to check status for. Defaults to control plane. Leave blank with default format for status on all nodes.")
+	statusCmd.Flags().BoolVarP(&watchStatus, "watch", "w", false, "Keep checking the status of the cluster, and print the changes as they happen. With --output=json, each change is printed as a line of JSON.")
+	statusCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Second, "How often to check the status of the cluster with --watch")
 }
 
 func statusText(st *Status, w io.Writer) error {
@@ -459,6 +559,7 @@ func clusterState(sts []*Status) ClusterState {
 	sc := statusCode(statusName)
 	cs := ClusterState{
 		BinaryVersion: version.GetVersion(),
+		TimeToStop:    sts[0].TimeToStop,
 
 		BaseState: BaseState{
 			Name:         ClusterFlagValue(),
@@ -472,6 +573,7 @@ func clusterState(sts []*Status) ClusterState {
 		},
 	}
 
+	degraded := ""
 	for _, st := range sts {
 		ns := NodeState{
 			BaseState: BaseState{
@@ -487,6 +589,15 @@ func clusterState(sts []*Status) ClusterState {
 			ns.Components["apiserver"] = BaseState{Name: "apiserver", StatusCode: statusCode(st.APIServer)}
 		}
 
+		// Nodes which are up, but with unwanted conditions, are degraded
+		ns.StatusDetail = st.Detail
+		if ns.StatusCode == OK && st.Detail != "" {
+			ns.StatusCode = Warning
+			if degraded == "" {
+				degraded = fmt.Sprintf("%s: %s", st.Name, st.Detail)
+			}
+		}
+
 		// Convert status codes to status names
 		ns.StatusName = codeNames[ns.StatusCode]
 		for k, v := range ns.Components {
@@ -496,6 +607,11 @@ func clusterState(sts []*Status) ClusterState {
 
 		cs.Nodes = append(cs.Nodes, ns)
 	}
+	if cs.StatusCode == OK && degraded != "" {
+		cs.StatusCode = Warning
+		cs.StatusName = codeNames[Warning]
+		cs.StatusDetail = degraded
+	}
 
 	evs, mtime, err := readEventLog(sts[0].Name)
 	if err != nil {
@@ -573,7 +689,9 @@ func clusterState(sts []*Status) ClusterState {
 	}
 
 	cs.StatusName = codeNames[cs.StatusCode]
-	cs.StatusDetail = codeDetails[cs.StatusCode]
+	if cs.StatusCode != Warning {
+		cs.StatusDetail = codeDetails[cs.StatusCode]
+	}
 	return cs
 }
 
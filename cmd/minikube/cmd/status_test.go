@@ -20,6 +20,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestExitCode(t *testing.T) {
@@ -59,6 +62,11 @@ func TestStatusText(t *testing.T) {
 			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured},
 			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Stopped\napiserver: Paused\nkubeconfig: Configured\n\n",
 		},
+		{
+			name:  "scheduled",
+			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, TimeToStop: "10m0s"},
+			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Running\napiserver: Running\nkubeconfig: Configured\ntimeToStop: 10m0s\n\n",
+		},
 		{
 			name:  "down",
 			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured},
@@ -105,3 +113,89 @@ func TestStatusJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterStateDegraded(t *testing.T) {
+	sts := []*Status{
+		{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured},
+		{Name: "minikube-m02", Host: "Running", Kubelet: "Running", APIServer: Irrelevant, Kubeconfig: Irrelevant, Worker: true, Detail: "MemoryPressure: kubelet has insufficient memory available"},
+	}
+	cs := clusterState(sts)
+	if cs.StatusCode != Warning || cs.StatusName != "Warning" {
+		t.Errorf("cluster status = %d (%s), want %d", cs.StatusCode, cs.StatusName, Warning)
+	}
+	if want := "minikube-m02: MemoryPressure: kubelet has insufficient memory available"; cs.StatusDetail != want {
+		t.Errorf("cluster detail = %q, want %q", cs.StatusDetail, want)
+	}
+	if cs.Nodes[0].StatusCode != OK {
+		t.Errorf("node %s status = %d, want %d", cs.Nodes[0].Name, cs.Nodes[0].StatusCode, OK)
+	}
+	if cs.Nodes[1].StatusCode != Warning || cs.Nodes[1].StatusDetail != sts[1].Detail {
+		t.Errorf("node %s status = %d (%q), want %d (%q)", cs.Nodes[1].Name, cs.Nodes[1].StatusCode, cs.Nodes[1].StatusDetail, Warning, sts[1].Detail)
+	}
+}
+
+func TestStatusChanges(t *testing.T) {
+	running := ClusterState{
+		BaseState: BaseState{Name: "minikube", StatusCode: OK, StatusName: "OK"},
+		Nodes: []NodeState{
+			{BaseState: BaseState{Name: "minikube", StatusCode: OK, StatusName: "OK"}, Components: map[string]BaseState{"kubelet": {Name: "kubelet", StatusCode: OK, StatusName: "OK"}}},
+			{BaseState: BaseState{Name: "minikube-m02", StatusCode: OK, StatusName: "OK"}},
+		},
+	}
+	stopped := ClusterState{
+		BaseState: BaseState{Name: "minikube", StatusCode: OK, StatusName: "OK"},
+		Nodes: []NodeState{
+			{BaseState: BaseState{Name: "minikube", StatusCode: OK, StatusName: "OK"}, Components: map[string]BaseState{"kubelet": {Name: "kubelet", StatusCode: Stopped, StatusName: "Stopped"}}},
+		},
+	}
+	now := time.Now()
+
+	initial := statusChanges(nil, statusEvents(running), now)
+	if len(initial) != 4 {
+		t.Fatalf("initial changes = %+v, want every object", initial)
+	}
+	if initial[0].Kind != clusterKind {
+		t.Errorf("first change is a %s, want the cluster", initial[0].Kind)
+	}
+
+	if got := statusChanges(statusEvents(running), statusEvents(running), now); len(got) != 0 {
+		t.Errorf("changes of an unchanged cluster = %+v, want none", got)
+	}
+
+	got := statusChanges(statusEvents(running), statusEvents(stopped), now)
+	want := []StatusEvent{
+		{BaseState: BaseState{Name: "kubelet", StatusCode: Stopped, StatusName: "Stopped"}, Time: now, Kind: componentKind, Node: "minikube", Previous: "OK"},
+		{BaseState: BaseState{Name: "minikube-m02", StatusCode: NotFound, StatusName: "NotFound"}, Time: now, Kind: nodeKind, Previous: "OK"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("statusChanges mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteStatusEvent(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	ev := StatusEvent{BaseState: BaseState{Name: "kubelet", StatusCode: Stopped, StatusName: "Stopped"}, Time: now, Kind: componentKind, Node: "minikube", Previous: "OK"}
+
+	defer func(o string) { output = o }(output)
+	output = "text"
+	var b bytes.Buffer
+	if err := writeStatusEvent(ev, &b); err != nil {
+		t.Fatalf("text error: %v", err)
+	}
+	if want := "2020-10-01T12:00:00Z component minikube/kubelet: OK -> Stopped\n"; b.String() != want {
+		t.Errorf("text = %q, want %q", b.String(), want)
+	}
+
+	output = "json"
+	b.Reset()
+	if err := writeStatusEvent(ev, &b); err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+	var got StatusEvent
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("json unmarshal error: %v", err)
+	}
+	if !got.Time.Equal(ev.Time) || got.BaseState != ev.BaseState || got.Previous != ev.Previous {
+		t.Errorf("json = %+v, want %+v", got, ev)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// The kinds of objects whose state changes are reported by 'minikube status --watch'
+const (
+	clusterKind   = "Cluster"
+	nodeKind      = "Node"
+	componentKind = "Component"
+)
+
+// StatusEvent is a change of the state of a cluster, of one of its nodes, or of one of their components
+type StatusEvent struct {
+	BaseState
+
+	Time time.Time
+	// Kind is what changed: Cluster, Node or Component
+	Kind string
+	// Node is the node of a component, or empty for the components of the cluster
+	Node string `json:",omitempty"`
+	// Previous is the previous status name, or empty when the object first appears
+	Previous string `json:",omitempty"`
+}
+
+// watchClusterStatus checks the status of a cluster every interval, and writes its changes to w until the process is interrupted
+func watchClusterStatus(api libmachine.API, cname string, interval time.Duration, w io.Writer) error {
+	if interval <= 0 {
+		return errors.Errorf("invalid interval %s", interval)
+	}
+
+	var prev map[string]StatusEvent
+	for {
+		cur := statusEvents(loadClusterState(api, cname))
+		for _, ev := range statusChanges(prev, cur, time.Now()) {
+			if err := writeStatusEvent(ev, w); err != nil {
+				return err
+			}
+		}
+		prev = cur
+		time.Sleep(interval)
+	}
+}
+
+// loadClusterState returns the state of a cluster, reloading its config so that added and deleted nodes are noticed
+func loadClusterState(api libmachine.API, cname string) ClusterState {
+	cc, err := config.Load(cname)
+	if err != nil {
+		if !config.IsNotExist(err) {
+			klog.Warningf("unable to load config: %v", err)
+		}
+		return ClusterState{BaseState: BaseState{Name: cname, StatusCode: NotFound, StatusName: codeNames[NotFound]}}
+	}
+
+	statuses := clusterStatuses(api, *cc)
+	if len(statuses) == 0 {
+		return ClusterState{BaseState: BaseState{Name: cname, StatusCode: NotFound, StatusName: codeNames[NotFound]}}
+	}
+	nodeConditions(cname, statuses)
+	cs := clusterState(statuses)
+	cs.Name = cname
+	return cs
+}
+
+// statusEvents flattens the state of a cluster into the states of its objects, keyed so that the cluster sorts first and components follow their node
+func statusEvents(cs ClusterState) map[string]StatusEvent {
+	evs := map[string]StatusEvent{
+		"cluster": {BaseState: cs.BaseState, Kind: clusterKind},
+	}
+	for k, c := range cs.Components {
+		evs["cluster "+k] = StatusEvent{BaseState: c, Kind: componentKind}
+	}
+	for _, n := range cs.Nodes {
+		evs["node "+n.Name] = StatusEvent{BaseState: n.BaseState, Kind: nodeKind}
+		for k, c := range n.Components {
+			evs["node "+n.Name+" "+k] = StatusEvent{BaseState: c, Kind: componentKind, Node: n.Name}
+		}
+	}
+	return evs
+}
+
+// statusChanges returns the objects whose state changed between two checks, including those which disappeared
+func statusChanges(prev map[string]StatusEvent, cur map[string]StatusEvent, now time.Time) []StatusEvent {
+	var keys []string
+	for k := range cur {
+		keys = append(keys, k)
+	}
+	for k := range prev {
+		if _, ok := cur[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []StatusEvent
+	for _, k := range keys {
+		p, existed := prev[k]
+		c, exists := cur[k]
+		if !exists {
+			c = StatusEvent{BaseState: BaseState{Name: p.Name, StatusCode: NotFound, StatusName: codeNames[NotFound]}, Kind: p.Kind, Node: p.Node}
+		}
+		if existed && p.BaseState == c.BaseState {
+			continue
+		}
+		if existed {
+			c.Previous = p.StatusName
+		}
+		c.Time = now
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// writeStatusEvent writes a status change as a line of text, or of JSON with --output=json
+func writeStatusEvent(ev StatusEvent, w io.Writer) error {
+	if strings.ToLower(output) == "json" {
+		bs, err := json.Marshal(ev)
+		if err != nil {
+			return errors.Wrap(err, "marshal")
+		}
+		_, err = fmt.Fprintf(w, "%s\n", bs)
+		return err
+	}
+
+	name := ev.Name
+	if ev.Kind == componentKind && ev.Node != "" {
+		name = ev.Node + "/" + ev.Name
+	}
+	line := fmt.Sprintf("%s %s %s: %s", ev.Time.Format(time.RFC3339), strings.ToLower(ev.Kind), name, ev.StatusName)
+	if ev.Previous != "" {
+		line = fmt.Sprintf("%s %s %s: %s -> %s", ev.Time.Format(time.RFC3339), strings.ToLower(ev.Kind), name, ev.Previous, ev.StatusName)
+	}
+	if ev.Step != "" {
+		line += fmt.Sprintf(" [%s]", ev.Step)
+	}
+	if ev.StatusDetail != "" {
+		line += fmt.Sprintf(" (%s)", ev.StatusDetail)
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
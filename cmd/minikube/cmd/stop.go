@@ -35,13 +35,17 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
 var (
-	stopAll    bool
-	keepActive bool
+	stopAll         bool
+	keepActive      bool
+	scheduledStop   time.Duration
+	cancelScheduled bool
+	stopOutput      string
 )
 
 // stopCmd represents the stop command
@@ -55,6 +59,9 @@ var stopCmd = &cobra.Command{
 func init() {
 	stopCmd.Flags().BoolVar(&stopAll, "all", false, "Set flag to stop all profiles (clusters)")
 	stopCmd.Flags().BoolVar(&keepActive, "keep-context-active", false, "keep the kube-context active after cluster is stopped. Defaults to false.")
+	stopCmd.Flags().DurationVar(&scheduledStop, "schedule", 0*time.Second, "Set flag to stop cluster after a set amount of time (e.g. --schedule=5m). Not supported by the none and ssh drivers, whose nodes minikube does not own")
+	stopCmd.Flags().BoolVar(&cancelScheduled, "cancel-scheduled", false, "cancel any existing scheduled stop requests")
+	stopCmd.Flags().StringVarP(&stopOutput, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 
 	if err := viper.GetViper().BindPFlags(stopCmd.Flags()); err != nil {
 		exit.Error(reason.InternalFlagsBind, "unable to bind flags", err)
@@ -63,8 +70,8 @@ func init() {
 
 // runStop handles the executes the flow of "minikube stop"
 func runStop(cmd *cobra.Command, args []string) {
+	out.SetJSON(stopOutput == "json")
 	register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
-	register.Reg.SetStep(register.Stopping)
 
 	// new code
 	var profilesToStop []string
@@ -81,6 +88,38 @@ func runStop(cmd *cobra.Command, args []string) {
 		profilesToStop = append(profilesToStop, cname)
 	}
 
+	if cancelScheduled {
+		register.Reg.SetStep(register.ScheduledStop)
+		if err := schedule.KillExisting(profilesToStop); err != nil {
+			exit.Error(reason.GuestStopSchedule, "Unable to cancel scheduled stop", err)
+		}
+		register.Reg.SetStep(register.Done)
+		out.T(style.Stopped, "All existing scheduled stops cancelled")
+		return
+	}
+
+	if scheduledStop != 0 {
+		register.Reg.SetStep(register.ScheduledStop)
+		if scheduledStop < 0 {
+			exit.Message(reason.Usage, "The scheduled stop duration must be positive, got {{.duration}}", out.V{"duration": scheduledStop})
+		}
+		for _, profile := range profilesToStop {
+			co := mustload.Running(profile)
+			if !schedule.Supported(co.Config.Driver) {
+				exit.Message(reason.Usage, "Scheduled stops power off the nodes, which the {{.driver}} driver does not own. Run 'minikube stop' instead", out.V{"driver": co.Config.Driver})
+			}
+		}
+		if err := schedule.Daemonize(profilesToStop, scheduledStop); err != nil {
+			exit.Error(reason.GuestStopSchedule, "Unable to schedule stop", err)
+		}
+		register.Reg.SetStep(register.Done)
+		for _, profile := range profilesToStop {
+			out.T(style.Stopped, `Scheduled "{{.profile}}" to stop in {{.duration}}`, out.V{"profile": profile, "duration": scheduledStop})
+		}
+		return
+	}
+
+	register.Reg.SetStep(register.Stopping)
 	stoppedNodes := 0
 	for _, profile := range profilesToStop {
 		stoppedNodes = stopProfile(profile)
@@ -97,6 +136,11 @@ func stopProfile(profile string) int {
 	register.Reg.SetStep(register.Stopping)
 
 	// end new code
+	// a manual stop supersedes any scheduled one
+	if err := schedule.KillExisting([]string{profile}); err != nil {
+		klog.Warningf("error cancelling scheduled stop for %s: %v", profile, err)
+	}
+
 	api, cc := mustload.Partial(profile)
 	defer api.Close()
 
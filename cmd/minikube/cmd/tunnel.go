@@ -18,27 +18,44 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/tunnel"
 	"k8s.io/minikube/pkg/minikube/tunnel/kic"
 )
 
-var cleanup bool
+// tunnelBackgroundEnv is set for the tunnel started by 'minikube tunnel start --background'
+const tunnelBackgroundEnv = "MINIKUBE_TUNNEL_BACKGROUND"
+
+var (
+	cleanup          bool
+	tunnelBackground bool
+	tunnelReconnect  bool
+	tunnelNamespaces []string
+	tunnelServices   []string
+)
 
 // tunnelCmd represents the tunnel command
 var tunnelCmd = &cobra.Command{
@@ -49,60 +66,253 @@ var tunnelCmd = &cobra.Command{
 		RootCmd.PersistentPreRun(cmd, args)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		manager := tunnel.NewManager()
-		cname := ClusterFlagValue()
-		co := mustload.Healthy(cname)
+		runTunnel(ClusterFlagValue())
+	},
+}
 
-		if cleanup {
-			klog.Info("Checking for tunnels to cleanup...")
-			if err := manager.CleanupNotRunningTunnels(); err != nil {
-				klog.Errorf("error cleaning up: %s", err)
-			}
+// tunnelStartCmd represents the tunnel start command
+var tunnelStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Connect to LoadBalancer services, in the foreground or in the background",
+	Long:  `Connect to LoadBalancer services, like 'minikube tunnel'. With --background, the tunnel keeps running after the command exits, until 'minikube tunnel stop'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		if tunnelBackground {
+			startTunnelBackground(cname)
+			return
 		}
+		runTunnel(cname)
+	},
+}
 
-		// Tunnel uses the k8s clientset to query the API server for services in the LoadBalancerEmulator.
-		// We define the tunnel and minikube error free if the API server responds within a second.
-		// This also contributes to better UX, the tunnel status check can happen every second and
-		// doesn't hang on the API server call during startup and shutdown time or if there is a temporary error.
-		clientset, err := kapi.Client(cname)
+// tunnelStatusCmd represents the tunnel status command
+var tunnelStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the routes and LoadBalancer services of a running tunnel",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		st, err := tunnel.LoadState(cname)
 		if err != nil {
-			exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
+			exit.Error(reason.SvcTunnelStart, "Unable to read the state of the tunnel", err)
+		}
+		if st == nil {
+			out.T(style.Stopped, "No tunnel is running for {{.profile}}", out.V{"profile": cname})
+			return
+		}
+
+		mode := "in the foreground"
+		if st.Background {
+			mode = "in the background"
+		}
+		out.T(style.Running, "The tunnel for {{.profile}} is running {{.mode}} (pid {{.pid}}, since {{.time}})", out.V{"profile": cname, "mode": mode, "pid": st.Pid, "time": st.Started.Format(time.RFC3339)})
+		if len(st.Filter.Namespaces) > 0 {
+			out.Infof("Namespaces: {{.namespaces}}", out.V{"namespaces": strings.Join(st.Filter.Namespaces, ", ")})
+		}
+		if len(st.Filter.Services) > 0 {
+			out.Infof("Services: {{.services}}", out.V{"services": strings.Join(st.Filter.Services, ", ")})
 		}
 
-		ctrlC := make(chan os.Signal, 1)
-		signal.Notify(ctrlC, os.Interrupt)
-		ctx, cancel := context.WithCancel(context.Background())
-		go func() {
-			<-ctrlC
-			cancel()
-		}()
+		routes, err := tunnel.Routes(cname)
+		if err != nil {
+			klog.Warningf("unable to list routes: %v", err)
+		}
+		for _, r := range routes {
+			out.Infof("Route: {{.route}}", out.V{"route": r.String()})
+		}
+		_, cc := mustload.Partial(cname)
+		if st.Background && len(routes) == 0 && !driver.NeedsPortForward(cc.Driver) {
+			// routes are added with sudo, which a background tunnel can not ask a password for once its credentials expire
+			out.WarningT("The tunnel has no route to the cluster, which it only adds while the cluster runs. If the cluster runs, see its log for why: {{.log}}", out.V{"log": tunnel.LogPath(cname)})
+		}
 
-		if driver.NeedsPortForward(co.Config.Driver) {
+		client, err := kapi.Client(cname)
+		if err != nil {
+			exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
+		}
+		lb := tunnel.NewFilteredLoadBalancerEmulator(client.CoreV1(), st.Filter)
+		svcs, err := lb.Services()
+		if err != nil {
+			exit.Error(reason.SvcList, "Unable to list the LoadBalancer services", err)
+		}
+		if len(svcs) == 0 {
+			out.T(style.Empty, "The tunnel has no LoadBalancer services")
+			return
+		}
 
-			port, err := oci.ForwardedPort(oci.Docker, cname, 22)
-			if err != nil {
-				exit.Error(reason.DrvPortForward, "error getting ssh port", err)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Namespace", "Name", "External IP", "Ports"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, svc := range svcs {
+			var ips, ports []string
+			for _, i := range svc.Status.LoadBalancer.Ingress {
+				ips = append(ips, i.IP)
 			}
-			sshPort := strconv.Itoa(port)
-			sshKey := filepath.Join(localpath.MiniPath(), "machines", cname, "id_rsa")
-
-			kicSSHTunnel := kic.NewSSHTunnel(ctx, sshPort, sshKey, clientset.CoreV1())
-			err = kicSSHTunnel.Start()
-			if err != nil {
-				exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
+			if len(ips) == 0 {
+				ips = []string{"<pending>"}
+			}
+			for _, p := range svc.Spec.Ports {
+				ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
 			}
+			table.Append([]string{svc.Namespace, svc.Name, strings.Join(ips, ","), strings.Join(ports, ",")})
+		}
+		table.Render()
+	},
+}
 
+// tunnelStopCmd represents the tunnel stop command
+var tunnelStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running tunnel, removing its routes",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		st, err := tunnel.LoadState(cname)
+		if err != nil {
+			exit.Error(reason.SvcTunnelStop, "Unable to read the state of the tunnel", err)
+		}
+		if st == nil {
+			out.T(style.Stopped, "No tunnel is running for {{.profile}}", out.V{"profile": cname})
 			return
 		}
 
-		done, err := manager.StartTunnel(ctx, cname, co.API, config.DefaultLoader, clientset.CoreV1())
+		out.T(style.Stopping, "Stopping the tunnel for {{.profile}} (pid {{.pid}}) ...", out.V{"profile": cname, "pid": st.Pid})
+		if err := tunnel.Stop(cname, 30*time.Second); err != nil {
+			exit.Error(reason.SvcTunnelStop, "Unable to stop the tunnel", err)
+		}
+		out.T(style.Stopped, "Stopped the tunnel for {{.profile}}", out.V{"profile": cname})
+	},
+}
+
+// runTunnel runs a tunnel in the foreground, until it is interrupted or, unless it reconnects, the cluster stops
+func runTunnel(cname string) {
+	manager := tunnel.NewManager()
+	co := mustload.Healthy(cname)
+	exitIfLoadBalancerAddon(co.Config)
+
+	if st, err := tunnel.LoadState(cname); err == nil && st != nil {
+		exit.Message(reason.SvcTunnelStart, "A tunnel is already running for {{.profile}} (pid {{.pid}})", out.V{"profile": cname, "pid": st.Pid})
+	}
+
+	if cleanup {
+		klog.Info("Checking for tunnels to cleanup...")
+		if err := manager.CleanupNotRunningTunnels(); err != nil {
+			klog.Errorf("error cleaning up: %s", err)
+		}
+	}
+
+	// Tunnel uses the k8s clientset to query the API server for services in the LoadBalancerEmulator.
+	// We define the tunnel and minikube error free if the API server responds within a second.
+	// This also contributes to better UX, the tunnel status check can happen every second and
+	// doesn't hang on the API server call during startup and shutdown time or if there is a temporary error.
+	clientset, err := kapi.Client(cname)
+	if err != nil {
+		exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
+	}
+
+	background := os.Getenv(tunnelBackgroundEnv) != ""
+	if background {
+		// The background tunnel outlives the terminal it was started from
+		signal.Ignore(syscall.SIGHUP)
+	}
+	ctrlC := make(chan os.Signal, 1)
+	signal.Notify(ctrlC, os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctrlC
+		cancel()
+	}()
+
+	filter := tunnel.ServiceFilter{Namespaces: tunnelNamespaces, Services: tunnelServices}
+	st := tunnel.State{Pid: os.Getpid(), Started: time.Now(), Background: background, Filter: filter}
+	if err := tunnel.SaveState(cname, st); err != nil {
+		klog.Warningf("unable to save the state of the tunnel: %v", err)
+	}
+	defer func() {
+		if err := tunnel.RemoveState(cname); err != nil {
+			klog.Warningf("unable to remove the state of the tunnel: %v", err)
+		}
+	}()
+
+	if driver.NeedsPortForward(co.Config.Driver) {
+		sshPort := func() (string, error) {
+			port, err := oci.ForwardedPort(oci.Docker, cname, 22)
+			return strconv.Itoa(port), err
+		}
+		if _, err := sshPort(); err != nil {
+			exit.Error(reason.DrvPortForward, "error getting ssh port", err)
+		}
+		sshKey := filepath.Join(localpath.MiniPath(), "machines", cname, "id_rsa")
+
+		kicSSHTunnel := kic.NewSSHTunnel(ctx, sshPort, sshKey, clientset.CoreV1(), filter)
+		err = kicSSHTunnel.Start()
 		if err != nil {
 			exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
 		}
-		<-done
-	},
+
+		return
+	}
+
+	done, err := manager.StartTunnel(ctx, cname, co.API, config.DefaultLoader, clientset.CoreV1(), tunnel.Options{Filter: filter, Reconnect: tunnelReconnect})
+	if err != nil {
+		exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
+	}
+	<-done
+}
+
+// exitIfLoadBalancerAddon exits if the loadbalancer-controller addon assigns addresses to LoadBalancer services,
+// which the tunnel would replace with their ClusterIP
+func exitIfLoadBalancerAddon(cc *config.ClusterConfig) {
+	if !assets.Addons["loadbalancer-controller"].IsEnabled(cc) {
+		return
+	}
+	out.T(style.Tip, "To use the tunnel instead, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cc.Name, "addons disable loadbalancer-controller")})
+	exit.Message(reason.SvcTunnelStart, "LoadBalancer services of {{.profile}} already get external IPs {{.start}}-{{.end}} from the loadbalancer-controller addon, no tunnel is needed", out.V{"profile": cc.Name, "start": cc.KubernetesConfig.LoadBalancerStartIP, "end": cc.KubernetesConfig.LoadBalancerEndIP})
+}
+
+// startTunnelBackground starts a tunnel which keeps running after minikube exits, logging to the profile directory
+func startTunnelBackground(cname string) {
+	co := mustload.Healthy(cname)
+	exitIfLoadBalancerAddon(co.Config)
+	if st, err := tunnel.LoadState(cname); err == nil && st != nil {
+		exit.Message(reason.SvcTunnelStart, "A tunnel is already running for {{.profile}} (pid {{.pid}})", out.V{"profile": cname, "pid": st.Pid})
+	}
+
+	// Routes are added with sudo, which can not ask for a password once the tunnel runs in the background
+	cacheSudo("The tunnel needs sudo to add routes, which may ask for your password now.")
+
+	logPath := tunnel.LogPath(cname)
+	args := []string{"tunnel", "start", "-p", cname,
+		fmt.Sprintf("--cleanup=%t", cleanup),
+		fmt.Sprintf("--reconnect=%t", tunnelReconnect),
+		"--namespaces=" + strings.Join(tunnelNamespaces, ","),
+		"--services=" + strings.Join(tunnelServices, ","),
+	}
+	// The tunnel records its state once it runs
+	pid, err := startBackground(args, []string{tunnelBackgroundEnv + "=true"}, logPath, func(pid int) bool {
+		st, err := tunnel.LoadState(cname)
+		return err == nil && st != nil && st.Pid == pid
+	})
+	if err != nil {
+		exit.Error(reason.SvcTunnelStart, "Unable to start the tunnel", err)
+	}
+	out.T(style.Running, "The tunnel for {{.profile}} is running in the background (pid {{.pid}}), logging to {{.log}}", out.V{"profile": cname, "pid": pid, "log": logPath})
+	out.T(style.Tip, "To stop it, run: '{{.command}}'", out.V{"command": mustload.ExampleCmd(cname, "tunnel stop")})
+}
+
+// addTunnelFlags adds the flags of the tunnels run by 'minikube tunnel' and 'minikube tunnel start'
+func addTunnelFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&cleanup, "cleanup", "c", true, "call with cleanup=true to remove old tunnels")
+	cmd.Flags().BoolVar(&tunnelReconnect, "reconnect", true, "Keep the tunnel running while the cluster is stopped, and restore its route once the cluster runs again")
+	cmd.Flags().StringSliceVar(&tunnelNamespaces, "namespaces", []string{}, "Only tunnel the LoadBalancer services of these namespaces. Defaults to every namespace.")
+	cmd.Flags().StringSliceVar(&tunnelServices, "services", []string{}, "Only tunnel these LoadBalancer services, as name or namespace/name. Defaults to every service.")
 }
 
 func init() {
-	tunnelCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", true, "call with cleanup=true to remove old tunnels")
+	addTunnelFlags(tunnelCmd)
+	addTunnelFlags(tunnelStartCmd)
+	tunnelStartCmd.Flags().BoolVar(&tunnelBackground, "background", false, "Keep the tunnel running in the background after the command exits")
+	tunnelCmd.AddCommand(tunnelStartCmd)
+	tunnelCmd.AddCommand(tunnelStatusCmd)
+	tunnelCmd.AddCommand(tunnelStopCmd)
 }
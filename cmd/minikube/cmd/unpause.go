@@ -81,7 +81,7 @@ var unpauseCmd = &cobra.Command{
 				exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
 			}
 
-			cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: r})
+			cr, err := cruntime.ForCluster(co.Config.KubernetesConfig, r)
 			if err != nil {
 				exit.Error(reason.InternalNewRuntime, "Failed runtime", err)
 			}
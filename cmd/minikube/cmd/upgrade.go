@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+var upgradeVersion string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade the Kubernetes version of a running cluster in place",
+	Long: `Upgrade the Kubernetes version of a running cluster in place, the way kubeadm clusters are upgraded in production.
+
+The control planes are upgraded first with 'kubeadm upgrade', then each worker is drained, upgraded and uncordoned.
+A snapshot of etcd is saved to the profile directory beforehand, and if the upgrade fails the cluster is rolled back to the snapshot and its previous version.`,
+	Example: "minikube upgrade --kubernetes-version=v1.19.2",
+	Run: func(cmd *cobra.Command, args []string) {
+		if upgradeVersion == "" {
+			exit.Message(reason.Usage, "Please specify the version to upgrade to with --kubernetes-version")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		defer co.API.Close()
+
+		oldVersion := co.Config.KubernetesConfig.KubernetesVersion
+		ovs, err := util.ParseKubernetesVersion(oldVersion)
+		if err != nil {
+			exit.Error(reason.InternalSemverParse, "Unable to parse the version of the cluster", err)
+		}
+		nvs, err := util.ParseKubernetesVersion(upgradeVersion)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid Kubernetes version {{.version}}: {{.error}}", out.V{"version": upgradeVersion, "error": err})
+		}
+		if err := validateUpgrade(ovs, nvs); err != nil {
+			exit.Message(reason.Usage, "Unable to upgrade from Kubernetes v{{.old}} to v{{.new}}: {{.error}}", out.V{"old": ovs, "new": nvs, "error": err})
+		}
+		if newest, err := util.ParseKubernetesVersion(constants.NewestKubernetesVersion); err == nil && nvs.GT(newest) {
+			out.WarningT("Kubernetes v{{.version}} is newer than the newest version tested by minikube: {{.newest}}", out.V{"version": nvs, "newest": constants.NewestKubernetesVersion})
+		}
+
+		newVersion := version.VersionPrefix + nvs.String()
+		out.T(style.Launch, "Upgrading {{.cluster}} from Kubernetes {{.old}} to {{.new}} ...", out.V{"cluster": co.Config.Name, "old": oldVersion, "new": newVersion})
+		if err := node.Upgrade(co.API, co.Config, newVersion); err != nil {
+			exit.Error(reason.KubernetesUpgradeFailed, "Failed to upgrade Kubernetes", err)
+		}
+		out.T(style.Ready, "Upgraded {{.cluster}} to Kubernetes {{.new}}", out.V{"cluster": co.Config.Name, "new": newVersion})
+	},
+}
+
+// validateUpgrade returns an error unless kubeadm is able to upgrade from the old version to the new one
+func validateUpgrade(from semver.Version, to semver.Version) error {
+	if !to.GT(from) {
+		return fmt.Errorf("the new version must be newer")
+	}
+	if to.Major != from.Major || to.Minor > from.Minor+1 {
+		return fmt.Errorf("kubeadm upgrades one minor version at a time, so upgrade to v%d.%d first", from.Major, from.Minor+1)
+	}
+	return nil
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeVersion, "kubernetes-version", "", "The Kubernetes version to upgrade to (ex: v1.19.2)")
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+)
+
+func TestValidateUpgrade(t *testing.T) {
+	var tests = []struct {
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"1.18.8", "1.18.9", false},
+		{"1.18.8", "1.19.2", false},
+		{"1.18.8", "1.19.0-rc.4", false},
+		{"1.18.8", "1.18.8", true},
+		{"1.19.2", "1.18.8", true},
+		{"1.17.11", "1.19.2", true},
+		{"1.19.2", "2.0.0", true},
+	}
+	for _, tc := range tests {
+		err := validateUpgrade(semver.MustParse(tc.from), semver.MustParse(tc.to))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validateUpgrade(%s, %s) = %v, wantErr %v", tc.from, tc.to, err, tc.wantErr)
+		}
+	}
+}
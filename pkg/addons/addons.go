@@ -33,6 +33,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/auth"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -382,6 +383,11 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 		}
 	}
 
+	// dex is the OpenID Connect issuer of clusters which were not given another one
+	if cc.KubernetesConfig.Auth.LocalDex {
+		additional = append(additional, auth.DexAddon)
+	}
+
 	// Apply new addons
 	for _, name := range additional {
 		// replace heapster as metrics-server because heapster is deprecated
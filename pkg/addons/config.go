@@ -156,6 +156,12 @@ var Addons = []*Addon{
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
 	},
+	{
+		name:        "loadbalancer-controller",
+		set:         SetBool,
+		validations: []setFn{reserveLoadBalancerPool, HasLoadBalancerPool},
+		callbacks:   []setFn{enableOrDisableAddon},
+	},
 	{
 		name:      "ambassador",
 		set:       SetBool,
@@ -182,4 +188,10 @@ var Addons = []*Addon{
 		validations: []setFn{IsVolumesnapshotsEnabled},
 		callbacks:   []setFn{enableOrDisableAddon, verifyAddonStatus},
 	},
+	{
+		name:        "dex",
+		set:         SetBool,
+		validations: []setFn{IsLocalOIDCIssuer},
+		callbacks:   []setFn{enableOrDisableAddon},
+	},
 }
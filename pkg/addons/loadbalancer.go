@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+const (
+	// kvmNetwork is the libvirt network which the kvm2 driver attaches the nodes of every profile to
+	kvmNetwork = "minikube-net"
+	// kvmPoolSize is the number of addresses of each kvm2 profile, which share the network
+	kvmPoolSize = 10
+)
+
+// ReserveLoadBalancerPool reserves addresses of the network of the nodes for the LoadBalancer services of a cluster
+// which has none, which the loadbalancer-controller addon assigns
+func ReserveLoadBalancerPool(cc *config.ClusterConfig) error {
+	if cc.KubernetesConfig.LoadBalancerStartIP != "" {
+		return nil
+	}
+	start, end, err := loadBalancerPool(*cc)
+	if err != nil || start == "" {
+		return err
+	}
+	klog.Infof("reserving %s-%s for LoadBalancer services of %s", start, end, cc.Name)
+	cc.KubernetesConfig.LoadBalancerStartIP = start
+	cc.KubernetesConfig.LoadBalancerEndIP = end
+	return nil
+}
+
+// loadBalancerPool returns the range of addresses for LoadBalancer services on the network of the nodes,
+// which Linux hosts reach directly with the docker and kvm2 drivers
+func loadBalancerPool(cc config.ClusterConfig) (string, string, error) {
+	if runtime.GOOS != "linux" {
+		return "", "", nil
+	}
+
+	switch cc.Driver {
+	case driver.Docker:
+		// every profile has a network of its own
+		subnet, err := oci.NetworkSubnet(oci.Docker, cc.Name)
+		if err != nil {
+			return "", "", errors.Wrap(err, "network subnet")
+		}
+		return poolFromSubnet(subnet)
+	case driver.KVM2:
+		return kvmPool(cc)
+	default:
+		return "", "", nil
+	}
+}
+
+// poolFromSubnet returns the addresses from .200 to .249 of a subnet: above those of the nodes,
+// outside of the DHCP range of the networks the kvm2 driver creates, and below the virtual IP of highly available clusters
+func poolFromSubnet(subnet *net.IPNet) (string, string, error) {
+	base := subnet.IP.To4()
+	if ones, bits := subnet.Mask.Size(); base == nil || bits != 32 || ones > 24 {
+		return "", "", fmt.Errorf("subnet %s is too small for LoadBalancer services", subnet)
+	}
+	return withLastOctet(base, 200).String(), withLastOctet(base, 249).String(), nil
+}
+
+// kvmPool returns a share of .200 to .249 of the kvm2 network which is outside of its DHCP range, and not reserved
+// by another profile, as the nodes of every profile share the network
+func kvmPool(cc config.ClusterConfig) (string, string, error) {
+	uri := cc.KVMQemuURI
+	if uri == "" {
+		uri = "qemu:///system"
+	}
+	c := exec.Command("virsh", "-c", uri, "net-dumpxml", kvmNetwork)
+	xmlData, err := c.Output()
+	if err != nil {
+		return "", "", errors.Wrapf(err, "%s", c.Args)
+	}
+	subnet, dhcp, err := kvmDHCPRange(xmlData)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "network %s", kvmNetwork)
+	}
+
+	profiles, _, err := config.ListProfiles()
+	if err != nil {
+		klog.Warningf("unable to list profiles: %v", err)
+	}
+	taken := map[string][2]net.IP{}
+	for _, p := range profiles {
+		if p.Name == cc.Name || p.Config == nil || p.Config.Driver != driver.KVM2 {
+			continue
+		}
+		k := p.Config.KubernetesConfig
+		start, end := net.ParseIP(k.LoadBalancerStartIP), net.ParseIP(k.LoadBalancerEndIP)
+		if start != nil && end != nil && subnet.Contains(start) {
+			taken[p.Name] = [2]net.IP{start, end}
+		}
+	}
+	return partitionPool(subnet, dhcp, taken)
+}
+
+// kvmDHCPRange returns the subnet of a libvirt network and the range of addresses leased by its DHCP server,
+// from the XML of 'virsh net-dumpxml'
+func kvmDHCPRange(xmlData []byte) (*net.IPNet, [2]net.IP, error) {
+	var network struct {
+		IPs []struct {
+			Address string `xml:"address,attr"`
+			Netmask string `xml:"netmask,attr"`
+			Prefix  string `xml:"prefix,attr"`
+			Ranges  []struct {
+				Start string `xml:"start,attr"`
+				End   string `xml:"end,attr"`
+			} `xml:"dhcp>range"`
+		} `xml:"ip"`
+	}
+	if err := xml.Unmarshal(xmlData, &network); err != nil {
+		return nil, [2]net.IP{}, errors.Wrap(err, "parsing network xml")
+	}
+	for _, ip := range network.IPs {
+		if net.ParseIP(ip.Address).To4() == nil || len(ip.Ranges) == 0 {
+			continue
+		}
+		prefix := ip.Prefix
+		if ip.Netmask != "" {
+			ones, _ := net.IPMask(net.ParseIP(ip.Netmask).To4()).Size()
+			prefix = fmt.Sprint(ones)
+		}
+		_, subnet, err := net.ParseCIDR(ip.Address + "/" + prefix)
+		if err != nil {
+			return nil, [2]net.IP{}, err
+		}
+		start, end := net.ParseIP(ip.Ranges[0].Start), net.ParseIP(ip.Ranges[0].End)
+		if start == nil || end == nil {
+			return nil, [2]net.IP{}, fmt.Errorf("invalid DHCP range %s-%s", ip.Ranges[0].Start, ip.Ranges[0].End)
+		}
+		return subnet, [2]net.IP{start, end}, nil
+	}
+	return nil, [2]net.IP{}, fmt.Errorf("no IPv4 DHCP range")
+}
+
+// partitionPool returns the first block of kvmPoolSize addresses from .200 to .249 of a subnet which is outside
+// of the DHCP range, and does not overlap the pools taken by other profiles
+func partitionPool(subnet *net.IPNet, dhcp [2]net.IP, taken map[string][2]net.IP) (string, string, error) {
+	first, last, err := poolFromSubnet(subnet)
+	if err != nil {
+		return "", "", err
+	}
+	base := subnet.IP.To4()
+	leased := false
+	var users []string
+	for o := int(net.ParseIP(first).To4()[3]); o+kvmPoolSize-1 <= int(net.ParseIP(last).To4()[3]); o += kvmPoolSize {
+		start, end := withLastOctet(base, byte(o)), withLastOctet(base, byte(o+kvmPoolSize-1))
+		if overlaps(start, end, dhcp[0], dhcp[1]) {
+			leased = true
+			continue
+		}
+		free := true
+		for name, pool := range taken {
+			if overlaps(start, end, pool[0], pool[1]) {
+				free = false
+				users = append(users, name)
+			}
+		}
+		if free {
+			return start.String(), end.String(), nil
+		}
+	}
+	if len(users) == 0 && leased {
+		return "", "", fmt.Errorf("the DHCP range %s-%s of network %s covers %s-%s, delete every kvm2 cluster so that minikube creates the network again", dhcp[0], dhcp[1], kvmNetwork, first, last)
+	}
+	return "", "", fmt.Errorf("%s-%s of network %s is taken by the profiles %s", first, last, kvmNetwork, strings.Join(dedupe(users), ", "))
+}
+
+// withLastOctet returns an IPv4 address with its last octet replaced
+func withLastOctet(ip net.IP, o byte) net.IP {
+	r := make(net.IP, net.IPv4len)
+	copy(r, ip.To4())
+	r[3] = o
+	return r
+}
+
+// overlaps returns whether two ranges of IPv4 addresses overlap
+func overlaps(start1, end1, start2, end2 net.IP) bool {
+	u := func(ip net.IP) uint32 { return binary.BigEndian.Uint32(ip.To4()) }
+	return u(start1) <= u(end2) && u(start2) <= u(end1)
+}
+
+// dedupe returns sorted strings without duplicates
+func dedupe(ss []string) []string {
+	sort.Strings(ss)
+	var r []string
+	for i, s := range ss {
+		if i == 0 || s != ss[i-1] {
+			r = append(r, s)
+		}
+	}
+	return r
+}
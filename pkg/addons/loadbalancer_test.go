@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPoolFromSubnet(t *testing.T) {
+	tests := []struct {
+		subnet    string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"192.168.49.0/24", "192.168.49.200", "192.168.49.249", false},
+		{"192.168.58.0/24", "192.168.58.200", "192.168.58.249", false},
+		{"172.17.0.0/16", "172.17.0.200", "172.17.0.249", false},
+		{"192.168.49.0/25", "", "", true},
+		{"fd00::/64", "", "", true},
+	}
+	for _, tc := range tests {
+		_, subnet, err := net.ParseCIDR(tc.subnet)
+		if err != nil {
+			t.Fatalf("ParseCIDR(%s): %v", tc.subnet, err)
+		}
+		start, end, err := poolFromSubnet(subnet)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("poolFromSubnet(%s) error = %v, want error %t", tc.subnet, err, tc.wantErr)
+		}
+		if start != tc.wantStart || end != tc.wantEnd {
+			t.Errorf("poolFromSubnet(%s) = %s-%s, want %s-%s", tc.subnet, start, end, tc.wantStart, tc.wantEnd)
+		}
+	}
+}
+
+func TestKVMDHCPRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		xml        string
+		wantSubnet string
+		wantRange  string
+		wantErr    bool
+	}{
+		{
+			name: "netmask",
+			xml: `<network><name>minikube-net</name><ip address='192.168.39.1' netmask='255.255.255.0'>
+  <dhcp><range start='192.168.39.2' end='192.168.39.199'/></dhcp></ip></network>`,
+			wantSubnet: "192.168.39.0/24",
+			wantRange:  "192.168.39.2-192.168.39.199",
+		},
+		{
+			name: "prefix",
+			xml: `<network><name>minikube-net</name><ip family='ipv6' address='fd00::1' prefix='64'/><ip address='192.168.39.1' prefix='24'>
+  <dhcp><range start='192.168.39.2' end='192.168.39.254'/></dhcp></ip></network>`,
+			wantSubnet: "192.168.39.0/24",
+			wantRange:  "192.168.39.2-192.168.39.254",
+		},
+		{name: "no dhcp", xml: `<network><ip address='192.168.39.1' netmask='255.255.255.0'/></network>`, wantErr: true},
+		{name: "invalid", xml: `<network>`, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			subnet, dhcp, err := kvmDHCPRange([]byte(tc.xml))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("kvmDHCPRange() error = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if subnet.String() != tc.wantSubnet {
+				t.Errorf("subnet = %s, want %s", subnet, tc.wantSubnet)
+			}
+			if got := dhcp[0].String() + "-" + dhcp[1].String(); got != tc.wantRange {
+				t.Errorf("DHCP range = %s, want %s", got, tc.wantRange)
+			}
+		})
+	}
+}
+
+func TestPartitionPool(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("192.168.39.0/24")
+	if err != nil {
+		t.Fatalf("ParseCIDR: %v", err)
+	}
+	dhcp := [2]net.IP{net.ParseIP("192.168.39.2"), net.ParseIP("192.168.39.199")}
+	pool := func(start, end string) [2]net.IP { return [2]net.IP{net.ParseIP(start), net.ParseIP(end)} }
+	tests := []struct {
+		name      string
+		dhcp      [2]net.IP
+		taken     map[string][2]net.IP
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{name: "first", dhcp: dhcp, wantStart: "192.168.39.200", wantEnd: "192.168.39.209"},
+		{
+			name:      "second profile",
+			dhcp:      dhcp,
+			taken:     map[string][2]net.IP{"p1": pool("192.168.39.200", "192.168.39.209")},
+			wantStart: "192.168.39.210",
+			wantEnd:   "192.168.39.219",
+		},
+		{
+			name:      "configured range",
+			dhcp:      dhcp,
+			taken:     map[string][2]net.IP{"p1": pool("192.168.39.200", "192.168.39.209"), "p2": pool("192.168.39.215", "192.168.39.225")},
+			wantStart: "192.168.39.230",
+			wantEnd:   "192.168.39.239",
+		},
+		{name: "full", dhcp: dhcp, taken: map[string][2]net.IP{"p1": pool("192.168.39.200", "192.168.39.249")}, wantErr: true},
+		{name: "old network", dhcp: [2]net.IP{net.ParseIP("192.168.39.2"), net.ParseIP("192.168.39.254")}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := partitionPool(subnet, tc.dhcp, tc.taken)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("partitionPool() error = %v, want error %t", err, tc.wantErr)
+			}
+			if start != tc.wantStart || end != tc.wantEnd {
+				t.Errorf("partitionPool() = %s-%s, want %s-%s", start, end, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
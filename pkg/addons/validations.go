@@ -72,6 +72,47 @@ func IsVolumesnapshotsEnabled(cc *config.ClusterConfig, _, value string) error {
 	return nil
 }
 
+// IsLocalOIDCIssuer is a validator which returns an error if the cluster was not started with dex as its OpenID Connect issuer
+func IsLocalOIDCIssuer(cc *config.ClusterConfig, _, value string) error {
+	enable, err := strconv.ParseBool(value)
+	if err != nil || !enable {
+		return nil
+	}
+	if !cc.KubernetesConfig.Auth.LocalDex {
+		return fmt.Errorf("the dex addon requires the cluster to be started with --auth-mode=oidc and no --oidc-issuer-url")
+	}
+	return nil
+}
+
+// reserveLoadBalancerPool is a validator which reserves addresses for LoadBalancer services when the
+// loadbalancer-controller addon is enabled, before HasLoadBalancerPool checks them
+func reserveLoadBalancerPool(cc *config.ClusterConfig, _, value string) error {
+	enable, err := strconv.ParseBool(value)
+	if err != nil || !enable {
+		return nil
+	}
+	if err := ReserveLoadBalancerPool(cc); err != nil {
+		return fmt.Errorf("unable to reserve addresses for LoadBalancer services: %v", err)
+	}
+	return nil
+}
+
+// HasLoadBalancerPool is a validator which returns an error if the cluster has no addresses for LoadBalancer services,
+// or if MetalLB assigns them
+func HasLoadBalancerPool(cc *config.ClusterConfig, _, value string) error {
+	enable, err := strconv.ParseBool(value)
+	if err != nil || !enable {
+		return nil
+	}
+	if cc.KubernetesConfig.LoadBalancerStartIP == "" || cc.KubernetesConfig.LoadBalancerEndIP == "" {
+		return fmt.Errorf("the loadbalancer-controller addon needs a range of addresses, configure one with 'minikube addons configure loadbalancer-controller'")
+	}
+	if assets.Addons["metallb"].IsEnabled(cc) {
+		return fmt.Errorf("the loadbalancer-controller addon conflicts with the metallb addon, disable it first")
+	}
+	return nil
+}
+
 // isAddonValid returns the addon, true if it is valid
 // otherwise returns nil, false
 func isAddonValid(name string) (*Addon, bool) {
@@ -18,7 +18,9 @@ package oci
 
 import (
 	"errors"
+	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -65,48 +67,53 @@ var ErrNetworkInUse = errors.New("unable to delete a network that is attached to
 
 // LogContainerDebug will print relevant docker/podman infos after a container fails
 func LogContainerDebug(ociBin string, name string) string {
-	rr, err := containerInspect(ociBin, name)
-	if err != nil {
-		klog.Warningf("Failed to get postmortem inspect. %s :%v", rr.Command(), err)
-	} else {
-		klog.Infof("Postmortem inspect (%q): %s", rr.Command(), rr.Output())
+	debug := ContainerDebug(ociBin, name)
+	files := make([]string, 0, len(debug))
+	for file := range debug {
+		files = append(files, file)
 	}
-
-	rr, err = containerLogs(ociBin, name)
-	if err != nil {
-		klog.Warningf("Failed to get postmortem logs. %s :%v", rr.Command(), err)
-	} else {
-		klog.Infof("Postmortem logs (%q): %s", rr.Command(), rr.Output())
+	sort.Strings(files)
+	for _, file := range files {
+		klog.Infof("Postmortem %s of %q: %s", file, name, debug[file])
 	}
 	if ociBin == Docker {
-		di, err := dockerSystemInfo()
-		if err != nil {
-			klog.Warningf("Failed to get postmortem docker info: %v", err)
-		} else {
-			klog.Infof("postmortem docker info: %+v", di)
-		}
 		logDockerNetworkInspect(name)
-	} else {
-		pi, err := podmanSystemInfo()
-		if err != nil {
-			klog.Warningf("couldn't get postmortem info, failed to to run podman info: %v", err)
-		} else {
-			klog.Infof("postmortem podman info: %+v", pi)
-		}
 	}
 
-	if rr.Stdout.Len() == 0 {
+	logs := strings.TrimSpace(string(debug["logs.txt"]))
+	if logs == "" {
 		return ""
 	}
 
 	// If available, return an excerpt of the post-mortem logs for inclusion in error message
-	excerpt := strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n")
+	excerpt := strings.Split(logs, "\n")
 	if len(excerpt) > 4 {
 		excerpt = excerpt[len(excerpt)-4:]
 	}
 	return strings.Join(excerpt, "\n")
 }
 
+// ContainerDebug returns the inspect and logs of a container along with the info of its daemon, keyed by file name.
+// Failures are returned in place of the output, so that the debug info of a broken container is still complete.
+func ContainerDebug(ociBin string, name string) map[string][]byte {
+	debug := map[string][]byte{}
+	record := func(file string, rr *RunResult, err error) {
+		if err != nil {
+			debug[file] = []byte(fmt.Sprintf("%s: %v\n%s", rr.Command(), err, rr.Output()))
+			return
+		}
+		debug[file] = rr.Stdout.Bytes()
+	}
+
+	rr, err := containerInspect(ociBin, name)
+	record("inspect.json", rr, err)
+	rr, err = containerLogs(ociBin, name)
+	record("logs.txt", rr, err)
+	rr, err = runCmd(exec.Command(ociBin, "info"))
+	record(ociBin+"-info.txt", rr, err)
+	return debug
+}
+
 // containerLogs will return out the logs for a container
 func containerLogs(ociBin string, name string) (*RunResult, error) {
 	if ociBin == Docker {
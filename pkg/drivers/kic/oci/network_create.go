@@ -99,6 +99,15 @@ func tryCreateDockerNetwork(subnetAddr string, subnetMask int, name string) (net
 	return gateway, nil
 }
 
+// NetworkSubnet returns the subnet of the network minikube created for a cluster
+func NetworkSubnet(ociBin string, name string) (*net.IPNet, error) {
+	if ociBin != Docker {
+		return nil, fmt.Errorf("%s network not implemented yet", ociBin)
+	}
+	subnet, _, err := dockerNetworkInspect(name)
+	return subnet, err
+}
+
 // returns subnet and gate if exists
 func dockerNetworkInspect(name string) (*net.IPNet, net.IP, error) {
 	cmd := exec.Command(Docker, "network", "inspect", name, "--format", "{{(index .IPAM.Config 0).Subnet}},{{(index .IPAM.Config 0).Gateway}}")
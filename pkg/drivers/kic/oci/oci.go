@@ -321,6 +321,22 @@ func StartContainer(ociBin string, container string) error {
 	return nil
 }
 
+// CommitContainer saves the filesystem of a container as the image named imageName
+func CommitContainer(ociBin string, container string, imageName string) error {
+	if _, err := runCmd(exec.Command(ociBin, "commit", container, imageName)); err != nil {
+		return errors.Wrapf(err, "committing %s", container)
+	}
+	return nil
+}
+
+// RemoveImage removes an image
+func RemoveImage(ociBin string, imageName string) error {
+	if _, err := runCmd(exec.Command(ociBin, "rmi", imageName)); err != nil {
+		return errors.Wrapf(err, "removing %s", imageName)
+	}
+	return nil
+}
+
 // ContainerID returns id of a container name
 func ContainerID(ociBin string, nameOrID string) (string, error) {
 	rr, err := runCmd(exec.Command(ociBin, "container", "inspect", "-f", "{{.Id}}", nameOrID))
@@ -616,6 +632,30 @@ func ShutDown(ociBin string, name string) error {
 	return nil
 }
 
+// PauseContainer freezes the processes of a container
+func PauseContainer(ociBin string, name string) error {
+	if _, err := runCmd(exec.Command(ociBin, "pause", name)); err != nil {
+		return errors.Wrapf(err, "pause %s", name)
+	}
+	return nil
+}
+
+// UnpauseContainer resumes the processes of a container frozen by PauseContainer
+func UnpauseContainer(ociBin string, name string) error {
+	if _, err := runCmd(exec.Command(ociBin, "unpause", name)); err != nil {
+		return errors.Wrapf(err, "unpause %s", name)
+	}
+	return nil
+}
+
+// KillContainer kills a container without shutting it down
+func KillContainer(ociBin string, name string) error {
+	if _, err := runCmd(exec.Command(ociBin, "kill", name)); err != nil {
+		return errors.Wrapf(err, "kill %s", name)
+	}
+	return nil
+}
+
 // iptablesFileExists checks if /var/lib/dpkg/alternatives/iptables exists in minikube
 // this file is necessary for the entrypoint script to pass
 // TODO: https://github.com/kubernetes/minikube/issues/8179
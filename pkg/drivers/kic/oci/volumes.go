@@ -21,6 +21,8 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -118,3 +120,37 @@ func prepareVolume(ociBin string, imageName string, nodeName string) error {
 	}
 	return nil
 }
+
+// SaveVolume archives the contents of the volume named volumeName into the tarball at tarballPath,
+// using the tar binary of the image named imageName
+func SaveVolume(ociBin string, volumeName, tarballPath, imageName string) error {
+	cmdArgs := append(volumeHelperArgs(ociBin, "/usr/bin/tar"),
+		"-v", fmt.Sprintf("%s:/snapshot", filepath.Dir(tarballPath)), "-v", fmt.Sprintf("%s:/volume:ro", volumeName),
+		imageName, "-cpf", path.Join("/snapshot", filepath.Base(tarballPath)), "-C", "/volume", ".")
+	if _, err := runCmd(exec.Command(ociBin, cmdArgs...)); err != nil {
+		return errors.Wrapf(err, "saving volume %s", volumeName)
+	}
+	return nil
+}
+
+// RestoreVolume replaces the contents of the volume named volumeName with the tarball at tarballPath,
+// using the tar binary of the image named imageName
+func RestoreVolume(ociBin string, volumeName, tarballPath, imageName string) error {
+	cmdArgs := append(volumeHelperArgs(ociBin, "/bin/bash"),
+		"-v", fmt.Sprintf("%s:/snapshot.tar:ro", tarballPath), "-v", fmt.Sprintf("%s:/volume", volumeName),
+		imageName, "-c", "find /volume -mindepth 1 -delete && tar -xpf /snapshot.tar -C /volume")
+	if _, err := runCmd(exec.Command(ociBin, cmdArgs...)); err != nil {
+		return errors.Wrapf(err, "restoring volume %s", volumeName)
+	}
+	return nil
+}
+
+// volumeHelperArgs returns the arguments to run a throwaway container with the given entrypoint
+func volumeHelperArgs(ociBin string, entrypoint string) []string {
+	cmdArgs := []string{"run", "--rm", "--entrypoint", entrypoint}
+	// see ExtractTarballToVolume
+	if ociBin == Podman && runtime.GOOS == "linux" {
+		cmdArgs = append(cmdArgs, "--security-opt", "label=disable")
+	}
+	return cmdArgs
+}
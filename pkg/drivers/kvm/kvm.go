@@ -488,5 +488,6 @@ func (d *Driver) undefineDomain(conn *libvirt.Connect, dom *libvirt.Domain) erro
 		return nil
 	}
 
-	return dom.Undefine()
+	// Snapshots taken with "minikube snapshot save" would otherwise prevent the domain from being undefined
+	return dom.UndefineFlags(libvirt.DOMAIN_UNDEFINE_SNAPSHOTS_METADATA)
 }
@@ -33,6 +33,8 @@ import (
 )
 
 // Replace with hardcoded range with CIDR
+// The addresses from .200 are left out of the DHCP range for LoadBalancer services and the virtual IP of the API servers.
+// Existing networks keep the range they were created with.
 // https://play.golang.org/p/m8TNTtygK0
 const networkTmpl = `
 <network>
@@ -40,7 +42,7 @@ const networkTmpl = `
   <dns enable='no'/>
   <ip address='192.168.39.1' netmask='255.255.255.0'>
     <dhcp>
-      <range start='192.168.39.2' end='192.168.39.254'/>
+      <range start='192.168.39.2' end='192.168.39.199'/>
     </dhcp>
   </ip>
 </network>
@@ -57,12 +57,13 @@ type Config struct {
 	MachineName      string
 	StorePath        string
 	ContainerRuntime string
+	CRISocket        string
 }
 
 // NewDriver returns a fully configured None driver
 func NewDriver(c Config) *Driver {
 	runner := command.NewExecRunner()
-	runtime, err := cruntime.New(cruntime.Config{Type: c.ContainerRuntime, Runner: runner})
+	runtime, err := cruntime.New(cruntime.Config{Type: c.ContainerRuntime, Socket: c.CRISocket, Runner: runner})
 	// Libraries shouldn't panic, but there is no way for drivers to return error :(
 	if err != nil {
 		klog.Fatalf("unable to create container runtime: %v", err)
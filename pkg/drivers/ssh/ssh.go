@@ -0,0 +1,307 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// dialTimeout is how long to wait for the SSH port of the machine to answer
+const dialTimeout = 15 * time.Second
+
+// stoppedMarker marks a machine whose cluster is stopped, as the machine itself keeps running
+var stoppedMarker = path.Join(vmpath.GuestPersistentDir, "stopped")
+
+// defaultSSHKeys are the keys of ~/.ssh looked for, in order, when no key is given
+var defaultSSHKeys = []string{"id_rsa", "id_ecdsa", "id_ed25519"}
+
+// cleanupPaths are paths to be removed from the machine on removal, and are used by both kubeadm and minikube.
+var cleanupPaths = []string{
+	vmpath.GuestEphemeralDir,
+	vmpath.GuestManifestsDir,
+	vmpath.GuestPersistentDir,
+}
+
+// Driver adopts an existing machine reachable over SSH. The machine is provisioned, but never created, powered off or destroyed.
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+	EnginePort       int
+	SSHKey           string
+	ContainerRuntime string
+	CRISocket        string
+	exec             command.Runner
+}
+
+// Config is configuration for the SSH driver
+type Config struct {
+	MachineName      string
+	StorePath        string
+	ContainerRuntime string
+	CRISocket        string
+}
+
+// NewDriver returns a fully configured SSH driver
+func NewDriver(c Config) *Driver {
+	d := &Driver{
+		EnginePort:       engine.DefaultPort,
+		ContainerRuntime: c.ContainerRuntime,
+		CRISocket:        c.CRISocket,
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: c.MachineName,
+			StorePath:   c.StorePath,
+		},
+	}
+	d.exec = command.NewSSHRunner(d)
+	return d
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return "ssh"
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.GetIP()
+}
+
+// GetSSHUsername returns username for use with ssh
+func (d *Driver) GetSSHUsername() string {
+	return d.SSHUser
+}
+
+// GetSSHKeyPath returns the key path for use with ssh
+func (d *Driver) GetSSHKeyPath() string {
+	return d.SSHKeyPath
+}
+
+// PreCreateCheck checks that the machine is reachable with the given key, or with the first of the default keys
+func (d *Driver) PreCreateCheck() error {
+	key, err := d.sshKey()
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(key)
+	if err != nil {
+		return errors.Wrap(err, "ssh key")
+	}
+	if _, err := ssh.ParsePrivateKey(data); err != nil {
+		return errors.Wrapf(err, "ssh key %s (keys with a passphrase are not supported)", key)
+	}
+	if !d.reachable() {
+		return fmt.Errorf("unable to reach %s", net.JoinHostPort(d.IPAddress, strconv.Itoa(d.SSHPort)))
+	}
+	return nil
+}
+
+// Create imports the SSH key of the machine
+func (d *Driver) Create() error {
+	key, err := d.sshKey()
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Importing SSH key %s ...", key)
+	d.SSHKeyPath = d.ResolveStorePath(path.Base(key))
+	if err := copySSHKey(key, d.SSHKeyPath); err != nil {
+		return err
+	}
+	if err := copySSHKey(key+".pub", d.SSHKeyPath+".pub"); err != nil {
+		klog.Infof("unable to copy the SSH public key: %v", err)
+	}
+	return nil
+}
+
+// GetURL returns a Docker URL inside this host
+func (d *Driver) GetURL() (string, error) {
+	if err := drivers.MustBeRunning(d); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(d.IPAddress, strconv.Itoa(d.EnginePort))), nil
+}
+
+// GetState returns the state of the cluster on the machine: stopped if the machine is unreachable or minikube stopped it
+func (d *Driver) GetState() (state.State, error) {
+	if !d.reachable() {
+		return state.Stopped, nil
+	}
+	if _, err := d.exec.RunCmd(exec.Command("test", "-f", stoppedMarker)); err == nil {
+		return state.Stopped, nil
+	}
+	return state.Running, nil
+}
+
+// Start marks the machine as running. Its containers are started by the bootstrapper.
+func (d *Driver) Start() error {
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "rm", "-f", stoppedMarker)); err != nil {
+		return errors.Wrap(err, "unmark stopped")
+	}
+	return nil
+}
+
+// Stop stops the kubelet and containers of the machine gracefully, leaving the machine running
+func (d *Driver) Stop() error {
+	if err := sysinit.New(d.exec).Stop("kubelet"); err != nil {
+		klog.Warningf("couldn't stop kubelet. will continue with stop anyways: %v", err)
+		if err := sysinit.New(d.exec).ForceStop("kubelet"); err != nil {
+			klog.Warningf("couldn't force stop kubelet. will continue with stop anyways: %v", err)
+		}
+	}
+	cr, err := d.runtime()
+	if err != nil {
+		return err
+	}
+	containers, err := cr.ListContainers(cruntime.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "containers")
+	}
+	if len(containers) > 0 {
+		if err := cr.StopContainers(containers); err != nil {
+			return errors.Wrap(err, "stop containers")
+		}
+	}
+	return d.markStopped()
+}
+
+// Kill stops the kubelet and containers of the machine forcefully, leaving the machine running
+func (d *Driver) Kill() error {
+	if err := sysinit.New(d.exec).ForceStop("kubelet"); err != nil {
+		klog.Warningf("couldn't force stop kubelet. will continue with kill anyways: %v", err)
+	}
+	cr, err := d.runtime()
+	if err != nil {
+		return err
+	}
+	containers, err := cr.ListContainers(cruntime.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "containers")
+	}
+	if len(containers) > 0 {
+		if err := cr.KillContainers(containers); err != nil {
+			return errors.Wrap(err, "kill")
+		}
+	}
+	return d.markStopped()
+}
+
+// Restart restarts the kubelet of the machine
+func (d *Driver) Restart() error {
+	if err := d.Start(); err != nil {
+		return err
+	}
+	return sysinit.New(d.exec).Restart("kubelet")
+}
+
+// Remove removes the data written by minikube from the machine, which is left running.
+// Unreachable machines are skipped, so that clusters of machines which are gone can be deleted.
+func (d *Driver) Remove() error {
+	if !d.reachable() {
+		klog.Warningf("%s is unreachable, leaving its data in place", d.IPAddress)
+		return nil
+	}
+	if err := d.Kill(); err != nil {
+		klog.Warningf("kill failed, will continue with removal anyways: %v", err)
+	}
+	klog.Infof("Removing: %s", cleanupPaths)
+	args := append([]string{"rm", "-rf"}, cleanupPaths...)
+	if _, err := d.exec.RunCmd(exec.Command("sudo", args...)); err != nil {
+		klog.Errorf("cleanup incomplete: %v", err)
+	}
+	return nil
+}
+
+// reachable returns whether the SSH port of the machine answers
+func (d *Driver) reachable() bool {
+	address := net.JoinHostPort(d.IPAddress, strconv.Itoa(d.SSHPort))
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		klog.Infof("%s is unreachable: %v", address, err)
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// sshKey returns the given SSH key, or else the first of the default keys which exists
+func (d *Driver) sshKey() (string, error) {
+	if d.SSHKey != "" {
+		return d.SSHKey, nil
+	}
+	dir := filepath.Join(homedir.HomeDir(), ".ssh")
+	for _, name := range defaultSSHKeys {
+		key := filepath.Join(dir, name)
+		if _, err := os.Stat(key); err == nil {
+			klog.Infof("No SSH key specified, using %s", key)
+			return key, nil
+		}
+	}
+	return "", errors.Errorf("no SSH key was given with --ssh-key, and none of %v was found in %s", defaultSSHKeys, dir)
+}
+
+// markStopped marks the cluster on the machine as stopped
+func (d *Driver) markStopped() error {
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "mkdir", "-p", vmpath.GuestPersistentDir)); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "touch", stoppedMarker)); err != nil {
+		return errors.Wrap(err, "mark stopped")
+	}
+	return nil
+}
+
+// runtime returns the container runtime of the machine
+func (d *Driver) runtime() (cruntime.Manager, error) {
+	cr, err := cruntime.New(cruntime.Config{Type: d.ContainerRuntime, Socket: d.CRISocket, Runner: d.exec})
+	if err != nil {
+		return nil, errors.Wrap(err, "runtime")
+	}
+	return cr, nil
+}
+
+// copySSHKey copies an SSH key, restricting its permissions
+func copySSHKey(src, dst string) error {
+	if err := mcnutils.CopyFile(src, dst); err != nil {
+		return errors.Wrap(err, "copy ssh key")
+	}
+	if err := os.Chmod(dst, 0600); err != nil {
+		return errors.Wrap(err, "chmod ssh key")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKey writes a private key, in the format of ssh-keygen, to path
+func writeKey(t *testing.T, path string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+}
+
+func TestCreateWithoutKey(t *testing.T) {
+	home, err := ioutil.TempDir("", "home")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	store, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(store)
+
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	if err := os.Setenv("HOME", home); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+
+	// the machine directory is created by libmachine before Create
+	if err := os.MkdirAll(filepath.Join(store, "machines", "m02"), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	d := NewDriver(Config{MachineName: "m02", StorePath: store})
+	if err := d.Create(); err == nil {
+		t.Errorf("Create() without a key nor default keys succeeded, expected an error")
+	}
+	if err := d.PreCreateCheck(); err == nil {
+		t.Errorf("PreCreateCheck() without a key nor default keys succeeded, expected an error")
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeKey(t, filepath.Join(home, ".ssh", "id_ecdsa"))
+	if err := d.Create(); err != nil {
+		t.Fatalf("Create() with a default key: %v", err)
+	}
+	expected := filepath.Join(store, "machines", "m02", "id_ecdsa")
+	if got := d.GetSSHKeyPath(); got != expected {
+		t.Errorf("GetSSHKeyPath() = %q, expected the imported default key %q", got, expected)
+	}
+	if _, err := os.Stat(expected); err != nil {
+		t.Errorf("default key was not imported: %v", err)
+	}
+}
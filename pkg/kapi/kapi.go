@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strings"
 	"time"
 
 	apps "k8s.io/api/apps/v1"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/drain"
 	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/vmpath"
@@ -211,3 +213,83 @@ func IsRetryableAPIError(err error) bool {
 func KubectlBinaryPath(version string) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", version, "kubectl")
 }
+
+// DrainNode cordons a node and evicts its pods, so that they are rescheduled on other nodes.
+// Pods which are not managed by a controller are only deleted if force is set, otherwise the node is left untouched.
+func DrainNode(c kubernetes.Interface, name string, force bool, timeout time.Duration) error {
+	if !force {
+		unmanaged, err := unmanagedPods(c, name)
+		if err != nil {
+			return err
+		}
+		if len(unmanaged) > 0 {
+			return fmt.Errorf("cannot drain node %s without force, pods %s are not managed by a controller", name, strings.Join(unmanaged, ", "))
+		}
+	}
+	d := drainer(c, force, timeout)
+	d.OnPodDeletedOrEvicted = func(pod *core.Pod, usingEviction bool) {
+		klog.Infof("removed pod %s/%s from node %s (eviction: %v)", pod.Namespace, pod.Name, name, usingEviction)
+	}
+	node, err := c.CoreV1().Nodes().Get(name, meta.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get node %s: %v", name, err)
+	}
+	if err := drain.RunCordonOrUncordon(d, node, true); err != nil {
+		return fmt.Errorf("cordon node %s: %v", name, err)
+	}
+	return drain.RunNodeDrain(d, name)
+}
+
+// unmanagedPods returns the running pods of a node which would be lost by draining it, as no controller recreates them
+func unmanagedPods(c kubernetes.Interface, name string) ([]string, error) {
+	options := meta.ListOptions{FieldSelector: fields.Set{"spec.nodeName": name}.AsSelector().String()}
+	pods, err := c.CoreV1().Pods(meta.NamespaceAll).List(options)
+	if err != nil {
+		return nil, fmt.Errorf("list pods of node %s: %v", name, err)
+	}
+	var unmanaged []string
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != name || pod.Status.Phase == core.PodSucceeded || pod.Status.Phase == core.PodFailed {
+			continue
+		}
+		// Mirror pods of static manifests are recreated by the kubelet
+		if _, ok := pod.Annotations[core.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+		if meta.GetControllerOf(&pod) == nil {
+			unmanaged = append(unmanaged, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	return unmanaged, nil
+}
+
+// UncordonNode allows pods to be scheduled on a node again
+func UncordonNode(c kubernetes.Interface, name string) error {
+	node, err := c.CoreV1().Nodes().Get(name, meta.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get node %s: %v", name, err)
+	}
+	return drain.RunCordonOrUncordon(drainer(c, false, 0), node, false)
+}
+
+// drainer returns a helper to drain nodes which ignores the pods of daemon sets, and those using emptyDir volumes
+func drainer(c kubernetes.Interface, force bool, timeout time.Duration) *drain.Helper {
+	return &drain.Helper{
+		Client:              c,
+		Force:               force,
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		DeleteLocalData:     true,
+		Timeout:             timeout,
+		Out:                 klogWriter{},
+		ErrOut:              klogWriter{},
+	}
+}
+
+// klogWriter writes the output of the drain helper to the logs
+type klogWriter struct{}
+
+func (klogWriter) Write(p []byte) (int, error) {
+	klog.Info(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func drainTestClient() *fake.Clientset {
+	controller := true
+	return fake.NewSimpleClientset(
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "m02"}},
+		&core.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: "web-1", Namespace: "default", OwnerReferences: []meta.OwnerReference{{Kind: "ReplicaSet", Name: "web", Controller: &controller}}},
+			Spec:       core.PodSpec{NodeName: "m02"},
+		},
+		&core.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: "standalone", Namespace: "default"},
+			Spec:       core.PodSpec{NodeName: "m02"},
+		},
+	)
+}
+
+func TestDrainNode(t *testing.T) {
+	c := drainTestClient()
+	err := DrainNode(c, "m02", false, 10*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "default/standalone") {
+		t.Fatalf("DrainNode = %v, expected an error naming the pod which is not managed by a controller", err)
+	}
+	if n, _ := c.CoreV1().Nodes().Get("m02", meta.GetOptions{}); n.Spec.Unschedulable {
+		t.Errorf("DrainNode cordoned the node although it failed")
+	}
+	pods, err := c.CoreV1().Pods("default").List(meta.ListOptions{})
+	if err != nil {
+		t.Fatalf("list pods: %v", err)
+	}
+	if len(pods.Items) != 2 {
+		t.Errorf("DrainNode left pods %v, expected it to evict none", pods.Items)
+	}
+
+	if err := DrainNode(c, "m02", true, 10*time.Second); err != nil {
+		t.Fatalf("DrainNode with force: %v", err)
+	}
+	n, err := c.CoreV1().Nodes().Get("m02", meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	if !n.Spec.Unschedulable {
+		t.Errorf("DrainNode did not cordon the node")
+	}
+	pods, err = c.CoreV1().Pods("default").List(meta.ListOptions{})
+	if err != nil {
+		t.Fatalf("list pods: %v", err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("DrainNode with force left pods %v", pods.Items)
+	}
+
+	if err := UncordonNode(c, "m02"); err != nil {
+		t.Fatalf("UncordonNode: %v", err)
+	}
+	if n, _ := c.CoreV1().Nodes().Get("m02", meta.GetOptions{}); n.Spec.Unschedulable {
+		t.Errorf("UncordonNode did not uncordon the node")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+)
+
+// announcer makes the addresses of the services reachable from the network of the node
+type announcer interface {
+	// Announce starts answering for an address
+	Announce(ip net.IP) error
+	// Withdraw stops answering for an address
+	Withdraw(ip net.IP)
+}
+
+const (
+	etherTypeARP = 0x0806
+	arpRequest   = 1
+	arpReply     = 2
+	// arpFrameLen is the length of an ethernet frame carrying an IPv4 ARP packet
+	arpFrameLen = 42
+)
+
+var broadcastMAC = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// arpPacket is an IPv4 over ethernet ARP packet
+type arpPacket struct {
+	op        uint16
+	senderMAC net.HardwareAddr
+	senderIP  net.IP
+	targetMAC net.HardwareAddr
+	targetIP  net.IP
+}
+
+// parseARP parses an ethernet frame carrying an IPv4 ARP packet
+func parseARP(frame []byte) (arpPacket, bool) {
+	if len(frame) < arpFrameLen || binary.BigEndian.Uint16(frame[12:14]) != etherTypeARP {
+		return arpPacket{}, false
+	}
+	a := frame[14:]
+	// ethernet hardware, IPv4 protocol
+	if binary.BigEndian.Uint16(a[0:2]) != 1 || binary.BigEndian.Uint16(a[2:4]) != 0x0800 || a[4] != 6 || a[5] != 4 {
+		return arpPacket{}, false
+	}
+	return arpPacket{
+		op:        binary.BigEndian.Uint16(a[6:8]),
+		senderMAC: net.HardwareAddr(append([]byte(nil), a[8:14]...)),
+		senderIP:  net.IP(append([]byte(nil), a[14:18]...)),
+		targetMAC: net.HardwareAddr(append([]byte(nil), a[18:24]...)),
+		targetIP:  net.IP(append([]byte(nil), a[24:28]...)),
+	}, true
+}
+
+// marshal returns the ethernet frame carrying the packet, sent to dst
+func (p arpPacket) marshal(dst net.HardwareAddr) []byte {
+	b := bytes.NewBuffer(make([]byte, 0, arpFrameLen))
+	b.Write(dst)
+	b.Write(p.senderMAC)
+	_ = binary.Write(b, binary.BigEndian, uint16(etherTypeARP))
+	_ = binary.Write(b, binary.BigEndian, []uint16{1, 0x0800})
+	b.Write([]byte{6, 4})
+	_ = binary.Write(b, binary.BigEndian, p.op)
+	b.Write(p.senderMAC)
+	b.Write(p.senderIP.To4())
+	b.Write(p.targetMAC)
+	b.Write(p.targetIP.To4())
+	return b.Bytes()
+}
+
+// replyTo returns the reply of hw to an ARP request for ip
+func replyTo(req arpPacket, hw net.HardwareAddr, ip net.IP) arpPacket {
+	return arpPacket{op: arpReply, senderMAC: hw, senderIP: ip, targetMAC: req.senderMAC, targetIP: req.senderIP}
+}
+
+// gratuitous returns the ARP packet announcing that ip moved to hw, so that the neighbours update their caches
+func gratuitous(hw net.HardwareAddr, ip net.IP) arpPacket {
+	return arpPacket{op: arpRequest, senderMAC: hw, senderIP: ip, targetMAC: broadcastMAC, targetIP: ip}
+}
+
+// interfaceFor returns the network interface with an address in the same subnet as ip
+func interfaceFor(ip net.IP) (*net.Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for i := range ifaces {
+		addrs, err := ifaces[i].Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if n, ok := a.(*net.IPNet); ok && n.Contains(ip) && len(ifaces[i].HardwareAddr) == 6 {
+				return &ifaces[i], nil
+			}
+		}
+	}
+	return nil, &net.AddrError{Err: "no interface is on the subnet of", Addr: ip.String()}
+}
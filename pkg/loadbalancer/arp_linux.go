@@ -0,0 +1,105 @@
+// +build linux
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// arpResponder answers the ARP requests for the addresses of the services with the address of an interface
+type arpResponder struct {
+	iface *net.Interface
+	fd    int
+
+	mu  sync.Mutex
+	ips map[string]bool
+}
+
+// newARPResponder opens a raw socket on an interface, which needs the NET_RAW capability
+func newARPResponder(iface *net.Interface) (announcer, error) {
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_RAW, int(htons(syscall.ETH_P_ARP)))
+	if err != nil {
+		return nil, errors.Wrap(err, "socket")
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: htons(syscall.ETH_P_ARP), Ifindex: iface.Index}); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrapf(err, "bind %s", iface.Name)
+	}
+	r := &arpResponder{iface: iface, fd: fd, ips: map[string]bool{}}
+	go r.serve()
+	return r, nil
+}
+
+// Announce answers for an address from now on, and tells the neighbours about it
+func (r *arpResponder) Announce(ip net.IP) error {
+	r.mu.Lock()
+	r.ips[ip.String()] = true
+	r.mu.Unlock()
+	return r.send(gratuitous(r.iface.HardwareAddr, ip), broadcastMAC)
+}
+
+// Withdraw stops answering for an address
+func (r *arpResponder) Withdraw(ip net.IP) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.ips, ip.String())
+}
+
+func (r *arpResponder) serve() {
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := syscall.Recvfrom(r.fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			klog.Errorf("reading ARP requests on %s: %v", r.iface.Name, err)
+			return
+		}
+		req, ok := parseARP(buf[:n])
+		if !ok || req.op != arpRequest {
+			continue
+		}
+		r.mu.Lock()
+		ours := r.ips[req.targetIP.String()]
+		r.mu.Unlock()
+		if !ours {
+			continue
+		}
+		klog.V(2).Infof("answering ARP request for %s from %s", req.targetIP, req.senderIP)
+		if err := r.send(replyTo(req, r.iface.HardwareAddr, req.targetIP), req.senderMAC); err != nil {
+			klog.Warningf("answering ARP request for %s: %v", req.targetIP, err)
+		}
+	}
+}
+
+func (r *arpResponder) send(p arpPacket, dst net.HardwareAddr) error {
+	to := &syscall.SockaddrLinklayer{Protocol: htons(syscall.ETH_P_ARP), Ifindex: r.iface.Index, Halen: 6}
+	copy(to.Addr[:], dst)
+	return syscall.Sendto(r.fd, p.marshal(dst), 0, to)
+}
+
+func htons(i uint16) uint16 {
+	return i<<8 | i>>8
+}
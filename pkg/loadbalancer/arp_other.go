@@ -0,0 +1,30 @@
+// +build !linux
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// newARPResponder is only implemented on Linux, where the controller runs
+func newARPResponder(iface *net.Interface) (announcer, error) {
+	return nil, fmt.Errorf("answering ARP requests is not supported on %s", runtime.GOOS)
+}
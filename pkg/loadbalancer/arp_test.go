@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"net"
+	"testing"
+)
+
+func TestARP(t *testing.T) {
+	hw := net.HardwareAddr{0x02, 0x42, 0xc0, 0xa8, 0x31, 0x02}
+	peer := net.HardwareAddr{0x02, 0x42, 0x0a, 0x00, 0x00, 0x01}
+	req := arpPacket{op: arpRequest, senderMAC: peer, senderIP: net.ParseIP("192.168.49.1").To4(), targetMAC: make(net.HardwareAddr, 6), targetIP: net.ParseIP("192.168.49.200").To4()}
+
+	frame := req.marshal(broadcastMAC)
+	if len(frame) != arpFrameLen {
+		t.Fatalf("frame length = %d, want %d", len(frame), arpFrameLen)
+	}
+	got, ok := parseARP(frame)
+	if !ok {
+		t.Fatalf("parseARP(%x) failed", frame)
+	}
+	if got.op != arpRequest || got.senderMAC.String() != peer.String() || !got.senderIP.Equal(req.senderIP) || !got.targetIP.Equal(req.targetIP) {
+		t.Errorf("parseARP() = %+v, want %+v", got, req)
+	}
+
+	reply, ok := parseARP(replyTo(got, hw, got.targetIP).marshal(got.senderMAC))
+	if !ok {
+		t.Fatalf("parseARP() of the reply failed")
+	}
+	if reply.op != arpReply || reply.senderMAC.String() != hw.String() || !reply.senderIP.Equal(req.targetIP) || reply.targetMAC.String() != peer.String() || !reply.targetIP.Equal(req.senderIP) {
+		t.Errorf("reply = %+v", reply)
+	}
+
+	if _, ok := parseARP(frame[:20]); ok {
+		t.Errorf("parseARP() of a truncated frame succeeded")
+	}
+	ip := append([]byte(nil), frame...)
+	ip[12], ip[13] = 0x08, 0x00
+	if _, ok := parseARP(ip); ok {
+		t.Errorf("parseARP() of an IPv4 frame succeeded")
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// resyncPeriod is how often every service is synced again, retrying those which failed
+const resyncPeriod = time.Minute
+
+// Controller assigns the addresses of a pool to the LoadBalancer services of a cluster
+type Controller struct {
+	client    kubernetes.Interface
+	pool      *Pool
+	announcer announcer
+
+	mu sync.Mutex
+}
+
+// NewController returns a controller assigning the addresses of pool, which are announced by a
+func NewController(client kubernetes.Interface, pool *Pool, a announcer) *Controller {
+	return &Controller{client: client, pool: pool, announcer: a}
+}
+
+// Run assigns addresses to the services until stop is closed
+func (c *Controller) Run(stop <-chan struct{}) error {
+	svcs, err := c.client.CoreV1().Services("").List(meta.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "list services")
+	}
+	c.restore(svcs.Items)
+
+	factory := informers.NewSharedInformerFactory(c.client, resyncPeriod)
+	factory.Core().V1().Services().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.handle,
+		UpdateFunc: func(_, obj interface{}) { c.handle(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = t.Obj
+			}
+			if svc, ok := obj.(*core.Service); ok {
+				c.release(serviceKey(svc))
+			}
+		},
+	})
+	factory.Start(stop)
+	<-stop
+	return nil
+}
+
+// restore takes back the addresses assigned to the services before the controller was restarted,
+// so that services started since then are not assigned the same ones
+func (c *Controller) restore(svcs []core.Service) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range svcs {
+		svc := &svcs[i]
+		if svc.Spec.Type != core.ServiceTypeLoadBalancer {
+			continue
+		}
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if !c.pool.Contains(ing.IP) {
+				continue
+			}
+			if _, err := c.pool.Assign(serviceKey(svc), ing.IP); err != nil {
+				klog.Warningf("unable to restore %s of %s: %v", ing.IP, serviceKey(svc), err)
+				continue
+			}
+			c.announce(ing.IP)
+		}
+	}
+}
+
+func (c *Controller) handle(obj interface{}) {
+	svc, ok := obj.(*core.Service)
+	if !ok {
+		return
+	}
+	if err := c.sync(svc); err != nil {
+		klog.Errorf("syncing %s: %v", serviceKey(svc), err)
+	}
+}
+
+// sync assigns an address to a LoadBalancer service, or releases the one of a service which is no longer a LoadBalancer
+func (c *Controller) sync(svc *core.Service) error {
+	key := serviceKey(svc)
+	if svc.Spec.Type != core.ServiceTypeLoadBalancer {
+		ip := c.release(key)
+		if ip == "" || !hasIngress(svc, ip) {
+			return nil
+		}
+		klog.Infof("releasing %s of %s, which is now of type %s", ip, key, svc.Spec.Type)
+		svc = svc.DeepCopy()
+		svc.Status.LoadBalancer.Ingress = nil
+		_, err := c.client.CoreV1().Services(svc.Namespace).UpdateStatus(svc)
+		return err
+	}
+
+	if svc.Spec.LoadBalancerIP != "" && !c.pool.Contains(svc.Spec.LoadBalancerIP) {
+		klog.Infof("skipping %s, which requested %s out of the pool %s", key, svc.Spec.LoadBalancerIP, c.pool)
+		return nil
+	}
+	c.mu.Lock()
+	ip, err := c.pool.Assign(key, svc.Spec.LoadBalancerIP)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.announce(ip)
+
+	if len(svc.Status.LoadBalancer.Ingress) == 1 && hasIngress(svc, ip) {
+		return nil
+	}
+	klog.Infof("assigning %s to %s", ip, key)
+	svc = svc.DeepCopy()
+	svc.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ip}}
+	_, err = c.client.CoreV1().Services(svc.Namespace).UpdateStatus(svc)
+	return err
+}
+
+// release releases the address of a service, returning it
+func (c *Controller) release(key string) string {
+	c.mu.Lock()
+	ip := c.pool.Release(key)
+	c.mu.Unlock()
+	if ip != "" {
+		klog.Infof("released %s of %s", ip, key)
+		c.announcer.Withdraw(net.ParseIP(ip))
+	}
+	return ip
+}
+
+func (c *Controller) announce(ip string) {
+	if err := c.announcer.Announce(net.ParseIP(ip)); err != nil {
+		klog.Warningf("unable to announce %s: %v", ip, err)
+	}
+}
+
+func hasIngress(svc *core.Service, ip string) bool {
+	for _, ing := range svc.Status.LoadBalancer.Ingress {
+		if ing.IP == ip {
+			return true
+		}
+	}
+	return false
+}
+
+func serviceKey(svc *core.Service) string {
+	return svc.Namespace + "/" + svc.Name
+}
+
+// StartController assigns the addresses from startIP to endIP to the LoadBalancer services of the cluster, and
+// answers ARP requests for them on iface. Without iface, the interface on the subnet of the pool is used.
+func StartController(startIP, endIP, iface string) error {
+	klog.Infof("Initializing the minikube load balancer controller...")
+	pool, err := NewPool(startIP, endIP)
+	if err != nil {
+		return err
+	}
+
+	var nic *net.Interface
+	if iface != "" {
+		nic, err = net.InterfaceByName(iface)
+	} else {
+		nic, err = interfaceFor(net.ParseIP(startIP))
+	}
+	if err != nil {
+		return errors.Wrap(err, "interface")
+	}
+	a, err := newARPResponder(nic)
+	if err != nil {
+		return errors.Wrap(err, "arp")
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "client")
+	}
+
+	klog.Infof("Load balancer controller initialized, assigning %s on %s", pool, nic.Name)
+	return NewController(clientset, pool, a).Run(wait.NeverStop)
+}
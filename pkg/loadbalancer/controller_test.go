@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeAnnouncer struct {
+	ips map[string]bool
+}
+
+func (a *fakeAnnouncer) Announce(ip net.IP) error {
+	a.ips[ip.String()] = true
+	return nil
+}
+
+func (a *fakeAnnouncer) Withdraw(ip net.IP) {
+	delete(a.ips, ip.String())
+}
+
+func (a *fakeAnnouncer) announced() []string {
+	ips := []string{}
+	for ip := range a.ips {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	return ips
+}
+
+func service(name string, typ core.ServiceType, ingress ...string) *core.Service {
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       core.ServiceSpec{Type: typ},
+	}
+	for _, ip := range ingress {
+		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, core.LoadBalancerIngress{IP: ip})
+	}
+	return svc
+}
+
+func ingressOf(t *testing.T, c *Controller, name string) []string {
+	svc, err := c.client.CoreV1().Services("default").Get(name, meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get %s: %v", name, err)
+	}
+	ips := []string{}
+	for _, ing := range svc.Status.LoadBalancer.Ingress {
+		ips = append(ips, ing.IP)
+	}
+	return ips
+}
+
+func TestController(t *testing.T) {
+	existing := service("existing", core.ServiceTypeLoadBalancer, "192.168.49.200")
+	web := service("web", core.ServiceTypeLoadBalancer)
+	requested := service("requested", core.ServiceTypeLoadBalancer)
+	requested.Spec.LoadBalancerIP = "192.168.49.205"
+	elsewhere := service("elsewhere", core.ServiceTypeLoadBalancer)
+	elsewhere.Spec.LoadBalancerIP = "10.0.0.1"
+	internal := service("internal", core.ServiceTypeClusterIP)
+
+	client := fake.NewSimpleClientset(existing, web, requested, elsewhere, internal)
+	pool, err := NewPool("192.168.49.200", "192.168.49.209")
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	a := &fakeAnnouncer{ips: map[string]bool{}}
+	c := NewController(client, pool, a)
+
+	c.restore([]core.Service{*existing, *web, *requested, *elsewhere, *internal})
+	for _, svc := range []*core.Service{web, requested, elsewhere, internal, existing} {
+		if err := c.sync(svc); err != nil {
+			t.Errorf("sync(%s): %v", svc.Name, err)
+		}
+	}
+
+	want := map[string][]string{
+		"existing":  {"192.168.49.200"},
+		"web":       {"192.168.49.201"},
+		"requested": {"192.168.49.205"},
+		"elsewhere": {},
+		"internal":  {},
+	}
+	for name, ips := range want {
+		if got := ingressOf(t, c, name); !reflect.DeepEqual(got, ips) {
+			t.Errorf("ingress of %s = %v, want %v", name, got, ips)
+		}
+	}
+	if got, want := a.announced(), []string{"192.168.49.200", "192.168.49.201", "192.168.49.205"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("announced %v, want %v", got, want)
+	}
+
+	// a service which is no longer a LoadBalancer releases its address
+	web, err = client.CoreV1().Services("default").Get("web", meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get web: %v", err)
+	}
+	web.Spec.Type = core.ServiceTypeNodePort
+	if err := c.sync(web); err != nil {
+		t.Errorf("sync(web): %v", err)
+	}
+	if got := ingressOf(t, c, "web"); len(got) != 0 {
+		t.Errorf("ingress of web = %v after it became a NodePort service, want none", got)
+	}
+
+	// deleted services release their address
+	c.release("default/existing")
+	if got, want := a.announced(), []string{"192.168.49.205"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("announced %v after releases, want %v", got, want)
+	}
+	svc, err := client.CoreV1().Services("default").Create(service("new", core.ServiceTypeLoadBalancer))
+	if err != nil {
+		t.Fatalf("create new: %v", err)
+	}
+	if err := c.sync(svc); err != nil {
+		t.Errorf("sync(new): %v", err)
+	}
+	if ip := pool.Assigned("default/new"); ip != "192.168.49.200" {
+		t.Errorf("address of new = %q, want the released 192.168.49.200", ip)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// Pool is a range of IPv4 addresses assigned to LoadBalancer services
+type Pool struct {
+	start, end uint32
+	// assigned maps the addresses to the services they are assigned to, as namespace/name
+	assigned map[uint32]string
+}
+
+// NewPool returns the pool of the addresses from start to end, both included
+func NewPool(start, end string) (*Pool, error) {
+	s, err := ipToInt(start)
+	if err != nil {
+		return nil, err
+	}
+	e, err := ipToInt(end)
+	if err != nil {
+		return nil, err
+	}
+	if e < s {
+		return nil, fmt.Errorf("the pool %s-%s ends before it starts", start, end)
+	}
+	return &Pool{start: s, end: e, assigned: map[uint32]string{}}, nil
+}
+
+// String returns the range of the pool
+func (p *Pool) String() string {
+	return fmt.Sprintf("%s-%s", intToIP(p.start), intToIP(p.end))
+}
+
+// Contains returns whether an address is in the pool
+func (p *Pool) Contains(ip string) bool {
+	i, err := ipToInt(ip)
+	return err == nil && i >= p.start && i <= p.end
+}
+
+// Assigned returns the address assigned to a service, if any
+func (p *Pool) Assigned(key string) string {
+	for i, k := range p.assigned {
+		if k == key {
+			return intToIP(i).String()
+		}
+	}
+	return ""
+}
+
+// Assign assigns an address to a service, preferring the one it requested. The address already assigned to the
+// service is kept.
+func (p *Pool) Assign(key string, requested string) (string, error) {
+	if ip := p.Assigned(key); ip != "" && (requested == "" || requested == ip) {
+		return ip, nil
+	}
+	if requested != "" {
+		if !p.Contains(requested) {
+			return "", fmt.Errorf("%s is not in the pool %s", requested, p)
+		}
+		i, _ := ipToInt(requested)
+		if k, ok := p.assigned[i]; ok && k != key {
+			return "", fmt.Errorf("%s is already assigned to %s", requested, k)
+		}
+		p.Release(key)
+		p.assigned[i] = key
+		return requested, nil
+	}
+	for i := p.start; i <= p.end; i++ {
+		if _, ok := p.assigned[i]; !ok {
+			p.assigned[i] = key
+			return intToIP(i).String(), nil
+		}
+	}
+	return "", fmt.Errorf("all the addresses of the pool %s are assigned", p)
+}
+
+// Release releases the address assigned to a service, returning it
+func (p *Pool) Release(key string) string {
+	for i, k := range p.assigned {
+		if k == key {
+			delete(p.assigned, i)
+			return intToIP(i).String()
+		}
+	}
+	return ""
+}
+
+func ipToInt(s string) (uint32, error) {
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("%q is not an IPv4 address", s)
+	}
+	return binary.BigEndian.Uint32(ip), nil
+}
+
+func intToIP(i uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, i)
+	return ip
+}
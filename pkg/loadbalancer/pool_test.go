@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	if _, err := NewPool("192.168.49.200", "192.168.49.100"); err == nil {
+		t.Errorf("NewPool() of a reversed range succeeded")
+	}
+	if _, err := NewPool("fe80::1", "fe80::2"); err == nil {
+		t.Errorf("NewPool() of an IPv6 range succeeded")
+	}
+
+	p, err := NewPool("192.168.49.200", "192.168.49.202")
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		key         string
+		requested   string
+		want        string
+		wantErr     bool
+	}{
+		{"first address", "default/a", "", "192.168.49.200", false},
+		{"kept address", "default/a", "", "192.168.49.200", false},
+		{"requested address", "default/b", "192.168.49.202", "192.168.49.202", false},
+		{"next free address", "default/c", "", "192.168.49.201", false},
+		{"requested address taken", "default/d", "192.168.49.200", "", true},
+		{"requested address out of the pool", "default/d", "192.168.49.10", "", true},
+		{"pool exhausted", "default/d", "", "", true},
+	}
+	for _, tc := range tests {
+		got, err := p.Assign(tc.key, tc.requested)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: Assign(%q, %q) error = %v, want error %t", tc.description, tc.key, tc.requested, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("%s: Assign(%q, %q) = %q, want %q", tc.description, tc.key, tc.requested, got, tc.want)
+		}
+	}
+
+	if ip := p.Release("default/c"); ip != "192.168.49.201" {
+		t.Errorf("Release() = %q, want 192.168.49.201", ip)
+	}
+	if ip := p.Release("default/c"); ip != "" {
+		t.Errorf("Release() of a released service = %q, want none", ip)
+	}
+	if ip, err := p.Assign("default/d", ""); err != nil || ip != "192.168.49.201" {
+		t.Errorf("Assign() after Release() = %q, %v, want 192.168.49.201", ip, err)
+	}
+	if ip, err := p.Assign("default/a", "192.168.49.201"); err == nil {
+		t.Errorf("Assign() of the address of another service = %q, want error", ip)
+	}
+	// moving to another requested address releases the previous one
+	p.Release("default/b")
+	if ip, err := p.Assign("default/a", "192.168.49.202"); err != nil || ip != "192.168.49.202" {
+		t.Errorf("Assign() of a new requested address = %q, %v, want 192.168.49.202", ip, err)
+	}
+	if ip, err := p.Assign("default/b", ""); err != nil || ip != "192.168.49.200" {
+		t.Errorf("Assign() of the previous address = %q, %v, want 192.168.49.200", ip, err)
+	}
+}
@@ -17,10 +17,18 @@ limitations under the License.
 package assets
 
 import (
+	"encoding/base64"
+	"io/ioutil"
+	"regexp"
 	"runtime"
+	"sort"
 
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 	"k8s.io/minikube/pkg/version"
 )
@@ -58,6 +66,61 @@ func (a *Addon) IsEnabled(cc *config.ClusterConfig) bool {
 	return a.enabled
 }
 
+// IsRequested checks if an Addon is enabled for the cluster, or is to be enabled with --addons
+func (a *Addon) IsRequested(cc *config.ClusterConfig) bool {
+	if a.IsEnabled(cc) {
+		return true
+	}
+	for _, name := range config.AddonList {
+		if name == a.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// imageRe matches the images of the containers in a manifest
+var imageRe = regexp.MustCompile(`(?m)^[\s-]*image:\s*["']?([^"'\s]+)`)
+
+// Images returns the container images an addon deploys, evaluating its templates with data
+func (a *Addon) Images(data interface{}) ([]string, error) {
+	seen := map[string]bool{}
+	for _, asset := range a.Assets {
+		b, err := Asset(asset.GetSourcePath())
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", asset.GetSourcePath())
+		}
+		if asset.IsTemplate() {
+			f, err := asset.Evaluate(data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "evaluate %s", asset.GetSourcePath())
+			}
+			if b, err = ioutil.ReadAll(f); err != nil {
+				return nil, errors.Wrapf(err, "reading %s", asset.GetSourcePath())
+			}
+		}
+		for _, img := range manifestImages(b) {
+			seen[img] = true
+		}
+	}
+
+	var images []string
+	for img := range seen {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// manifestImages returns the images referenced by a manifest
+func manifestImages(b []byte) []string {
+	var images []string
+	for _, m := range imageRe.FindAllSubmatch(b, -1) {
+		images = append(images, string(m[1]))
+	}
+	return images
+}
+
 // Addons is the list of addons
 // TODO: Make dynamically loadable: move this data to a .yaml file within each addon directory
 var Addons = map[string]*Addon{
@@ -400,6 +463,14 @@ var Addons = map[string]*Addon{
 			"0640",
 			true),
 	}, false, "metallb"),
+	"loadbalancer-controller": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/loadbalancer-controller/loadbalancer-controller.yaml.tmpl",
+			vmpath.GuestAddonsDir,
+			"loadbalancer-controller.yaml",
+			"0640",
+			true),
+	}, false, "loadbalancer-controller"),
 	"ambassador": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/ambassador/ambassador-operator-crds.yaml",
@@ -540,6 +611,14 @@ var Addons = map[string]*Addon{
 			"0640",
 			false),
 	}, false, "csi-hostpath-driver"),
+	"dex": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/dex/dex.yaml.tmpl",
+			vmpath.GuestAddonsDir,
+			"dex.yaml",
+			"0640",
+			true),
+	}, false, "dex"),
 }
 
 // GenerateTemplateData generates template data for template assets
@@ -553,20 +632,44 @@ func GenerateTemplateData(cfg config.KubernetesConfig) interface{} {
 		ea = "-" + runtime.GOARCH
 	}
 	opts := struct {
-		Arch                      string
-		ExoticArch                string
-		ImageRepository           string
-		LoadBalancerStartIP       string
-		LoadBalancerEndIP         string
-		StorageProvisionerVersion string
+		Arch                        string
+		ExoticArch                  string
+		ImageRepository             string
+		LoadBalancerStartIP         string
+		LoadBalancerEndIP           string
+		StorageProvisionerVersion   string
+		LoadBalancerControllerImage string
+		OIDCIssuerURL               string
+		OIDCClientID                string
+		OIDCClientSecret            string
+		DexTLSCert                  string
+		DexTLSKey                   string
 	}{
-		Arch:                      a,
-		ExoticArch:                ea,
-		ImageRepository:           cfg.ImageRepository,
-		LoadBalancerStartIP:       cfg.LoadBalancerStartIP,
-		LoadBalancerEndIP:         cfg.LoadBalancerEndIP,
-		StorageProvisionerVersion: version.GetStorageProvisionerVersion(),
+		Arch:                        a,
+		ExoticArch:                  ea,
+		ImageRepository:             cfg.ImageRepository,
+		LoadBalancerStartIP:         cfg.LoadBalancerStartIP,
+		LoadBalancerEndIP:           cfg.LoadBalancerEndIP,
+		StorageProvisionerVersion:   version.GetStorageProvisionerVersion(),
+		LoadBalancerControllerImage: images.LoadBalancerController(cfg.ImageRepository),
+		OIDCIssuerURL:               cfg.Auth.OIDCIssuerURL,
+		OIDCClientID:                cfg.Auth.OIDCClientID,
+		OIDCClientSecret:            cfg.Auth.OIDCClientSecret,
+	}
+	if cfg.Auth.LocalDex {
+		opts.DexTLSCert = base64File(localpath.DexCert(cfg.ClusterName))
+		opts.DexTLSKey = base64File(localpath.DexKey(cfg.ClusterName))
 	}
 
 	return opts
 }
+
+// base64File returns the base64 encoded contents of a file, as in the data of a Secret
+func base64File(path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		klog.Warningf("unable to read %s: %v", path, err)
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
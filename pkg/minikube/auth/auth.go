@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth sets up the authentication modes of the API server, besides client certificates: OIDC, static tokens and webhooks.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// ModeOIDC authenticates users with OpenID Connect ID tokens
+	ModeOIDC = "oidc"
+	// ModeWebhook authenticates bearer tokens with a webhook
+	ModeWebhook = "webhook"
+	// ModeToken authenticates static bearer tokens
+	ModeToken = "token"
+
+	// DexAddon is the addon serving a local OIDC issuer
+	DexAddon = "dex"
+	// DexNodePort is the node port the dex addon is served on
+	DexNodePort = 32000
+	// DexClientSecret is the secret of the client the dex addon is configured with
+	DexClientSecret = "minikube-dex-secret"
+
+	// tokenUser is the user of a generated static token
+	tokenUser = "minikube-token"
+)
+
+// Modes returns the supported authentication modes
+func Modes() []string {
+	return []string{ModeOIDC, ModeWebhook, ModeToken}
+}
+
+// UserName returns the name of the kubeconfig user, and context, of a cluster authenticated by its authentication mode
+func UserName(clusterName string, mode string) string {
+	return clusterName + "-" + mode
+}
+
+// TokenFilePath returns the static token file of a cluster
+func TokenFilePath(k8s config.KubernetesConfig) string {
+	if k8s.Auth.TokenFile != "" {
+		return k8s.Auth.TokenFile
+	}
+	return filepath.Join(localpath.Profile(k8s.ClusterName), "tokens.csv")
+}
+
+// Setup prepares the authentication mode of a cluster whose primary control plane has the given IP:
+// it points OIDC to the dex addon unless another issuer is configured, and generates a static token unless a token file is given.
+func Setup(cc *config.ClusterConfig, cpIP string) error {
+	a := &cc.KubernetesConfig.Auth
+	switch a.Mode {
+	case ModeOIDC:
+		if a.OIDCIssuerURL != "" && !a.LocalDex {
+			return nil
+		}
+		a.LocalDex = true
+		a.OIDCIssuerURL = "https://" + net.JoinHostPort(cpIP, strconv.Itoa(DexNodePort))
+		if a.OIDCClientSecret == "" {
+			a.OIDCClientSecret = DexClientSecret
+		}
+		return dexCert(cc.KubernetesConfig, cpIP)
+	case ModeToken:
+		p := TokenFilePath(cc.KubernetesConfig)
+		if a.TokenFile != "" {
+			_, err := os.Stat(p)
+			return err
+		}
+		if _, err := os.Stat(p); err == nil {
+			return nil
+		}
+		token := a.Token
+		if token == "" {
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				return errors.Wrap(err, "generating token")
+			}
+			token = hex.EncodeToString(b)
+		}
+		klog.Infof("writing static token of %s to %s", tokenUser, p)
+		line := fmt.Sprintf("%s,%s,%s,\"system:masters\"\n", token, tokenUser, tokenUser)
+		return ioutil.WriteFile(p, []byte(line), 0600)
+	}
+	return nil
+}
+
+// dexCert generates the serving certificate of the dex addon, signed by the cluster CA, unless a valid one exists for the IP
+func dexCert(k8s config.KubernetesConfig, ip string) error {
+	certPath := localpath.DexCert(k8s.ClusterName)
+	caCert := bootstrapper.CACertPath(k8s)
+	if err := util.VerifyCert(certPath, caCert, bootstrapper.CertRenewalWindow); err == nil {
+		if c, err := util.ReadCert(certPath); err == nil {
+			for _, certIP := range c.IPAddresses {
+				if certIP.Equal(net.ParseIP(ip)) {
+					return nil
+				}
+			}
+		}
+	}
+	return util.GenerateSignedCert(certPath, localpath.DexKey(k8s.ClusterName), "dex", []net.IP{net.ParseIP(ip)}, []string{"dex.dex.svc"}, caCert, bootstrapper.CAKeyPath(k8s))
+}
+
+// KubeconfigUser returns the kubeconfig user authenticated by the authentication mode of a cluster.
+// It returns nil if there is no such user, as when a webhook is used without a token.
+func KubeconfigUser(k8s config.KubernetesConfig) (*api.AuthInfo, error) {
+	a := k8s.Auth
+	switch a.Mode {
+	case ModeOIDC:
+		// kubelogin, the oidc-login plugin of kubectl, logs in and refreshes the ID token
+		args := []string{
+			"oidc-login", "get-token",
+			"--oidc-issuer-url=" + a.OIDCIssuerURL,
+			"--oidc-client-id=" + a.OIDCClientID,
+		}
+		if a.OIDCClientSecret != "" {
+			args = append(args, "--oidc-client-secret="+a.OIDCClientSecret)
+		}
+		for _, claim := range []string{a.OIDCUsernameClaim, a.OIDCGroupsClaim} {
+			if claim != "" && claim != "sub" {
+				args = append(args, "--oidc-extra-scope="+claim)
+			}
+		}
+		switch {
+		case a.LocalDex:
+			args = append(args, "--certificate-authority="+bootstrapper.CACertPath(k8s))
+		case a.OIDCCAFile != "":
+			args = append(args, "--certificate-authority="+a.OIDCCAFile)
+		}
+		user := api.NewAuthInfo()
+		user.Exec = &api.ExecConfig{APIVersion: "client.authentication.k8s.io/v1beta1", Command: "kubectl", Args: args}
+		return user, nil
+	case ModeToken, ModeWebhook:
+		token := a.Token
+		if token == "" && a.Mode == ModeToken {
+			var err error
+			if token, err = firstToken(TokenFilePath(k8s)); err != nil {
+				return nil, err
+			}
+		}
+		if token == "" {
+			return nil, nil
+		}
+		user := api.NewAuthInfo()
+		user.Token = token
+		return user, nil
+	}
+	return nil, nil
+}
+
+// firstToken returns the token of the first user of a static token file
+func firstToken(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return "", fmt.Errorf("%s has no tokens", path)
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing %s", path)
+		}
+		if len(record) < 3 || strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		return strings.TrimSpace(record[0]), nil
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestSetupToken(t *testing.T) {
+	home, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("MINIKUBE_HOME", os.Getenv("MINIKUBE_HOME"))
+	os.Setenv("MINIKUBE_HOME", home)
+	if err := os.MkdirAll(localpath.Profile("p1"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	cc := &config.ClusterConfig{Name: "p1", KubernetesConfig: config.KubernetesConfig{ClusterName: "p1", Auth: config.AuthConfig{Mode: ModeToken}}}
+	if err := Setup(cc, "192.168.39.2"); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	b, err := ioutil.ReadFile(TokenFilePath(cc.KubernetesConfig))
+	if err != nil {
+		t.Fatalf("reading token file: %v", err)
+	}
+	fields := strings.Split(strings.TrimSpace(string(b)), ",")
+	if len(fields) != 4 || len(fields[0]) != 64 || fields[1] != tokenUser || fields[3] != `"system:masters"` {
+		t.Fatalf("unexpected token file: %q", b)
+	}
+
+	// The token is kept across restarts
+	if err := Setup(cc, "192.168.39.2"); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	user, err := KubeconfigUser(cc.KubernetesConfig)
+	if err != nil {
+		t.Fatalf("KubeconfigUser: %v", err)
+	}
+	if user == nil || user.Token != fields[0] {
+		t.Errorf("KubeconfigUser returned %+v, expected the token %s", user, fields[0])
+	}
+}
+
+func TestKubeconfigUser(t *testing.T) {
+	k8s := config.KubernetesConfig{ClusterName: "p1", Auth: config.AuthConfig{
+		Mode:              ModeOIDC,
+		OIDCIssuerURL:     "https://sso.example.com",
+		OIDCClientID:      "kubernetes",
+		OIDCUsernameClaim: "email",
+		OIDCGroupsClaim:   "groups",
+		OIDCCAFile:        "/home/user/sso-ca.crt",
+	}}
+	user, err := KubeconfigUser(k8s)
+	if err != nil {
+		t.Fatalf("KubeconfigUser: %v", err)
+	}
+	if user.Exec == nil {
+		t.Fatalf("KubeconfigUser returned %+v, expected an exec user", user)
+	}
+	args := strings.Join(user.Exec.Args, " ")
+	for _, want := range []string{"oidc-login get-token", "--oidc-issuer-url=https://sso.example.com", "--oidc-client-id=kubernetes", "--oidc-extra-scope=email", "--oidc-extra-scope=groups", "--certificate-authority=/home/user/sso-ca.crt"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("exec args %q do not contain %q", args, want)
+		}
+	}
+
+	k8s.Auth = config.AuthConfig{Mode: ModeWebhook, WebhookConfigFile: "/home/user/webhook.kubeconfig"}
+	if user, err := KubeconfigUser(k8s); err != nil || user != nil {
+		t.Errorf("KubeconfigUser of a webhook without a token = %+v, %v, expected no user", user, err)
+	}
+	k8s.Auth.Token = "abc"
+	if user, err := KubeconfigUser(k8s); err != nil || user == nil || user.Token != "abc" {
+		t.Errorf("KubeconfigUser of a webhook with a token = %+v, %v, expected the token abc", user, err)
+	}
+}
+
+func TestFirstToken(t *testing.T) {
+	tests := []struct {
+		description string
+		content     string
+		expected    string
+		err         bool
+	}{
+		{"single", "abc,user,uid\n", "abc", false},
+		{"groups", "abc,user,uid,\"group1,group2\"\ndef,other,uid2\n", "abc", false},
+		{"comment", "#token,user,uid\nabc,user,uid\n", "abc", false},
+		{"empty", "", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "tokens")
+			if err != nil {
+				t.Fatalf("tempfile: %v", err)
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.WriteString(test.content); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			f.Close()
+
+			got, err := firstToken(f.Name())
+			if (err != nil) != test.err {
+				t.Fatalf("firstToken returned error %v, expected error: %v", err, test.err)
+			}
+			if got != test.expected {
+				t.Errorf("firstToken = %q, expected %q", got, test.expected)
+			}
+		})
+	}
+}
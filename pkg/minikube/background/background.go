@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package background keeps the state of the minikube processes running in the background, such as tunnels, so that
+// other minikube commands can find and stop them
+package background
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// state holds the fields common to the states of every background process
+type state struct {
+	Pid int
+}
+
+// CheckIfRunning returns whether the process pid runs
+// TODO(balintp): this is vulnerable to pid reuse we should include process name in the check
+var CheckIfRunning = func(pid int) (bool, error) {
+	p, err := os.FindProcess(pid)
+	if runtime.GOOS == "windows" {
+		return err == nil, nil
+	}
+	// on unix systems further checking is required, as findProcess is noop
+	if err != nil {
+		return false, fmt.Errorf("error finding process %d: %s", pid, err)
+	}
+	if err := p.Signal(syscall.Signal(0)); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// SaveState records the state s of a running process in path. s is marshalled to JSON, and must have a Pid field.
+func SaveState(path string, s interface{}) error {
+	bs, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	return ioutil.WriteFile(path, bs, 0o600)
+}
+
+// RemoveState removes the state of a process, once it exits
+func RemoveState(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// LoadState reads the state of a process into s, returning whether the process runs. The state of a process which
+// exited without removing it is removed.
+func LoadState(path string, s interface{}) (bool, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	var st state
+	if err := json.Unmarshal(bs, &st); err != nil {
+		return false, errors.Wrap(err, "unmarshal")
+	}
+
+	running, err := CheckIfRunning(st.Pid)
+	if err != nil {
+		return false, err
+	}
+	if !running {
+		klog.Infof("process %d of %s is no longer running", st.Pid, path)
+		return false, RemoveState(path)
+	}
+	if err := json.Unmarshal(bs, s); err != nil {
+		return false, errors.Wrap(err, "unmarshal")
+	}
+	return true, nil
+}
+
+// Stop stops the running process whose state is in path, waiting up to timeout for it to clean up and exit
+func Stop(path string, timeout time.Duration) error {
+	var st state
+	running, err := LoadState(path, &st)
+	if err != nil || !running {
+		return err
+	}
+	p, err := os.FindProcess(st.Pid)
+	if err != nil {
+		return errors.Wrapf(err, "finding process %d", st.Pid)
+	}
+
+	// Interrupting the process lets it clean up, as if Ctrl-C was pressed, but Windows can only kill processes
+	if runtime.GOOS == "windows" {
+		err = p.Kill()
+	} else {
+		err = p.Signal(os.Interrupt)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "stopping process %d", st.Pid)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		running, err := CheckIfRunning(st.Pid)
+		if err != nil {
+			return err
+		}
+		if !running {
+			return RemoveState(path)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	return errors.Errorf("process %d did not stop within %s", st.Pid, timeout)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package background
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testState struct {
+	Pid  int
+	Name string
+}
+
+func TestState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minikube-background")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "p1", "state.json")
+
+	var st testState
+	if running, err := LoadState(path, &st); err != nil || running {
+		t.Fatalf("LoadState() without a state = %v, %v, want false", running, err)
+	}
+
+	want := testState{Pid: os.Getpid(), Name: "tunnel"}
+	if err := SaveState(path, want); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	if running, err := LoadState(path, &st); err != nil || !running || st != want {
+		t.Fatalf("LoadState() = %+v, %v, %v, want %+v of a running process", st, running, err, want)
+	}
+
+	// The state of a process which exited without removing it is removed
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skipf("unable to run a process: %v", err)
+	}
+	if err := SaveState(path, testState{Pid: exited.Process.Pid}); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	if running, err := LoadState(path, &st); err != nil || running {
+		t.Errorf("LoadState() of an exited process = %v, %v, want false", running, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("state of an exited process was not removed: %v", err)
+	}
+	if err := Stop(path, time.Second); err != nil {
+		t.Errorf("Stop() without a running process: %v", err)
+	}
+}
+
+func TestStop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minikube-background")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	sleep := exec.Command("sleep", "60")
+	if err := sleep.Start(); err != nil {
+		t.Skipf("unable to run a process: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = sleep.Wait()
+		close(exited)
+	}()
+	if err := SaveState(path, testState{Pid: sleep.Process.Pid}); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	// the process is only reaped once waited for, so it still exists until then
+	running := CheckIfRunning
+	defer func() { CheckIfRunning = running }()
+	CheckIfRunning = func(pid int) (bool, error) {
+		select {
+		case <-exited:
+			return false, nil
+		default:
+			return running(pid)
+		}
+	}
+	if err := Stop(path, 10*time.Second); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("state of a stopped process was not removed: %v", err)
+	}
+}
@@ -41,7 +41,10 @@ type Bootstrapper interface {
 	WaitForNode(config.ClusterConfig, config.Node, time.Duration) error
 	JoinCluster(config.ClusterConfig, config.Node, string) error
 	UpdateNode(config.ClusterConfig, config.Node, cruntime.Manager) error
-	GenerateToken(config.ClusterConfig) (string, error)
+	UpgradeNode(config.ClusterConfig, config.Node, cruntime.Manager) error
+	RollbackNode(config.ClusterConfig, config.Node, cruntime.Manager) error
+	RotateCerts(config.ClusterConfig, config.Node, cruntime.Manager) error
+	GenerateToken(config.ClusterConfig, config.Node) (string, error)
 	// LogCommands returns a map of log type to a command which will display that log.
 	LogCommands(config.ClusterConfig, LogOptions) map[string]string
 	SetupCerts(config.KubernetesConfig, config.Node) error
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+const (
+	// AuditPolicyDir is where the audit policy is installed, which is mounted into the API server pod
+	AuditPolicyDir = "/etc/kubernetes/audit"
+	// AuditLogDir is where the API server writes the audit log, which is mounted into the API server pod
+	AuditLogDir = "/var/log/kubernetes/audit"
+)
+
+// AuditLogPath is the audit log of the API server
+var AuditLogPath = path.Join(AuditLogDir, "audit.log")
+
+// auditPresets are the audit policies selectable by name, mapped to the level of requests they log
+var auditPresets = map[string]string{
+	"metadata":        "Metadata",
+	"request":         "Request",
+	"requestresponse": "RequestResponse",
+}
+
+// AuditPresets returns the names of the audit policy presets
+func AuditPresets() []string {
+	return []string{"metadata", "request", "requestresponse"}
+}
+
+// IsAuditPreset returns whether an audit policy is one of the presets, rather than a file
+func IsAuditPreset(policy string) bool {
+	_, ok := auditPresets[policy]
+	return ok
+}
+
+// auditPolicy returns the audit policy which logs requests at the given level.
+// Requests the control plane makes continuously are not logged, and secrets are never logged beyond their metadata.
+func auditPolicy(level string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+  - "RequestReceived"
+rules:
+  - level: None
+    users: ["system:kube-proxy"]
+    verbs: ["watch"]
+  - level: None
+    userGroups: ["system:nodes"]
+    verbs: ["get"]
+  - level: None
+    users: ["system:kube-controller-manager", "system:kube-scheduler", "system:serviceaccount:kube-system:endpoint-controller"]
+    verbs: ["get", "update"]
+    namespaces: ["kube-system"]
+    resources:
+      - group: ""
+        resources: ["endpoints"]
+      - group: "coordination.k8s.io"
+        resources: ["leases"]
+  - level: None
+    nonResourceURLs: ["/healthz*", "/livez*", "/readyz*", "/version"]
+  - level: Metadata
+    resources:
+      - group: ""
+        resources: ["secrets", "configmaps"]
+      - group: "authentication.k8s.io"
+        resources: ["tokenreviews"]
+  - level: %s
+`, level))
+}
+
+// AuditOptions returns the flags of the API server for the audit policy of a cluster
+func AuditOptions(k8s config.KubernetesConfig) config.ExtraOptionSlice {
+	if k8s.AuditPolicy == "" {
+		return nil
+	}
+	opt := func(key, value string) config.ExtraOption {
+		return config.ExtraOption{Component: Apiserver, Key: key, Value: value}
+	}
+	return config.ExtraOptionSlice{
+		opt("audit-policy-file", path.Join(AuditPolicyDir, "policy.yaml")),
+		opt("audit-log-path", AuditLogPath),
+		opt("audit-log-maxsize", "10"),
+		opt("audit-log-maxbackup", "1"),
+	}
+}
+
+// AuditVolumes returns the host paths mounted into the API server pod for the audit policy of a cluster
+func AuditVolumes(k8s config.KubernetesConfig) []ExtraVolume {
+	if k8s.AuditPolicy == "" {
+		return nil
+	}
+	return []ExtraVolume{
+		{Name: "audit-policy", HostPath: AuditPolicyDir, MountPath: AuditPolicyDir, ReadOnly: true, PathType: "DirectoryOrCreate"},
+		{Name: "audit-log", HostPath: AuditLogDir, MountPath: AuditLogDir, PathType: "DirectoryOrCreate"},
+	}
+}
+
+// AuditFiles returns the audit policy referenced by AuditOptions
+func AuditFiles(k8s config.KubernetesConfig) ([]assets.CopyableFile, error) {
+	p := k8s.AuditPolicy
+	if p == "" {
+		return nil, nil
+	}
+	if level, ok := auditPresets[p]; ok {
+		return []assets.CopyableFile{assets.NewMemoryAsset(auditPolicy(level), AuditPolicyDir, "policy.yaml", "0644")}, nil
+	}
+	f, err := assets.NewFileAsset(p, AuditPolicyDir, "policy.yaml", "0644")
+	if err != nil {
+		return nil, errors.Wrapf(err, "audit policy %s", p)
+	}
+	return []assets.CopyableFile{f}, nil
+}
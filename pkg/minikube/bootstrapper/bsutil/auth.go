@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/auth"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// AuthDir is where the files of the authentication mode are copied to, within the certificates directory which is mounted into the API server pod
+var AuthDir = path.Join(vmpath.GuestKubernetesCertsDir, "auth")
+
+// AuthOptions returns the flags of the API server for the authentication mode of a cluster
+func AuthOptions(k8s config.KubernetesConfig) config.ExtraOptionSlice {
+	a := k8s.Auth
+	opt := func(key, value string) config.ExtraOption {
+		return config.ExtraOption{Component: Apiserver, Key: key, Value: value}
+	}
+
+	var opts config.ExtraOptionSlice
+	switch a.Mode {
+	case auth.ModeOIDC:
+		opts = append(opts, opt("oidc-issuer-url", a.OIDCIssuerURL), opt("oidc-client-id", a.OIDCClientID))
+		if a.OIDCUsernameClaim != "" {
+			opts = append(opts, opt("oidc-username-claim", a.OIDCUsernameClaim))
+		}
+		if a.OIDCGroupsClaim != "" {
+			opts = append(opts, opt("oidc-groups-claim", a.OIDCGroupsClaim))
+		}
+		switch {
+		case a.LocalDex:
+			opts = append(opts, opt("oidc-ca-file", path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt")))
+		case a.OIDCCAFile != "":
+			opts = append(opts, opt("oidc-ca-file", path.Join(AuthDir, "oidc-ca.crt")))
+		}
+	case auth.ModeToken:
+		opts = append(opts, opt("token-auth-file", path.Join(AuthDir, "tokens.csv")))
+	case auth.ModeWebhook:
+		opts = append(opts, opt("authentication-token-webhook-config-file", path.Join(AuthDir, "webhook.kubeconfig")))
+	}
+	return opts
+}
+
+// AuthFiles returns the files referenced by AuthOptions
+func AuthFiles(k8s config.KubernetesConfig) ([]assets.CopyableFile, error) {
+	a := k8s.Auth
+	type file struct {
+		src   string
+		name  string
+		perms string
+	}
+
+	var files []file
+	switch a.Mode {
+	case auth.ModeOIDC:
+		// the serving certificate of the dex addon is part of its manifest, as a Secret
+		if !a.LocalDex && a.OIDCCAFile != "" {
+			files = append(files, file{a.OIDCCAFile, "oidc-ca.crt", "0644"})
+		}
+	case auth.ModeToken:
+		files = append(files, file{auth.TokenFilePath(k8s), "tokens.csv", "0600"})
+	case auth.ModeWebhook:
+		files = append(files, file{a.WebhookConfigFile, "webhook.kubeconfig", "0600"})
+	}
+
+	var copyable []assets.CopyableFile
+	for _, f := range files {
+		asset, err := assets.NewFileAsset(f.src, AuthDir, f.name, f.perms)
+		if err != nil {
+			return nil, errors.Wrapf(err, "auth asset %s", f.src)
+		}
+		copyable = append(copyable, asset)
+	}
+	return copyable, nil
+}
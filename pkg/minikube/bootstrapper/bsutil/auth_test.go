@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestAuthOptions(t *testing.T) {
+	tests := []struct {
+		description string
+		auth        config.AuthConfig
+		expected    map[string]string
+	}{
+		{
+			description: "none",
+			expected:    map[string]string{},
+		},
+		{
+			description: "oidc with dex",
+			auth:        config.AuthConfig{Mode: "oidc", OIDCIssuerURL: "https://192.168.39.2:32000", OIDCClientID: "minikube", OIDCUsernameClaim: "email", LocalDex: true},
+			expected: map[string]string{
+				"oidc-issuer-url":     "https://192.168.39.2:32000",
+				"oidc-client-id":      "minikube",
+				"oidc-username-claim": "email",
+				"oidc-ca-file":        "/var/lib/minikube/certs/ca.crt",
+			},
+		},
+		{
+			description: "oidc with an external issuer",
+			auth:        config.AuthConfig{Mode: "oidc", OIDCIssuerURL: "https://sso.example.com", OIDCClientID: "kubernetes", OIDCGroupsClaim: "groups", OIDCCAFile: "/home/user/sso-ca.crt"},
+			expected: map[string]string{
+				"oidc-issuer-url":   "https://sso.example.com",
+				"oidc-client-id":    "kubernetes",
+				"oidc-groups-claim": "groups",
+				"oidc-ca-file":      "/var/lib/minikube/certs/auth/oidc-ca.crt",
+			},
+		},
+		{
+			description: "token",
+			auth:        config.AuthConfig{Mode: "token"},
+			expected:    map[string]string{"token-auth-file": "/var/lib/minikube/certs/auth/tokens.csv"},
+		},
+		{
+			description: "webhook",
+			auth:        config.AuthConfig{Mode: "webhook", WebhookConfigFile: "/home/user/webhook.kubeconfig"},
+			expected:    map[string]string{"authentication-token-webhook-config-file": "/var/lib/minikube/certs/auth/webhook.kubeconfig"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got := map[string]string{}
+			for _, o := range AuthOptions(config.KubernetesConfig{Auth: test.auth}) {
+				if o.Component != Apiserver {
+					t.Errorf("option %s is for %s, expected %s", o.Key, o.Component, Apiserver)
+				}
+				got[o.Key] = o.Value
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("AuthOptions diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -30,6 +30,9 @@ import (
 // KubeadmYamlPath is the path to the kubeadm configuration
 var KubeadmYamlPath = path.Join(vmpath.GuestEphemeralDir, "kubeadm.yaml")
 
+// RuntimeClassesPath is the path to the RuntimeClass objects registered by minikube
+var RuntimeClassesPath = path.Join(vmpath.GuestEphemeralDir, "runtimeclasses.yaml")
+
 const (
 	// KubeletServiceFile is the file for the systemd kubelet.service
 	KubeletServiceFile = "/lib/systemd/system/kubelet.service"
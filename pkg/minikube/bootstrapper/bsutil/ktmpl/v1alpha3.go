@@ -45,7 +45,13 @@ kind: ClusterConfiguration
 {{if .ImageRepository}}imageRepository: {{.ImageRepository}}
 {{end}}{{range .ComponentOptions}}{{.Component}}ExtraArgs:{{range $i, $val := printMapInOrder .ExtraArgs ": " }}
   {{$val}}{{end}}
-{{end -}}
+{{if .ExtraVolumes}}{{.Component}}ExtraVolumes:{{range .ExtraVolumes}}
+  - name: {{.Name}}
+    hostPath: {{.HostPath}}
+    mountPath: {{.MountPath}}
+    writable: {{not .ReadOnly}}
+    pathType: {{.PathType}}{{end}}
+{{end}}{{end -}}
 {{if .FeatureArgs}}featureGates: {{range $i, $val := .FeatureArgs}}
   {{$i}}: {{$val}}{{end}}
 {{end -}}
@@ -66,10 +66,18 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		return nil, errors.Wrap(err, "getting cgroup driver")
 	}
 
-	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, cp)
+	// flags given with --extra-config override those of the authentication mode and audit policy
+	extraOpts := append(AuthOptions(k8s), AuditOptions(k8s)...)
+	extraOpts = append(extraOpts, k8s.ExtraOptions...)
+	componentOpts, err := createExtraComponentConfig(extraOpts, version, componentFeatureArgs, cp, k8s.APIServerHAVIP)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating extra component config for kubeadm")
 	}
+	for i := range componentOpts {
+		if componentOpts[i].Component == componentToKubeadmConfigKey[Apiserver] {
+			componentOpts[i].ExtraVolumes = AuditVolumes(k8s)
+		}
+	}
 
 	cnm, err := cni.New(cc)
 	if err != nil {
@@ -19,8 +19,11 @@ package bsutil
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
@@ -32,6 +35,9 @@ import (
 	"k8s.io/minikube/pkg/util"
 )
 
+// PoolLabel is the label of the nodes of a pool. Kubelets may not set labels of the kubernetes.io and k8s.io namespaces.
+const PoolLabel = "minikube.io/pool"
+
 func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manager) (map[string]string, error) {
 	k8s := mc.KubernetesConfig
 	version, err := util.ParseKubernetesVersion(k8s.KubernetesVersion)
@@ -63,6 +69,21 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 		extraOpts["hostname-override"] = nodeName
 	}
 
+	// Labels and taints are registered by the kubelet when the node joins
+	if labels := nodeLabels(nc); labels != "" {
+		if l, ok := extraOpts["node-labels"]; ok {
+			labels = l + "," + labels
+		}
+		extraOpts["node-labels"] = labels
+	}
+	if len(nc.Taints) > 0 {
+		taints := strings.Join(nc.Taints, ",")
+		if t, ok := extraOpts["register-with-taints"]; ok {
+			taints = t + "," + taints
+		}
+		extraOpts["register-with-taints"] = taints
+	}
+
 	pauseImage := images.Pause(version, k8s.ImageRepository)
 	if _, ok := extraOpts["pod-infra-container-image"]; !ok && k8s.ImageRepository != "" && pauseImage != "" && k8s.ContainerRuntime != remoteContainerRuntime {
 		extraOpts["pod-infra-container-image"] = pauseImage
@@ -81,6 +102,19 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 	return extraOpts, nil
 }
 
+// nodeLabels returns the labels of a node and its pool, as the kubelet expects them
+func nodeLabels(n config.Node) string {
+	var labels []string
+	for k, v := range n.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(labels)
+	if n.Pool != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", PoolLabel, n.Pool))
+	}
+	return strings.Join(labels, ",")
+}
+
 // NewKubeletConfig generates a new systemd unit containing a configured kubelet
 // based on the options present in the KubernetesConfig.
 func NewKubeletConfig(mc config.ClusterConfig, nc config.Node, r cruntime.Manager) ([]byte, error) {
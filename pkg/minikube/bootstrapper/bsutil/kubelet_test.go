@@ -203,3 +203,42 @@ ExecStart=/var/lib/minikube/binaries/v1.18.2/kubelet --authorization-mode=Webhoo
 		})
 	}
 }
+
+func TestExtraKubeletOptsNodePool(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name: "minikube",
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: constants.DefaultKubernetesVersion,
+			ContainerRuntime:  "docker",
+			ExtraOptions:      config.ExtraOptionSlice{{Component: Kubelet, Key: "node-labels", Value: "extra=true"}},
+		},
+	}
+	n := config.Node{
+		IP:     "192.168.1.101",
+		Name:   "m02",
+		Worker: true,
+		Pool:   "gpu",
+		Labels: map[string]string{"zone": "b", "accelerator": "fake"},
+		Taints: []string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"},
+	}
+	cc.Nodes = []config.Node{{IP: "192.168.1.100", Name: "minikube", ControlPlane: true}, n}
+
+	runtime, err := cruntime.New(cruntime.Config{Type: "docker"})
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	opts, err := extraKubeletOpts(cc, n, runtime)
+	if err != nil {
+		t.Fatalf("extraKubeletOpts: %v", err)
+	}
+
+	expected := map[string]string{
+		"node-labels":          "extra=true,accelerator=fake,zone=b,minikube.io/pool=gpu",
+		"register-with-taints": "dedicated=gpu:NoSchedule,spot:PreferNoSchedule",
+	}
+	for k, v := range expected {
+		if opts[k] != v {
+			t.Errorf("--%s = %q, expected %q", k, opts[k], v)
+		}
+	}
+}
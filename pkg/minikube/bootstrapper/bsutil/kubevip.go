@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// KubeVIPManifestPath is where the kube-vip static pod manifest is installed on control plane nodes
+var KubeVIPManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// kubeVIPTmpl runs kube-vip in ARP mode, so that the leader among the control planes answers for the virtual IP
+var kubeVIPTmpl = template.Must(template.New("kubeVIP").Parse(`apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-vip
+  namespace: kube-system
+spec:
+  containers:
+  - name: kube-vip
+    image: {{.Image}}
+    imagePullPolicy: IfNotPresent
+    args:
+    - manager
+    env:
+    - name: vip_arp
+      value: "true"
+    - name: port
+      value: "{{.Port}}"
+    - name: vip_interface
+      value: {{.Interface}}
+    - name: vip_cidr
+      value: "32"
+    - name: cp_enable
+      value: "true"
+    - name: cp_namespace
+      value: kube-system
+    - name: vip_leaderelection
+      value: "true"
+    - name: vip_leaseduration
+      value: "5"
+    - name: vip_renewdeadline
+      value: "3"
+    - name: vip_retryperiod
+      value: "1"
+    - name: address
+      value: {{.VIP}}
+    securityContext:
+      capabilities:
+        add:
+        - NET_ADMIN
+        - NET_RAW
+    volumeMounts:
+    - mountPath: /etc/kubernetes/admin.conf
+      name: kubeconfig
+  hostNetwork: true
+  volumes:
+  - hostPath:
+      path: /etc/kubernetes/admin.conf
+    name: kubeconfig
+`))
+
+// GenerateKubeVIPManifest generates the static pod which advertises the virtual IP of a highly available cluster on iface
+func GenerateKubeVIPManifest(cc config.ClusterConfig, n config.Node, iface string) ([]byte, error) {
+	vip := cc.KubernetesConfig.APIServerHAVIP
+	if vip == "" {
+		return nil, fmt.Errorf("cluster %q has no virtual IP", cc.Name)
+	}
+
+	port := n.Port
+	if port <= 0 {
+		port = constants.APIServerPort
+	}
+
+	opts := struct {
+		Image     string
+		Port      int
+		Interface string
+		VIP       string
+	}{
+		Image:     images.KubeVIP(cc.KubernetesConfig.ImageRepository),
+		Port:      port,
+		Interface: iface,
+		VIP:       vip,
+	}
+
+	var b bytes.Buffer
+	if err := kubeVIPTmpl.Execute(&b, opts); err != nil {
+		return nil, errors.Wrap(err, "template execute")
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestGenerateKubeVIPManifest(t *testing.T) {
+	n := config.Node{IP: "192.168.49.3", Name: "m02", ControlPlane: true}
+
+	if _, err := GenerateKubeVIPManifest(config.ClusterConfig{Name: "minikube"}, n, "eth0"); err == nil {
+		t.Errorf("expected an error for a cluster without a virtual IP")
+	}
+
+	cc := config.ClusterConfig{
+		Name:             "minikube",
+		HA:               true,
+		KubernetesConfig: config.KubernetesConfig{APIServerHAVIP: "192.168.49.254"},
+	}
+	got, err := GenerateKubeVIPManifest(cc, n, "eth1")
+	if err != nil {
+		t.Fatalf("GenerateKubeVIPManifest: %v", err)
+	}
+	for _, want := range []string{
+		"image: ghcr.io/kube-vip/kube-vip:v0.3.7",
+		"- name: address\n      value: 192.168.49.254",
+		"- name: vip_interface\n      value: eth1",
+		"- name: port\n      value: \"8443\"",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("manifest does not contain %q:\n%s", want, got)
+		}
+	}
+}
@@ -58,6 +58,11 @@ func (pc *NodeCondition) NetworkUnavailable() bool {
 	return pc.Type == v1.NodeNetworkUnavailable && pc.Status == v1.ConditionTrue
 }
 
+// Unwanted detects if the condition is disk, memory or PID pressure, or network unavailability
+func (pc *NodeCondition) Unwanted() bool {
+	return pc.DiskPressure() || pc.MemoryPressure() || pc.PIDPressure() || pc.NetworkUnavailable()
+}
+
 const errTextFormat = "node has unwanted condition %q : Reason %q Message: %q"
 
 // ErrMemoryPressure is thrown when there is node memory pressure condition
@@ -150,3 +155,22 @@ func NodePressure(cs *kubernetes.Clientset) error {
 	}
 	return nil
 }
+
+// NodeConditions returns the unwanted conditions of every node, by node name. Unlike NodePressure, it does not retry.
+func NodeConditions(cs kubernetes.Interface) (map[string][]NodeCondition, error) {
+	ns, err := cs.CoreV1().Nodes().List(meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list nodes")
+	}
+
+	conds := map[string][]NodeCondition{}
+	for _, n := range ns.Items {
+		for _, c := range n.Status.Conditions {
+			pc := NodeCondition{Type: c.Type, Status: c.Status, Reason: c.Reason, Message: c.Message}
+			if pc.Unwanted() {
+				conds[n.Name] = append(conds[n.Name], pc)
+			}
+		}
+	}
+	return conds, nil
+}
@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/otiai10/copy"
 	"github.com/pkg/errors"
@@ -46,12 +47,15 @@ import (
 	"k8s.io/minikube/pkg/util"
 )
 
+// CertRenewalWindow is how long before they expire that certificates are renewed
+const CertRenewalWindow = 30 * 24 * time.Hour
+
 // SetupCerts gets the generated credentials required to talk to the APIServer.
 func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node) ([]assets.CopyableFile, error) {
 	localPath := localpath.Profile(k8s.ClusterName)
 	klog.Infof("Setting up %s for IP: %s\n", localPath, n.IP)
 
-	ccs, err := generateSharedCACerts()
+	ccs, err := generateSharedCACerts(k8s)
 	if err != nil {
 		return nil, errors.Wrap(err, "shared CA certs")
 	}
@@ -61,14 +65,20 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node)
 		return nil, errors.Wrap(err, "profile certs")
 	}
 
-	xfer = append(xfer, ccs.caCert)
-	xfer = append(xfer, ccs.caKey)
-	xfer = append(xfer, ccs.proxyCert)
-	xfer = append(xfer, ccs.proxyKey)
+	// the CA may be provided by the user, so is copied under the name kubeadm expects
+	targets := map[string]string{}
+	for _, p := range xfer {
+		targets[p] = filepath.Base(p)
+	}
+	targets[ccs.caCert] = "ca.crt"
+	targets[ccs.caKey] = "ca.key"
+	targets[ccs.proxyCert] = "proxy-client-ca.crt"
+	targets[ccs.proxyKey] = "proxy-client-ca.key"
+	xfer = append(xfer, ccs.caCert, ccs.caKey, ccs.proxyCert, ccs.proxyKey)
 
 	copyableFiles := []assets.CopyableFile{}
 	for _, p := range xfer {
-		cert := filepath.Base(p)
+		cert := targets[p]
 		perms := "0644"
 		if strings.HasSuffix(cert, ".key") {
 			perms = "0600"
@@ -80,7 +90,7 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node)
 		copyableFiles = append(copyableFiles, certFile)
 	}
 
-	caCerts, err := collectCACerts()
+	caCerts, err := collectCACerts(ccs.caCert)
 	if err != nil {
 		return nil, err
 	}
@@ -137,8 +147,24 @@ type CACerts struct {
 	proxyKey  string
 }
 
+// CACertPath returns the path of the CA certificate the certificates of a cluster are signed with
+func CACertPath(k8s config.KubernetesConfig) string {
+	if k8s.CACertPath != "" {
+		return k8s.CACertPath
+	}
+	return localpath.CACert()
+}
+
+// CAKeyPath returns the path of the key of the CA the certificates of a cluster are signed with
+func CAKeyPath(k8s config.KubernetesConfig) string {
+	if k8s.CAKeyPath != "" {
+		return k8s.CAKeyPath
+	}
+	return filepath.Join(localpath.MiniPath(), "ca.key")
+}
+
 // generateSharedCACerts generates CA certs shared among profiles, but only if missing
-func generateSharedCACerts() (CACerts, error) {
+func generateSharedCACerts(k8s config.KubernetesConfig) (CACerts, error) {
 	globalPath := localpath.MiniPath()
 	cc := CACerts{
 		caCert:    localpath.CACert(),
@@ -146,6 +172,14 @@ func generateSharedCACerts() (CACerts, error) {
 		proxyCert: filepath.Join(globalPath, "proxy-client-ca.crt"),
 		proxyKey:  filepath.Join(globalPath, "proxy-client-ca.key"),
 	}
+	if k8s.CACertPath != "" {
+		klog.Infof("using the CA %s", k8s.CACertPath)
+		if err := util.ValidateCA(k8s.CACertPath, k8s.CAKeyPath); err != nil {
+			return cc, errors.Wrap(err, "custom CA")
+		}
+		cc.caCert = k8s.CACertPath
+		cc.caKey = k8s.CAKeyPath
+	}
 
 	caCertSpecs := []struct {
 		certPath string
@@ -165,6 +199,9 @@ func generateSharedCACerts() (CACerts, error) {
 	}
 
 	for _, ca := range caCertSpecs {
+		if ca.certPath == k8s.CACertPath {
+			continue
+		}
 		if canRead(ca.certPath) && canRead(ca.keyPath) {
 			klog.Infof("skipping %s CA generation: %s", ca.subject, ca.keyPath)
 			continue
@@ -197,6 +234,9 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 	apiServerIPs := append(
 		k8s.APIServerIPs,
 		[]net.IP{net.ParseIP(n.IP), serviceIP, net.ParseIP(oci.DefaultBindIPV4), net.ParseIP("10.0.0.1")}...)
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+	}
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
 		apiServerNames,
@@ -265,9 +305,13 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 			kp = kp + "." + spec.hash
 		}
 
-		if canRead(cp) && canRead(kp) {
-			klog.Infof("skipping %s signed cert generation: %s", spec.subject, kp)
-			continue
+		if canRead(kp) {
+			err := util.VerifyCert(cp, spec.caCertPath, CertRenewalWindow)
+			if err == nil {
+				klog.Infof("skipping %s signed cert generation: %s", spec.subject, kp)
+				continue
+			}
+			klog.Infof("regenerating %s signed cert: %v", spec.subject, err)
 		}
 
 		klog.Infof("generating %s signed cert: %s", spec.subject, kp)
@@ -295,6 +339,49 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 	return xfer, nil
 }
 
+// RemoveProfileCerts removes the certificates of a profile signed by the shared CAs, so that SetupCerts generates them again
+func RemoveProfileCerts(clusterName string) error {
+	profilePath := localpath.Profile(clusterName)
+	for _, pattern := range []string{"client.*", "apiserver.*", "proxy-client.*"} {
+		matches, err := filepath.Glob(filepath.Join(profilePath, pattern))
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			klog.Infof("removing %s", m)
+			if err := os.Remove(m); err != nil {
+				return errors.Wrapf(err, "remove %s", m)
+			}
+		}
+	}
+	return nil
+}
+
+// KubeletClientCert returns a new client certificate and key for the kubelet of a node, PEM encoded together as the kubelet keeps them
+func KubeletClientCert(k8s config.KubernetesConfig, nodeName string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "kubelet-client")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "kubelet-client.crt")
+	keyPath := filepath.Join(dir, "kubelet-client.key")
+	if err := util.GenerateClientCert(certPath, keyPath, "system:node:"+nodeName, []string{"system:nodes"}, CACertPath(k8s), CAKeyPath(k8s)); err != nil {
+		return nil, errors.Wrap(err, "generate kubelet client cert")
+	}
+
+	var data []byte
+	for _, p := range []string{certPath, keyPath} {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
 // isValidPEMCertificate checks whether the input file is a valid PEM certificate (with at least one CERTIFICATE block)
 func isValidPEMCertificate(filePath string) (bool, error) {
 	fileBytes, err := ioutil.ReadFile(filePath)
@@ -319,8 +406,8 @@ func isValidPEMCertificate(filePath string) (bool, error) {
 }
 
 // collectCACerts looks up all PEM certificates with .crt or .pem extension in ~/.minikube/certs to copy to the host.
-// minikube root CA is also included but libmachine certificates (ca.pem/cert.pem) are excluded.
-func collectCACerts() (map[string]string, error) {
+// The cluster CA, caCert, is also included but libmachine certificates (ca.pem/cert.pem) are excluded.
+func collectCACerts(caCert string) (map[string]string, error) {
 	localPath := localpath.MiniPath()
 	certFiles := map[string]string{}
 
@@ -369,7 +456,7 @@ func collectCACerts() (map[string]string, error) {
 	}
 
 	// populates minikube CA
-	certFiles[filepath.Join(localPath, "ca.crt")] = path.Join(vmpath.GuestCertAuthDir, "minikubeCA.pem")
+	certFiles[caCert] = path.Join(vmpath.GuestCertAuthDir, "minikubeCA.pem")
 
 	filtered := map[string]string{}
 	for k, v := range certFiles {
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// KubeletPKIDir is where the kubelet keeps its certificates
+const KubeletPKIDir = "/var/lib/kubelet/pki"
+
+// CertExpiry is when a certificate expires
+type CertExpiry struct {
+	Name     string
+	Path     string
+	Subject  string
+	NotAfter time.Time
+}
+
+// controlPlaneCerts are the certificates of control plane nodes, relative to the Kubernetes certs directory
+var controlPlaneCerts = []struct {
+	name string
+	path string
+}{
+	{"apiserver", "apiserver.crt"},
+	{"apiserver-kubelet-client", "apiserver-kubelet-client.crt"},
+	{"apiserver-etcd-client", "apiserver-etcd-client.crt"},
+	{"proxy-client-ca", "proxy-client-ca.crt"},
+	{"proxy-client", "proxy-client.crt"},
+	{"front-proxy-ca", "front-proxy-ca.crt"},
+	{"front-proxy-client", "front-proxy-client.crt"},
+	{"etcd-ca", "etcd/ca.crt"},
+	{"etcd-server", "etcd/server.crt"},
+	{"etcd-peer", "etcd/peer.crt"},
+	{"etcd-healthcheck-client", "etcd/healthcheck-client.crt"},
+}
+
+// NodeCertExpiries returns when the certificates of a node expire. Certificates missing from the node are skipped.
+func NodeCertExpiries(cr command.Runner, n config.Node) ([]CertExpiry, error) {
+	certs := [][2]string{{"ca", path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt")}}
+	if n.ControlPlane {
+		for _, c := range controlPlaneCerts {
+			certs = append(certs, [2]string{c.name, path.Join(vmpath.GuestKubernetesCertsDir, c.path)})
+		}
+	}
+	certs = append(certs,
+		[2]string{"kubelet-client", path.Join(KubeletPKIDir, "kubelet-client-current.pem")},
+		[2]string{"kubelet", path.Join(KubeletPKIDir, "kubelet.crt")},
+	)
+
+	var expiries []CertExpiry
+	for _, c := range certs {
+		rr, err := cr.RunCmd(exec.Command("sudo", "cat", c[1]))
+		if err != nil {
+			klog.Infof("skipping %s: %v", c[1], err)
+			continue
+		}
+		e, err := certExpiry(c[0], c[1], rr.Stdout.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		expiries = append(expiries, e)
+	}
+	return expiries, nil
+}
+
+// HostCertExpiries returns when the certificates kept on the host for a cluster expire
+func HostCertExpiries(k8s config.KubernetesConfig) ([]CertExpiry, error) {
+	certs := [][2]string{
+		{"ca", CACertPath(k8s)},
+		{"client", localpath.ClientCert(k8s.ClusterName)},
+	}
+	var expiries []CertExpiry
+	for _, c := range certs {
+		b, err := ioutil.ReadFile(c[1])
+		if err != nil {
+			return nil, err
+		}
+		e, err := certExpiry(c[0], c[1], b)
+		if err != nil {
+			return nil, err
+		}
+		expiries = append(expiries, e)
+	}
+	return expiries, nil
+}
+
+// certExpiry parses the first certificate of PEM encoded data
+func certExpiry(name, path string, b []byte) (CertExpiry, error) {
+	c, err := util.ParseCert(b)
+	if err != nil {
+		return CertExpiry{}, errors.Wrapf(err, "parsing %s", path)
+	}
+	return CertExpiry{Name: name, Path: path, Subject: c.Subject.CommonName, NotAfter: c.NotAfter}, nil
+}
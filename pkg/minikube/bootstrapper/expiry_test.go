@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestNodeCertExpiries(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	caCert := filepath.Join(tempDir, "ca.crt")
+	caKey := filepath.Join(tempDir, "ca.key")
+	if err := util.GenerateCACert(caCert, caKey, "minikubeCA"); err != nil {
+		t.Fatalf("GenerateCACert: %v", err)
+	}
+	ca, err := ioutil.ReadFile(caCert)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	// the kubelet keeps its client certificate and key in the same file
+	kubelet, err := KubeletClientCert(config.KubernetesConfig{CACertPath: caCert, CAKeyPath: caKey}, "m02")
+	if err != nil {
+		t.Fatalf("KubeletClientCert: %v", err)
+	}
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo cat /var/lib/minikube/certs/ca.crt":                  string(ca),
+		"sudo cat /var/lib/kubelet/pki/kubelet-client-current.pem": string(kubelet),
+	})
+
+	got, err := NodeCertExpiries(f, config.Node{Name: "m02", Worker: true})
+	if err != nil {
+		t.Fatalf("NodeCertExpiries: %v", err)
+	}
+	expected := map[string]string{"ca": "minikubeCA", "kubelet-client": "system:node:m02"}
+	if len(got) != len(expected) {
+		t.Fatalf("NodeCertExpiries returned %d certs, expected %d: %+v", len(got), len(expected), got)
+	}
+	for _, c := range got {
+		if expected[c.Name] != c.Subject {
+			t.Errorf("cert %s has subject %q, expected %q", c.Name, c.Subject, expected[c.Name])
+		}
+		if c.NotAfter.IsZero() {
+			t.Errorf("cert %s has no expiry", c.Name)
+		}
+	}
+}
+
+func TestCertExpiryInvalid(t *testing.T) {
+	if _, err := certExpiry("ca", "/var/lib/minikube/certs/ca.crt", []byte("not a certificate")); err == nil {
+		t.Errorf("certExpiry should fail to parse invalid data")
+	}
+}
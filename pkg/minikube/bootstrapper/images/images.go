@@ -160,6 +160,14 @@ func dashboardMetrics(repo string) string {
 	return path.Join(repo, "metrics-scraper:v1.0.4")
 }
 
+// KubeVIP returns the image which advertises the virtual IP of highly available clusters
+func KubeVIP(repo string) string {
+	if repo == "" {
+		repo = "ghcr.io/kube-vip"
+	}
+	return path.Join(repo, "kube-vip:v0.3.7")
+}
+
 // KindNet returns the image used for kindnet
 func KindNet(repo string) string {
 	if repo == "" {
@@ -167,3 +175,9 @@ func KindNet(repo string) string {
 	}
 	return path.Join(repo, "kindnetd:0.5.4")
 }
+
+// LoadBalancerController returns the image of the loadbalancer-controller addon
+func LoadBalancerController(mirror string) string {
+	// See deploy/addons/loadbalancer-controller/loadbalancer-controller.yaml.tmpl
+	return path.Join(minikubeRepo(mirror), "loadbalancer-controller:"+version.GetLoadBalancerControllerVersion())
+}
@@ -176,7 +176,7 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 	}
 
 	extraFlags := bsutil.CreateFlagsFromExtraArgs(cfg.KubernetesConfig.ExtraOptions)
-	r, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
+	r, err := cruntime.ForCluster(cfg.KubernetesConfig, k.c)
 	if err != nil {
 		return err
 	}
@@ -243,6 +243,10 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "apply cni")
 	}
 
+	if err := k.applyRuntimeClasses(cfg); err != nil {
+		return errors.Wrap(err, "apply runtime classes")
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(3)
 
@@ -300,7 +304,7 @@ func (k *Bootstrapper) applyCNI(cfg config.ClusterConfig) error {
 
 // unpause unpauses any Kubernetes backplane components
 func (k *Bootstrapper) unpause(cfg config.ClusterConfig) error {
-	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
+	cr, err := cruntime.ForCluster(cfg.KubernetesConfig, k.c)
 	if err != nil {
 		return err
 	}
@@ -424,7 +428,7 @@ func (k *Bootstrapper) WaitForNode(cfg config.ClusterConfig, n config.Node, time
 		return nil
 	}
 
-	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
+	cr, err := cruntime.ForCluster(cfg.KubernetesConfig, k.c)
 	if err != nil {
 		return errors.Wrapf(err, "create runtme-manager %s", cfg.KubernetesConfig.ContainerRuntime)
 	}
@@ -558,7 +562,11 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 	}
 
 	// Save the costly tax of reinstalling Kubernetes if the only issue is a missing kube context
-	_, err = kubeconfig.UpdateEndpoint(cfg.Name, hostname, port, kubeconfig.PathFromEnv())
+	kcHostname, _, kcPort, err := driver.APIServerEndpoint(&cfg, &cp, cfg.Driver)
+	if err != nil {
+		return errors.Wrap(err, "api server endpoint")
+	}
+	_, err = kubeconfig.UpdateEndpoint(cfg.Name, kcHostname, kcPort, kubeconfig.PathFromEnv())
 	if err != nil {
 		klog.Warningf("unable to update kubeconfig (cluster will likely require a reset): %v", err)
 	}
@@ -612,7 +620,7 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 		}
 	}
 
-	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
+	cr, err := cruntime.ForCluster(cfg.KubernetesConfig, k.c)
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
@@ -631,6 +639,10 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "apply cni")
 	}
 
+	if err := k.applyRuntimeClasses(cfg); err != nil {
+		return errors.Wrap(err, "apply runtime classes")
+	}
+
 	if err := kverify.WaitForSystemPods(cr, k, cfg, k.c, client, time.Now(), kconst.DefaultControlPlaneTimeout); err != nil {
 		return errors.Wrap(err, "system pods")
 	}
@@ -665,6 +677,13 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 
 	// Join the master by specifying its token
 	joinCmd = fmt.Sprintf("%s --node-name=%s", joinCmd, driver.MachineName(cc, n))
+	if n.ControlPlane {
+		port := n.Port
+		if port <= 0 {
+			port = constants.APIServerPort
+		}
+		joinCmd = fmt.Sprintf("%s --control-plane --apiserver-advertise-address=%s --apiserver-bind-port=%d", joinCmd, n.IP, port)
+	}
 
 	join := func() error {
 		// reset first to clear any possibly existing state
@@ -691,11 +710,78 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		return errors.Wrap(err, "starting kubelet")
 	}
 
+	if n.ControlPlane {
+		if err := k.joinedControlPlane(cc, n); err != nil {
+			return errors.Wrap(err, "control plane")
+		}
+	}
+
+	return nil
+}
+
+// joinedControlPlane finishes setting up a control plane which joined an existing cluster
+func (k *Bootstrapper) joinedControlPlane(cc config.ClusterConfig, n config.Node) error {
+	// Now that its API server is up, the control plane can talk to it rather than to the primary
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(n.IP)); err != nil {
+		return errors.Wrap(err, "host alias")
+	}
+
+	if cc.HA {
+		// kubeadm reset clears the manifests directory, so kube-vip is installed after joining
+		manifest, err := k.kubeVIPManifest(cc, n)
+		if err != nil {
+			return errors.Wrap(err, "kube-vip")
+		}
+		if err := bsutil.CopyFiles(k.c, []assets.CopyableFile{manifest}); err != nil {
+			return errors.Wrap(err, "copy kube-vip")
+		}
+	}
+
+	if n.Worker {
+		// kubeadm taints joining control planes, but minikube control planes are workers too
+		taint := fmt.Sprintf("sudo KUBECONFIG=%s %s taint nodes %s node-role.kubernetes.io/master:NoSchedule-",
+			path.Join(vmpath.GuestPersistentDir, "kubeconfig"), kubectlPath(cc), driver.MachineName(cc, n))
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", taint)); err != nil {
+			klog.Warningf("unable to remove control plane taint: %v", err)
+		}
+	}
 	return nil
 }
 
+// kubeVIPManifest returns the kube-vip static pod for a control plane of a highly available cluster
+func (k *Bootstrapper) kubeVIPManifest(cfg config.ClusterConfig, n config.Node) (assets.CopyableFile, error) {
+	iface, err := k.interfaceFor(n.IP)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding interface for %s", n.IP)
+	}
+	manifest, err := bsutil.GenerateKubeVIPManifest(cfg, n, iface)
+	if err != nil {
+		return nil, err
+	}
+	return assets.NewMemoryAssetTarget(manifest, bsutil.KubeVIPManifestPath, "0600"), nil
+}
+
+// interfaceFor returns the name of the network interface which has the given IPv4 address
+func (k *Bootstrapper) interfaceFor(ip string) (string, error) {
+	rr, err := k.c.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show"))
+	if err != nil {
+		return "", err
+	}
+	// 2: eth0    inet 192.168.49.2/24 brd 192.168.49.255 scope global eth0 ...
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "inet" {
+			continue
+		}
+		if strings.Split(fields[3], "/")[0] == ip {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("no interface has address %s", ip)
+}
+
 // GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
 	// Take that generated token and use it to get a kubeadm join command
 	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
 	r, err := k.c.RunCmd(tokenCmd)
@@ -710,12 +796,213 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 		joinCmd = fmt.Sprintf("%s --cri-socket %s", joinCmd, cc.KubernetesConfig.CRISocket)
 	}
 
+	if n.ControlPlane {
+		key, err := k.uploadCerts(cc)
+		if err != nil {
+			return "", errors.Wrap(err, "uploading certs")
+		}
+		joinCmd = fmt.Sprintf("%s --certificate-key=%s", joinCmd, key)
+	}
+
 	return joinCmd, nil
 }
 
+// uploadCerts uploads the certificates shared by control planes to the cluster, returning the key to decrypt them
+func (k *Bootstrapper) uploadCerts(cc config.ClusterConfig) (string, error) {
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s init phase upload-certs --upload-certs --config %s", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), bsutil.KubeadmYamlPath))
+	rr, err := k.c.RunCmd(c)
+	if err != nil {
+		return "", err
+	}
+
+	// The key is the last line of output
+	lines := strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n")
+	key := strings.TrimSpace(lines[len(lines)-1])
+	if key == "" {
+		return "", fmt.Errorf("no certificate key in output: %s", rr.Output())
+	}
+	return key, nil
+}
+
+// UpgradeNode upgrades the Kubernetes components of a running node to the version of the given cluster config
+func (k *Bootstrapper) UpgradeNode(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
+	start := time.Now()
+	klog.Infof("UpgradeNode: %+v", n)
+	defer func() {
+		klog.Infof("UpgradeNode complete in %s", time.Since(start))
+	}()
+
+	kv := cfg.KubernetesConfig.KubernetesVersion
+	version, err := util.ParseKubernetesVersion(kv)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+
+	// Transferring binaries may stop the kubelet, which keeps running the previous version until the node is upgraded
+	sm := sysinit.New(k.c)
+	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, k.c, sm); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+	if err := sm.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "starting kubelet")
+	}
+
+	cp, err := config.PrimaryControlPlane(&cfg)
+	if err != nil {
+		return errors.Wrap(err, "control plane")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(kv)
+	var cmds []string
+	switch {
+	case n.Name == cp.Name:
+		// The primary control plane upgrades the cluster-wide configuration and components
+		kubeadmCfg, err := bsutil.GenerateKubeadmYAML(cfg, n, r)
+		if err != nil {
+			return errors.Wrap(err, "generating kubeadm cfg")
+		}
+		if err := bsutil.CopyFiles(k.c, []assets.CopyableFile{assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath, "0640")}); err != nil {
+			return errors.Wrap(err, "copy kubeadm cfg")
+		}
+		flags := fmt.Sprintf("--config %s", bsutil.KubeadmYamlPath)
+		if len(version.Pre) > 0 {
+			flags += " --allow-release-candidate-upgrades"
+		}
+		cmds = []string{
+			fmt.Sprintf("%s upgrade plan %s %s", kubeadm, kv, flags),
+			fmt.Sprintf("%s upgrade apply %s %s --yes", kubeadm, kv, flags),
+		}
+	case n.ControlPlane || version.GTE(semver.MustParse("1.15.0")):
+		cmds = []string{fmt.Sprintf("%s upgrade node", kubeadm)}
+	default:
+		cmds = []string{fmt.Sprintf("%s upgrade node config --kubelet-version %s", kubeadm, kv)}
+	}
+	for _, c := range cmds {
+		rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c))
+		if err != nil {
+			return errors.Wrapf(err, "kubeadm upgrade: %s", rr.Output())
+		}
+		klog.Infof("%s:\n%s", c, rr.Stdout.String())
+	}
+
+	// The kubelet must not be newer than the API server, so it is only upgraded now
+	if err := k.UpdateNode(cfg, n, r); err != nil {
+		return errors.Wrap(err, "updating node")
+	}
+	return sm.Restart("kubelet")
+}
+
+// RollbackNode returns a node to the version of the given cluster config, after a failed upgrade.
+// The etcd data of control planes is expected to have been restored already.
+func (k *Bootstrapper) RollbackNode(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
+	klog.Infof("RollbackNode: %+v", n)
+	if err := k.UpdateNode(cfg, n, r); err != nil {
+		return errors.Wrap(err, "updating node")
+	}
+
+	if n.ControlPlane {
+		// Regenerate the static pods which the upgrade replaced
+		conf := bsutil.KubeadmYamlPath
+		baseCmd := fmt.Sprintf("%s init", bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion))
+		cmds := []string{
+			fmt.Sprintf("sudo cp %s.new %s", conf, conf),
+			fmt.Sprintf("%s phase control-plane all --config %s", baseCmd, conf),
+			fmt.Sprintf("%s phase etcd local --config %s", baseCmd, conf),
+		}
+		for _, c := range cmds {
+			if rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+				return errors.Wrapf(err, "rollback: %s", rr.Output())
+			}
+		}
+	}
+	return sysinit.New(k.c).Restart("kubelet")
+}
+
+// RotateCerts renews the certificates of a node, and restarts the components which use them.
+// The certificates minikube generates on the host are expected to have been removed beforehand, so that they are generated again.
+func (k *Bootstrapper) RotateCerts(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
+	klog.Infof("RotateCerts: %+v", n)
+	if n.ControlPlane {
+		kubeadmCfg, err := bsutil.GenerateKubeadmYAML(cfg, n, r)
+		if err != nil {
+			return errors.Wrap(err, "generating kubeadm cfg")
+		}
+		if err := bsutil.CopyFiles(k.c, []assets.CopyableFile{assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath, "0640")}); err != nil {
+			return errors.Wrap(err, "copy kubeadm cfg")
+		}
+
+		// certificates renewed by kubeadm which minikube also generates are replaced by SetupCerts
+		certs := "certs"
+		version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+		if err != nil {
+			return errors.Wrap(err, "parsing Kubernetes version")
+		}
+		if version.LT(semver.MustParse("1.20.0")) {
+			certs = "alpha certs"
+		}
+		c := fmt.Sprintf("%s %s renew all --config %s", bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion), certs, bsutil.KubeadmYamlPath)
+		if rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+			return errors.Wrapf(err, "kubeadm certs renew: %s", rr.Output())
+		}
+	}
+
+	if err := k.SetupCerts(cfg.KubernetesConfig, n); err != nil {
+		return errors.Wrap(err, "setting up certs")
+	}
+	if err := k.rotateKubeletCerts(cfg, n); err != nil {
+		return errors.Wrap(err, "kubelet certs")
+	}
+
+	if n.ControlPlane {
+		// the kubelet restarts the static pods of the control plane, which then load their new certificates
+		for _, name := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"} {
+			ids, err := r.ListContainers(cruntime.ListOptions{Name: name, Namespaces: []string{"kube-system"}})
+			if err != nil {
+				return errors.Wrapf(err, "list %s", name)
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := r.StopContainers(ids); err != nil {
+				return errors.Wrapf(err, "stop %s", name)
+			}
+		}
+	}
+	return sysinit.New(k.c).Restart("kubelet")
+}
+
+// rotateKubeletCerts replaces the client certificate of the kubelet with one signed by the cluster CA,
+// and removes its self-signed serving certificate, which the kubelet generates again on restart.
+func (k *Bootstrapper) rotateKubeletCerts(cfg config.ClusterConfig, n config.Node) error {
+	kubeletConf := "/etc/kubernetes/kubelet.conf"
+	current := path.Join(bootstrapper.KubeletPKIDir, "kubelet-client-current.pem")
+	if _, err := k.c.RunCmd(exec.Command("sudo", "grep", "-q", current, kubeletConf)); err != nil {
+		// older versions of kubeadm embed the client certificate within the kubeconfig of the kubelet
+		klog.Warningf("%s does not use %s, not rotating the kubelet client certificate: %v", kubeletConf, current, err)
+	} else {
+		cert, err := bootstrapper.KubeletClientCert(cfg.KubernetesConfig, bsutil.KubeNodeName(cfg, n))
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("kubelet-client-%s.pem", time.Now().Format("2006-01-02-15-04-05"))
+		if err := k.c.Copy(assets.NewMemoryAsset(cert, bootstrapper.KubeletPKIDir, name, "0600")); err != nil {
+			return errors.Wrap(err, "copy kubelet client cert")
+		}
+		if rr, err := k.c.RunCmd(exec.Command("sudo", "ln", "-fs", path.Join(bootstrapper.KubeletPKIDir, name), current)); err != nil {
+			return errors.Wrapf(err, "link kubelet client cert: %s", rr.Output())
+		}
+	}
+
+	serving := []string{path.Join(bootstrapper.KubeletPKIDir, "kubelet.crt"), path.Join(bootstrapper.KubeletPKIDir, "kubelet.key")}
+	if rr, err := k.c.RunCmd(exec.Command("sudo", append([]string{"rm", "-f"}, serving...)...)); err != nil {
+		return errors.Wrapf(err, "remove kubelet serving cert: %s", rr.Output())
+	}
+	return nil
+}
+
 // DeleteCluster removes the components that were started earlier
 func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
-	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
+	cr, err := cruntime.ForCluster(k8s, k.c)
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
@@ -766,15 +1053,20 @@ func (k *Bootstrapper) SetupCerts(k8s config.KubernetesConfig, n config.Node) er
 
 // UpdateCluster updates the control plane with cluster-level info.
 func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
-	images, err := images.Kubeadm(cfg.KubernetesConfig.ImageRepository, cfg.KubernetesConfig.KubernetesVersion)
+	imgs, err := images.Kubeadm(cfg.KubernetesConfig.ImageRepository, cfg.KubernetesConfig.KubernetesVersion)
 	if err != nil {
 		return errors.Wrap(err, "kubeadm images")
 	}
+	if cfg.HA {
+		// kube-vip is not preloaded
+		imgs = append(imgs, images.KubeVIP(cfg.KubernetesConfig.ImageRepository))
+	}
+	if cfg.KubernetesConfig.LoadBalancerStartIP != "" && assets.Addons["loadbalancer-controller"].IsRequested(&cfg) {
+		// the image of the loadbalancer-controller addon is not preloaded either
+		imgs = append(imgs, images.LoadBalancerController(cfg.KubernetesConfig.ImageRepository))
+	}
 
-	r, err := cruntime.New(cruntime.Config{
-		Type:   cfg.KubernetesConfig.ContainerRuntime,
-		Runner: k.c, Socket: cfg.KubernetesConfig.CRISocket,
-	})
+	r, err := cruntime.ForCluster(cfg.KubernetesConfig, k.c)
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
@@ -784,7 +1076,7 @@ func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
 	}
 
 	if cfg.KubernetesConfig.ShouldLoadCachedImages {
-		if err := machine.LoadImages(&cfg, k.c, images, constants.ImageCacheDir); err != nil {
+		if err := machine.LoadImages(&cfg, k.c, imgs, constants.ImageCacheDir); err != nil {
 			out.FailureT("Unable to load cached images: {{.error}}", out.V{"error": err})
 		}
 	}
@@ -834,6 +1126,16 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 
 	if n.ControlPlane {
 		files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath+".new", "0640"))
+		authFiles, err := bsutil.AuthFiles(cfg.KubernetesConfig)
+		if err != nil {
+			return errors.Wrap(err, "auth files")
+		}
+		files = append(files, authFiles...)
+		auditFiles, err := bsutil.AuditFiles(cfg.KubernetesConfig)
+		if err != nil {
+			return errors.Wrap(err, "audit files")
+		}
+		files = append(files, auditFiles...)
 	}
 
 	// Installs compatibility shims for non-systemd environments
@@ -844,22 +1146,66 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 	}
 	files = append(files, shims...)
 
-	if err := bsutil.CopyFiles(k.c, files); err != nil {
-		return errors.Wrap(err, "copy")
-	}
-
 	cp, err := config.PrimaryControlPlane(&cfg)
 	if err != nil {
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	// Joining control planes keep using the primary until they are joined, see joinedControlPlane
+	aliasIP := cp.IP
+	if vip := cfg.KubernetesConfig.APIServerHAVIP; cfg.HA && vip != "" {
+		switch {
+		case n.Name == cp.Name:
+			manifest, err := k.kubeVIPManifest(cfg, n)
+			if err != nil {
+				return errors.Wrap(err, "kube-vip")
+			}
+			files = append(files, manifest)
+		case !n.ControlPlane:
+			aliasIP = vip
+		}
+	}
+
+	if err := bsutil.CopyFiles(k.c, files); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(aliasIP)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
 	return nil
 }
 
+// applyRuntimeClasses registers the runtime classes which pods can select with runtimeClassName
+func (k *Bootstrapper) applyRuntimeClasses(cfg config.ClusterConfig) error {
+	classes := cfg.KubernetesConfig.RuntimeClasses
+	if len(classes) == 0 {
+		return nil
+	}
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+	manifest, err := cruntime.RuntimeClassManifest(classes, version)
+	if err != nil {
+		return err
+	}
+	f := assets.NewMemoryAssetTarget(manifest, bsutil.RuntimeClassesPath, "0644")
+	if err := k.c.Copy(f); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeoutSeconds*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sudo", kubectlPath(cfg), "apply", "-f", bsutil.RuntimeClassesPath,
+		fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig")))
+	if rr, err := k.c.RunCmd(cmd); err != nil {
+		return errors.Wrapf(err, "cmd: %s output: %s", rr.Command(), rr.Output())
+	}
+	return nil
+}
+
 // kubectlPath returns the path to the kubelet
 func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
@@ -943,7 +1289,7 @@ func (k *Bootstrapper) elevateKubeSystemPrivileges(cfg config.ClusterConfig) err
 // stopKubeSystem stops all the containers in the kube-system to prevent #8740 when doing hot upgrade
 func (k *Bootstrapper) stopKubeSystem(cfg config.ClusterConfig) error {
 	klog.Info("stopping kube-system containers ...")
-	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
+	cr, err := cruntime.ForCluster(cfg.KubernetesConfig, k.c)
 	if err != nil {
 		return errors.Wrap(err, "new cruntime")
 	}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle creates and imports signed archives of everything minikube downloads to start a cluster,
+// so that clusters can be started on machines without network access.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// FormatVersion is the version of the bundle format
+	FormatVersion = 1
+
+	manifestName  = "manifest.json"
+	signatureName = "manifest.sig"
+	filesDir      = "files"
+)
+
+// File is a file within a bundle
+type File struct {
+	// Path is slash separated, and relative to the minikube home directory
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes the contents of a bundle. It is signed, and lists the checksum of every file.
+type Manifest struct {
+	FormatVersion     int       `json:"formatVersion"`
+	MinikubeVersion   string    `json:"minikubeVersion"`
+	KubernetesVersion string    `json:"kubernetesVersion"`
+	Driver            string    `json:"driver"`
+	ContainerRuntime  string    `json:"containerRuntime"`
+	Arch              string    `json:"arch"`
+	Created           time.Time `json:"created"`
+	// Images are cached images to load into the nodes of a cluster on start, such as the images of addons
+	Images []string `json:"images,omitempty"`
+	Files  []File   `json:"files"`
+}
+
+// Write archives files, given as slash separated paths within root, to w as a bundle signed with key
+func Write(w io.Writer, m Manifest, root string, paths []string, key ed25519.PrivateKey) error {
+	m.Files = nil
+	for _, p := range paths {
+		if err := validPath(p); err != nil {
+			return err
+		}
+		f, err := checksum(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			return errors.Wrapf(err, "checksum of %s", p)
+		}
+		f.Path = p
+		m.Files = append(m.Files, f)
+	}
+
+	mb, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal manifest")
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	if err := writeEntry(tw, manifestName, 0644, mb); err != nil {
+		return err
+	}
+	if err := writeEntry(tw, signatureName, 0644, ed25519.Sign(key, mb)); err != nil {
+		return err
+	}
+	for _, f := range m.Files {
+		klog.Infof("bundling %s (%d bytes)", f.Path, f.Size)
+		if err := writeFile(tw, root, f); err != nil {
+			return errors.Wrapf(err, "bundling %s", f.Path)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "closing archive")
+	}
+	return gw.Close()
+}
+
+// Import verifies a bundle signed with the key of pub, and extracts its files to root.
+// No file is written unless the manifest is signed by the key, and each file is only kept if its checksum matches the manifest.
+func Import(r io.Reader, root string, pub ed25519.PublicKey) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "not a bundle")
+	}
+	tr := tar.NewReader(gr)
+
+	mb, err := readEntry(tr, manifestName)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := readEntry(tr, signatureName)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(pub, mb, sig) {
+		return nil, fmt.Errorf("the signature of the bundle does not match the public key")
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(mb, &m); err != nil {
+		return nil, errors.Wrap(err, "parsing manifest")
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d, expected %d", m.FormatVersion, FormatVersion)
+	}
+
+	pending := map[string]File{}
+	for _, f := range m.Files {
+		if err := validPath(f.Path); err != nil {
+			return nil, err
+		}
+		pending[path.Join(filesDir, f.Path)] = f
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading bundle")
+		}
+		f, ok := pending[hdr.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not listed in the manifest of the bundle", hdr.Name)
+		}
+		delete(pending, hdr.Name)
+
+		klog.Infof("importing %s (%d bytes)", f.Path, f.Size)
+		if err := extractFile(tr, root, f, os.FileMode(hdr.Mode).Perm()); err != nil {
+			return nil, errors.Wrapf(err, "importing %s", f.Path)
+		}
+	}
+
+	for name := range pending {
+		return nil, fmt.Errorf("%s is missing from the bundle", name)
+	}
+	return &m, nil
+}
+
+// validPath returns an error unless p is a slash separated path within a directory
+func validPath(p string) error {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, `\`) || path.Clean(p) != p || strings.HasPrefix(p, "../") || p == ".." {
+		return fmt.Errorf("invalid path in bundle: %q", p)
+	}
+	return nil
+}
+
+// checksum returns the size and SHA-256 checksum of a file
+func checksum(p string) (File, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// writeEntry writes an in-memory file to an archive
+func writeEntry(tw *tar.Writer, name string, mode int64, b []byte) error {
+	hdr := &tar.Header{Name: name, Mode: mode, Size: int64(len(b)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	_, err := tw.Write(b)
+	return errors.Wrapf(err, "writing %s", name)
+}
+
+// writeFile writes a file within root to an archive, failing if it changed since its checksum was taken
+func writeFile(tw *tar.Writer, root string, bf File) error {
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(bf.Path)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() != bf.Size {
+		return fmt.Errorf("size changed from %d to %d bytes", bf.Size, fi.Size())
+	}
+	hdr := &tar.Header{Name: path.Join(filesDir, bf.Path), Mode: int64(fi.Mode().Perm()), Size: bf.Size, ModTime: fi.ModTime(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// readEntry reads the next entry of an archive, which must be named name
+func readEntry(tr *tar.Reader, name string) ([]byte, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", name)
+	}
+	if hdr.Name != name {
+		return nil, fmt.Errorf("expected %s in the bundle, found %s", name, hdr.Name)
+	}
+	return ioutil.ReadAll(tr)
+}
+
+// extractFile extracts a file to root, replacing any existing file only once its checksum is verified
+func extractFile(r io.Reader, root string, bf File, mode os.FileMode) error {
+	dst := filepath.Join(root, filepath.FromSlash(bf.Path))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// If we left behind a temp file, remove it.
+		if _, err := os.Stat(tmp.Name()); err == nil {
+			if err := os.Remove(tmp.Name()); err != nil {
+				klog.Warningf("failed to clean up the temp file %s: %v", tmp.Name(), err)
+			}
+		}
+	}()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); n != bf.Size || sum != bf.SHA256 {
+		return fmt.Errorf("checksum mismatch: got %s (%d bytes), expected %s (%d bytes)", sum, n, bf.SHA256, bf.Size)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeTestBundle bundles a few files, returning the bundle and the public key it is signed with
+func writeTestBundle(t *testing.T) ([]byte, ed25519.PublicKey) {
+	t.Helper()
+	src, err := ioutil.TempDir("", "bundle-src")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(src)
+
+	files := map[string]string{
+		"cache/iso/minikube-v1.15.0.iso":    "iso",
+		"cache/linux/v1.19.2/kubelet":       "kubelet",
+		"cache/images/k8s.gcr.io/pause_3.2": "pause",
+	}
+	var paths []string
+	for p, content := range files {
+		dst := filepath.Join(src, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(dst, []byte(content), 0755); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		paths = append(paths, p)
+	}
+
+	key, err := LoadOrCreateKey(filepath.Join(src, "certs", "bundle.key"))
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey: %v", err)
+	}
+	pub, err := ReadPublicKey(filepath.Join(src, "certs", "bundle.key.pub"))
+	if err != nil {
+		t.Fatalf("ReadPublicKey: %v", err)
+	}
+
+	var b bytes.Buffer
+	m := Manifest{FormatVersion: FormatVersion, KubernetesVersion: "v1.19.2", Driver: "kvm2", ContainerRuntime: "docker", Images: []string{"k8s.gcr.io/pause:3.2"}}
+	if err := Write(&b, m, src, paths, key); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return b.Bytes(), pub
+}
+
+func TestWriteImport(t *testing.T) {
+	b, pub := writeTestBundle(t)
+
+	dst, err := ioutil.TempDir("", "bundle-dst")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	m, err := Import(bytes.NewReader(b), dst, pub)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if diff := cmp.Diff([]string{"k8s.gcr.io/pause:3.2"}, m.Images); diff != "" {
+		t.Errorf("Import images diff (-want +got):\n%s", diff)
+	}
+	if len(m.Files) != 3 {
+		t.Errorf("Import returned %d files, expected 3", len(m.Files))
+	}
+
+	p := filepath.Join(dst, "cache", "linux", "v1.19.2", "kubelet")
+	got, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatalf("reading imported file: %v", err)
+	}
+	if string(got) != "kubelet" {
+		t.Errorf("imported %s = %q, expected %q", p, got, "kubelet")
+	}
+	if fi, err := os.Stat(p); err != nil || fi.Mode().Perm() != 0755 {
+		t.Errorf("imported %s has mode %v (%v), expected 0755", p, fi.Mode(), err)
+	}
+}
+
+func TestImportWrongKey(t *testing.T) {
+	b, _ := writeTestBundle(t)
+	other, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	dst, err := ioutil.TempDir("", "bundle-dst")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	if _, err := Import(bytes.NewReader(b), dst, other); err == nil {
+		t.Fatalf("Import should reject a bundle signed with another key")
+	}
+	if entries, _ := ioutil.ReadDir(dst); len(entries) != 0 {
+		t.Errorf("Import wrote %d entries despite the signature mismatch", len(entries))
+	}
+}
+
+func TestImportTampered(t *testing.T) {
+	b, pub := writeTestBundle(t)
+
+	// Rewrite the bundle, replacing the contents of kubelet
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	var tampered bytes.Buffer
+	gw := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading: %v", err)
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("reading: %v", err)
+		}
+		if hdr.Name == "files/cache/linux/v1.19.2/kubelet" {
+			content = []byte("tampered")
+			hdr.Size = int64(len(content))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("writing: %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("writing: %v", err)
+		}
+	}
+	tw.Close()
+	gw.Close()
+
+	dst, err := ioutil.TempDir("", "bundle-dst")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	if _, err := Import(&tampered, dst, pub); err == nil {
+		t.Fatalf("Import should reject a bundle whose contents do not match their checksums")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "cache", "linux", "v1.19.2", "kubelet")); err == nil {
+		t.Errorf("Import kept a file whose checksum does not match")
+	}
+}
+
+func TestValidPath(t *testing.T) {
+	var tests = []struct {
+		path  string
+		valid bool
+	}{
+		{"cache/iso/minikube-v1.15.0.iso", true},
+		{"cache/images/gcr.io/k8s-minikube/kicbase_v0.0.14", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../.bashrc", false},
+		{"cache/../../.bashrc", false},
+		{"..", false},
+		{`cache\iso`, false},
+		{"cache//iso", false},
+	}
+	for _, tc := range tests {
+		if err := validPath(tc.path); (err == nil) != tc.valid {
+			t.Errorf("validPath(%q) = %v, expected valid: %v", tc.path, err, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+// Options describe the clusters a bundle is able to start
+type Options struct {
+	KubernetesVersion string
+	Driver            string
+	ContainerRuntime  string
+	Addons            []string
+}
+
+// Collect caches everything needed to start a cluster with the given options, downloading what is missing.
+// It returns the manifest of a bundle, and the paths of its files within the minikube home directory.
+func Collect(o Options) (Manifest, []string, error) {
+	m := Manifest{
+		FormatVersion:     FormatVersion,
+		MinikubeVersion:   version.GetVersion(),
+		KubernetesVersion: o.KubernetesVersion,
+		Driver:            o.Driver,
+		ContainerRuntime:  o.ContainerRuntime,
+		Arch:              runtime.GOARCH,
+		Created:           time.Now(),
+	}
+	var files []string
+
+	switch {
+	case driver.IsVM(o.Driver):
+		out.T(style.ISODownload, "Caching the VM boot image ...")
+		u, err := download.ISO(download.DefaultISOURLs(), false)
+		if err != nil {
+			return m, nil, errors.Wrap(err, "ISO")
+		}
+		files = append(files, filepath.FromSlash(strings.TrimPrefix(download.LocalISOResource(u), "file://")))
+	case driver.IsKIC(o.Driver):
+		out.T(style.Pulling, "Caching the base image ...")
+		if err := image.SaveToDir([]string{kic.BaseImage}, constants.ImageCacheDir); err != nil {
+			return m, nil, errors.Wrap(err, "base image")
+		}
+		files = append(files, imageCachePath(kic.BaseImage))
+	}
+
+	out.T(style.FileDownload, "Caching Kubernetes {{.version}} ...", out.V{"version": o.KubernetesVersion})
+	if download.PreloadExists(o.KubernetesVersion, o.ContainerRuntime, true) {
+		if err := download.Preload(o.KubernetesVersion, o.ContainerRuntime, true); err != nil {
+			return m, nil, errors.Wrap(err, "preload")
+		}
+		files = append(files, download.TarballPath(o.KubernetesVersion, o.ContainerRuntime), download.PreloadChecksumPath(o.KubernetesVersion, o.ContainerRuntime))
+	} else {
+		klog.Infof("no preload for %s on %s, caching images", o.KubernetesVersion, o.ContainerRuntime)
+		imgs, err := bootstrapper.GetCachedImageList("", o.KubernetesVersion, bootstrapper.Kubeadm)
+		if err != nil {
+			return m, nil, errors.Wrap(err, "images")
+		}
+		if err := image.SaveToDir(imgs, constants.ImageCacheDir); err != nil {
+			return m, nil, errors.Wrap(err, "caching images")
+		}
+		for _, img := range imgs {
+			files = append(files, imageCachePath(img))
+		}
+	}
+
+	// kube-vip and the loadbalancer-controller addon are not preloaded, but highly available clusters and the addon need them
+	extra := []string{images.KubeVIP(""), images.LoadBalancerController("")}
+	if err := image.SaveToDir(extra, constants.ImageCacheDir); err != nil {
+		return m, nil, errors.Wrap(err, "caching extra images")
+	}
+	for _, img := range extra {
+		files = append(files, imageCachePath(img))
+	}
+
+	if err := machine.CacheBinariesForBootstrapper(o.KubernetesVersion, bootstrapper.Kubeadm); err != nil {
+		return m, nil, errors.Wrap(err, "binaries")
+	}
+	for _, bin := range bootstrapper.GetCachedBinaryList(bootstrapper.Kubeadm) {
+		files = append(files, localpath.MakeMiniPath("cache", "linux", o.KubernetesVersion, bin))
+	}
+	if runtime.GOOS != "linux" {
+		kubectl := "kubectl"
+		if runtime.GOOS == "windows" {
+			kubectl = "kubectl.exe"
+		}
+		p, err := download.Binary(kubectl, o.KubernetesVersion, runtime.GOOS, runtime.GOARCH)
+		if err != nil {
+			return m, nil, errors.Wrap(err, "kubectl")
+		}
+		files = append(files, p)
+	}
+
+	imgs, err := addonImages(o.Addons)
+	if err != nil {
+		return m, nil, err
+	}
+	if len(imgs) > 0 {
+		out.T(style.Pulling, "Caching the images of addons: {{.addons}} ...", out.V{"addons": strings.Join(o.Addons, ", ")})
+		if err := image.SaveToDir(imgs, constants.ImageCacheDir); err != nil {
+			return m, nil, errors.Wrap(err, "caching addon images")
+		}
+		for _, img := range imgs {
+			files = append(files, imageCachePath(img))
+		}
+		m.Images = imgs
+	}
+
+	var paths []string
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return m, nil, errors.Wrapf(err, "missing from the cache")
+		}
+		rel, err := filepath.Rel(localpath.MiniPath(), f)
+		if err != nil {
+			return m, nil, errors.Wrapf(err, "%s is not within %s", f, localpath.MiniPath())
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return m, paths, nil
+}
+
+// addonImages returns the images deployed by addons
+func addonImages(addons []string) ([]string, error) {
+	data := assets.GenerateTemplateData(config.KubernetesConfig{})
+	seen := map[string]bool{}
+	for _, name := range addons {
+		addon, ok := assets.Addons[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not a valid addon", name)
+		}
+		imgs, err := addon.Images(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "images of %s", name)
+		}
+		for _, img := range imgs {
+			seen[img] = true
+		}
+	}
+
+	var imgs []string
+	for img := range seen {
+		imgs = append(imgs, img)
+	}
+	sort.Strings(imgs)
+	return imgs, nil
+}
+
+// imageCachePath returns where an image is cached by image.SaveToDir
+func imageCachePath(img string) string {
+	return localpath.SanitizeCacheDir(filepath.Join(constants.ImageCacheDir, img))
+}
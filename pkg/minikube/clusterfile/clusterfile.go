@@ -0,0 +1,475 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterfile reads, validates and writes declarative cluster definition files
+package clusterfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/auth"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+const (
+	// APIVersion is the version of the cluster definition schema
+	APIVersion = "minikube.sigs.k8s.io/v1alpha1"
+	// Kind is the kind of cluster definitions
+	Kind = "Cluster"
+	// Redacted replaces the secrets of exported definitions, which must be supplied again by flags when the definition is used
+	Redacted = "REDACTED"
+)
+
+// Cluster is a declarative definition of a minikube cluster
+type Cluster struct {
+	APIVersion string   `yaml:"apiVersion" json:"apiVersion"`
+	Kind       string   `yaml:"kind" json:"kind"`
+	Metadata   Metadata `yaml:"metadata" json:"metadata"`
+	Spec       Spec     `yaml:"spec" json:"spec"`
+}
+
+// Metadata identifies a cluster
+type Metadata struct {
+	// Name is the profile name of the cluster
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+// Spec describes the desired state of a cluster. Unset fields keep the defaults of 'minikube start'.
+type Spec struct {
+	Driver             string                       `yaml:"driver,omitempty" json:"driver,omitempty"`
+	KubernetesVersion  string                       `yaml:"kubernetesVersion,omitempty" json:"kubernetesVersion,omitempty"`
+	ContainerRuntime   string                       `yaml:"containerRuntime,omitempty" json:"containerRuntime,omitempty"`
+	CRISocket          string                       `yaml:"criSocket,omitempty" json:"criSocket,omitempty"`
+	RuntimeClasses     map[string]string            `yaml:"runtimeClasses,omitempty" json:"runtimeClasses,omitempty"`
+	CPUs               int                          `yaml:"cpus,omitempty" json:"cpus,omitempty"`
+	Memory             string                       `yaml:"memory,omitempty" json:"memory,omitempty"`
+	DiskSize           string                       `yaml:"diskSize,omitempty" json:"diskSize,omitempty"`
+	Nodes              int                          `yaml:"nodes,omitempty" json:"nodes,omitempty"`
+	HA                 bool                         `yaml:"ha,omitempty" json:"ha,omitempty"`
+	CNI                string                       `yaml:"cni,omitempty" json:"cni,omitempty"`
+	APIServerName      string                       `yaml:"apiServerName,omitempty" json:"apiServerName,omitempty"`
+	APIServerNames     []string                     `yaml:"apiServerNames,omitempty" json:"apiServerNames,omitempty"`
+	APIServerIPs       []string                     `yaml:"apiServerIPs,omitempty" json:"apiServerIPs,omitempty"`
+	APIServerPort      int                          `yaml:"apiServerPort,omitempty" json:"apiServerPort,omitempty"`
+	DNSDomain          string                       `yaml:"dnsDomain,omitempty" json:"dnsDomain,omitempty"`
+	ServiceCIDR        string                       `yaml:"serviceCIDR,omitempty" json:"serviceCIDR,omitempty"`
+	ImageRepository    string                       `yaml:"imageRepository,omitempty" json:"imageRepository,omitempty"`
+	RegistryMirrors    []string                     `yaml:"registryMirrors,omitempty" json:"registryMirrors,omitempty"`
+	InsecureRegistries []string                     `yaml:"insecureRegistries,omitempty" json:"insecureRegistries,omitempty"`
+	FeatureGates       map[string]bool              `yaml:"featureGates,omitempty" json:"featureGates,omitempty"`
+	ExtraConfig        map[string]map[string]string `yaml:"extraConfig,omitempty" json:"extraConfig,omitempty"`
+	Addons             []string                     `yaml:"addons,omitempty" json:"addons,omitempty"`
+	Mounts             []Mount                      `yaml:"mounts,omitempty" json:"mounts,omitempty"`
+	CACert             string                       `yaml:"caCert,omitempty" json:"caCert,omitempty"`
+	CAKey              string                       `yaml:"caKey,omitempty" json:"caKey,omitempty"`
+	Auth               *Auth                        `yaml:"auth,omitempty" json:"auth,omitempty"`
+	AuditPolicy        string                       `yaml:"auditPolicy,omitempty" json:"auditPolicy,omitempty"`
+	NodePools          []NodePool                   `yaml:"nodePools,omitempty" json:"nodePools,omitempty"`
+}
+
+// Mount shares a host directory with the cluster
+type Mount struct {
+	HostPath  string `yaml:"hostPath" json:"hostPath"`
+	GuestPath string `yaml:"guestPath" json:"guestPath"`
+}
+
+// Auth is an authentication mode of the API server besides client certificates.
+// An oidc mode without an issuer URL uses the dex addon as its issuer.
+type Auth struct {
+	Mode              string `yaml:"mode" json:"mode"`
+	OIDCIssuerURL     string `yaml:"oidcIssuerURL,omitempty" json:"oidcIssuerURL,omitempty"`
+	OIDCClientID      string `yaml:"oidcClientID,omitempty" json:"oidcClientID,omitempty"`
+	OIDCClientSecret  string `yaml:"oidcClientSecret,omitempty" json:"oidcClientSecret,omitempty"`
+	OIDCUsernameClaim string `yaml:"oidcUsernameClaim,omitempty" json:"oidcUsernameClaim,omitempty"`
+	OIDCGroupsClaim   string `yaml:"oidcGroupsClaim,omitempty" json:"oidcGroupsClaim,omitempty"`
+	OIDCCAFile        string `yaml:"oidcCAFile,omitempty" json:"oidcCAFile,omitempty"`
+	TokenFile         string `yaml:"tokenFile,omitempty" json:"tokenFile,omitempty"`
+	WebhookConfig     string `yaml:"webhookConfig,omitempty" json:"webhookConfig,omitempty"`
+	Token             string `yaml:"token,omitempty" json:"token,omitempty"`
+}
+
+// NodePool is a group of worker nodes added after the nodes of the cluster, which share resources, labels and taints.
+// Nodes without a pool name are not labelled with a pool.
+type NodePool struct {
+	Name     string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Count    int               `yaml:"count" json:"count"`
+	CPUs     int               `yaml:"cpus,omitempty" json:"cpus,omitempty"`
+	Memory   string            `yaml:"memory,omitempty" json:"memory,omitempty"`
+	DiskSize string            `yaml:"diskSize,omitempty" json:"diskSize,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Taints   []string          `yaml:"taints,omitempty" json:"taints,omitempty"`
+}
+
+// Read reads and validates a cluster definition file in YAML or JSON format
+func Read(path string) (*Cluster, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a cluster definition in YAML or JSON format
+func Parse(data []byte) (*Cluster, error) {
+	var c Cluster
+	// JSON is a subset of YAML, so both are parsed alike, and unknown fields are rejected so that typos do not go unnoticed
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, errors.Wrap(err, "parsing cluster definition")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate returns an error describing every invalid field of a cluster definition
+func (c *Cluster) Validate() error {
+	var errs field.ErrorList
+	if c.APIVersion != APIVersion {
+		errs = append(errs, field.NotSupported(field.NewPath("apiVersion"), c.APIVersion, []string{APIVersion}))
+	}
+	if c.Kind != Kind {
+		errs = append(errs, field.NotSupported(field.NewPath("kind"), c.Kind, []string{Kind}))
+	}
+	if c.Metadata.Name != "" && !config.ProfileNameValid(c.Metadata.Name) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), c.Metadata.Name, "only alphanumeric characters and dashes are permitted, with at least 2 characters, starting with an alphanumeric character"))
+	}
+	errs = append(errs, c.Spec.validate(field.NewPath("spec"))...)
+	return errs.ToAggregate()
+}
+
+func (s *Spec) validate(p *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if s.Driver != "" && !driver.Supported(s.Driver) {
+		errs = append(errs, field.NotSupported(p.Child("driver"), s.Driver, driver.SupportedDrivers()))
+	}
+	if s.KubernetesVersion != "" && s.KubernetesVersion != "stable" && s.KubernetesVersion != "latest" {
+		if _, err := pkgutil.ParseKubernetesVersion(s.KubernetesVersion); err != nil {
+			errs = append(errs, field.Invalid(p.Child("kubernetesVersion"), s.KubernetesVersion, "must be a version such as v1.19.2, 'stable' or 'latest'"))
+		}
+	}
+	if s.ContainerRuntime != "" && !contains(cruntime.ValidRuntimes(), s.ContainerRuntime) {
+		errs = append(errs, field.NotSupported(p.Child("containerRuntime"), s.ContainerRuntime, cruntime.ValidRuntimes()))
+	}
+	if s.ContainerRuntime == "cri" && s.CRISocket == "" {
+		errs = append(errs, field.Required(p.Child("criSocket"), "required by the cri container runtime"))
+	}
+	for _, name := range s.runtimeClassNames() {
+		if err := cruntime.ValidateRuntimeClass(name, s.RuntimeClasses[name]); err != nil {
+			errs = append(errs, field.Invalid(p.Child("runtimeClasses").Key(name), s.RuntimeClasses[name], err.Error()))
+		}
+	}
+	if s.CPUs < 0 {
+		errs = append(errs, field.Invalid(p.Child("cpus"), s.CPUs, "must not be negative"))
+	}
+	for _, size := range []struct {
+		name  string
+		value string
+	}{{"memory", s.Memory}, {"diskSize", s.DiskSize}} {
+		if size.value == "" {
+			continue
+		}
+		if _, err := pkgutil.CalculateSizeInMB(size.value); err != nil {
+			errs = append(errs, field.Invalid(p.Child(size.name), size.value, "must be a size such as 4096mb or 4g"))
+		}
+	}
+	if s.Nodes < 0 {
+		errs = append(errs, field.Invalid(p.Child("nodes"), s.Nodes, "must not be negative"))
+	}
+	for i, ip := range s.APIServerIPs {
+		if net.ParseIP(ip) == nil {
+			errs = append(errs, field.Invalid(p.Child("apiServerIPs").Index(i), ip, "must be an IP address"))
+		}
+	}
+	if s.APIServerPort < 0 || s.APIServerPort > 65535 {
+		errs = append(errs, field.Invalid(p.Child("apiServerPort"), s.APIServerPort, "must be a port number"))
+	}
+	if s.ServiceCIDR != "" {
+		if _, _, err := net.ParseCIDR(s.ServiceCIDR); err != nil {
+			errs = append(errs, field.Invalid(p.Child("serviceCIDR"), s.ServiceCIDR, "must be a CIDR such as 10.96.0.0/12"))
+		}
+	}
+	for _, component := range s.extraConfigComponents() {
+		if invalid := bsutil.FindInvalidExtraConfigFlags(config.ExtraOptionSlice{{Component: component}}); len(invalid) > 0 {
+			errs = append(errs, field.Invalid(p.Child("extraConfig").Key(component), component, "unknown component"))
+		}
+	}
+	for i, name := range s.Addons {
+		if _, ok := assets.Addons[name]; !ok {
+			errs = append(errs, field.NotFound(p.Child("addons").Index(i), name))
+		}
+	}
+	if len(s.Mounts) > 1 {
+		errs = append(errs, field.TooMany(p.Child("mounts"), len(s.Mounts), 1))
+	}
+	for i, m := range s.Mounts {
+		if m.HostPath == "" {
+			errs = append(errs, field.Required(p.Child("mounts").Index(i).Child("hostPath"), ""))
+		}
+		if m.GuestPath == "" {
+			errs = append(errs, field.Required(p.Child("mounts").Index(i).Child("guestPath"), ""))
+		}
+	}
+	if (s.CACert == "") != (s.CAKey == "") {
+		errs = append(errs, field.Required(p.Child("caKey"), "caCert and caKey must be given together"))
+	}
+	if s.Auth != nil && !contains(auth.Modes(), s.Auth.Mode) {
+		errs = append(errs, field.NotSupported(p.Child("auth", "mode"), s.Auth.Mode, auth.Modes()))
+	}
+	for i, np := range s.NodePools {
+		pp := p.Child("nodePools").Index(i)
+		if np.Count < 1 {
+			errs = append(errs, field.Invalid(pp.Child("count"), np.Count, "must be at least 1"))
+		}
+		if np.CPUs < 0 {
+			errs = append(errs, field.Invalid(pp.Child("cpus"), np.CPUs, "must not be negative"))
+		}
+		for _, size := range []struct {
+			name  string
+			value string
+		}{{"memory", np.Memory}, {"diskSize", np.DiskSize}} {
+			if size.value == "" {
+				continue
+			}
+			if _, err := pkgutil.CalculateSizeInMB(size.value); err != nil {
+				errs = append(errs, field.Invalid(pp.Child(size.name), size.value, "must be a size such as 4096mb or 4g"))
+			}
+		}
+	}
+	return errs
+}
+
+// FromClusterConfig returns the definition of an existing cluster
+func FromClusterConfig(cc *config.ClusterConfig) *Cluster {
+	k := cc.KubernetesConfig
+	s := Spec{
+		Driver:             cc.Driver,
+		KubernetesVersion:  k.KubernetesVersion,
+		ContainerRuntime:   k.ContainerRuntime,
+		CRISocket:          k.CRISocket,
+		RuntimeClasses:     k.RuntimeClasses,
+		CPUs:               cc.CPUs,
+		Nodes:              len(cc.Nodes),
+		HA:                 cc.HA,
+		CNI:                k.CNI,
+		APIServerName:      k.APIServerName,
+		APIServerNames:     k.APIServerNames,
+		DNSDomain:          k.DNSDomain,
+		ServiceCIDR:        k.ServiceCIDR,
+		ImageRepository:    k.ImageRepository,
+		RegistryMirrors:    cc.RegistryMirror,
+		InsecureRegistries: cc.InsecureRegistry,
+	}
+	if cc.Memory > 0 {
+		s.Memory = fmt.Sprintf("%dmb", cc.Memory)
+	}
+	if cc.DiskSize > 0 {
+		s.DiskSize = fmt.Sprintf("%dmb", cc.DiskSize)
+	}
+	if cp, err := config.PrimaryControlPlane(cc); err == nil {
+		s.APIServerPort = cp.Port
+	}
+	for _, ip := range k.APIServerIPs {
+		s.APIServerIPs = append(s.APIServerIPs, ip.String())
+	}
+
+	for _, fg := range strings.Split(k.FeatureGates, ",") {
+		kv := strings.SplitN(fg, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if s.FeatureGates == nil {
+			s.FeatureGates = map[string]bool{}
+		}
+		s.FeatureGates[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1]) == "true"
+	}
+
+	for _, eo := range k.ExtraOptions {
+		if s.ExtraConfig == nil {
+			s.ExtraConfig = map[string]map[string]string{}
+		}
+		if s.ExtraConfig[eo.Component] == nil {
+			s.ExtraConfig[eo.Component] = map[string]string{}
+		}
+		s.ExtraConfig[eo.Component][eo.Key] = eo.Value
+	}
+
+	for name, enabled := range cc.Addons {
+		if enabled {
+			s.Addons = append(s.Addons, name)
+		}
+	}
+	sort.Strings(s.Addons)
+
+	if k.CACertPath != "" {
+		s.CACert = k.CACertPath
+		s.CAKey = k.CAKeyPath
+	}
+	if a := k.Auth; a.Mode != "" {
+		s.Auth = &Auth{
+			Mode:              a.Mode,
+			OIDCClientID:      a.OIDCClientID,
+			OIDCClientSecret:  redact(a.OIDCClientSecret),
+			OIDCUsernameClaim: a.OIDCUsernameClaim,
+			OIDCGroupsClaim:   a.OIDCGroupsClaim,
+			OIDCCAFile:        a.OIDCCAFile,
+			TokenFile:         a.TokenFile,
+			WebhookConfig:     a.WebhookConfigFile,
+			Token:             redact(a.Token),
+		}
+		// The dex addon is configured again on start, with its default secret
+		if !a.LocalDex {
+			s.Auth.OIDCIssuerURL = a.OIDCIssuerURL
+		} else if a.OIDCClientSecret == auth.DexClientSecret {
+			s.Auth.OIDCClientSecret = ""
+		}
+	}
+	s.AuditPolicy = k.AuditPolicy
+	s.Nodes, s.NodePools = nodePools(cc.Nodes)
+
+	// Only container drivers record the mount, as it cannot be changed after creation
+	for _, m := range cc.ContainerVolumeMounts {
+		if i := strings.LastIndex(m, ":"); i > 0 {
+			s.Mounts = append(s.Mounts, Mount{HostPath: m[:i], GuestPath: m[i+1:]})
+		}
+	}
+
+	return &Cluster{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Metadata:   Metadata{Name: cc.Name},
+		Spec:       s,
+	}
+}
+
+// redact returns Redacted for a secret, so that exported definitions can be shared
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// nodePools returns the number of nodes with the resources of the cluster and without labels,
+// and groups the other nodes in node pools
+func nodePools(nodes []config.Node) (int, []NodePool) {
+	plain := 0
+	var pools []NodePool
+	for _, n := range nodes {
+		if n.ControlPlane || (n.Pool == "" && n.CPUs == 0 && n.Memory == 0 && n.DiskSize == 0 && len(n.Labels) == 0 && len(n.Taints) == 0) {
+			plain++
+			continue
+		}
+		np := NodePool{Name: n.Pool, Count: 1, CPUs: n.CPUs, Labels: n.Labels, Taints: n.Taints}
+		if n.Memory > 0 {
+			np.Memory = fmt.Sprintf("%dmb", n.Memory)
+		}
+		if n.DiskSize > 0 {
+			np.DiskSize = fmt.Sprintf("%dmb", n.DiskSize)
+		}
+		found := false
+		for i := range pools {
+			if samePool(pools[i], np) {
+				pools[i].Count++
+				found = true
+				break
+			}
+		}
+		if !found {
+			pools = append(pools, np)
+		}
+	}
+	return plain, pools
+}
+
+// samePool returns whether nodes of two pools are alike
+func samePool(a, b NodePool) bool {
+	return a.Name == b.Name && a.CPUs == b.CPUs && a.Memory == b.Memory && a.DiskSize == b.DiskSize &&
+		reflect.DeepEqual(a.Labels, b.Labels) && reflect.DeepEqual(a.Taints, b.Taints)
+}
+
+// FeatureGatesString returns the feature gates in the format of the --feature-gates flag
+func (s *Spec) FeatureGatesString() string {
+	var fgs []string
+	for name, enabled := range s.FeatureGates {
+		fgs = append(fgs, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	sort.Strings(fgs)
+	return strings.Join(fgs, ",")
+}
+
+// ExtraConfigStrings returns the extra configuration in the format of the --extra-config flag
+func (s *Spec) ExtraConfigStrings() []string {
+	var eos []string
+	for component, opts := range s.ExtraConfig {
+		for key, value := range opts {
+			eos = append(eos, fmt.Sprintf("%s.%s=%s", component, key, value))
+		}
+	}
+	sort.Strings(eos)
+	return eos
+}
+
+// extraConfigComponents returns the components with extra configuration, in order
+func (s *Spec) extraConfigComponents() []string {
+	var components []string
+	for component := range s.ExtraConfig {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	return components
+}
+
+// RuntimeClassStrings returns the runtime classes in the NAME=HANDLER format of the --runtime-class flag
+func (s *Spec) RuntimeClassStrings() []string {
+	var rcs []string
+	for _, name := range s.runtimeClassNames() {
+		rcs = append(rcs, name+"="+s.RuntimeClasses[name])
+	}
+	return rcs
+}
+
+// runtimeClassNames returns the names of the runtime classes, in order
+func (s *Spec) runtimeClassNames() []string {
+	var names []string
+	for name := range s.RuntimeClasses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
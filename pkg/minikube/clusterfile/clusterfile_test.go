@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterfile
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/minikube/auth"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParse(t *testing.T) {
+	valid := `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+metadata:
+  name: dev
+spec:
+  driver: docker
+  kubernetesVersion: v1.19.2
+  cpus: 4
+  memory: 8g
+  nodes: 2
+  featureGates:
+    EphemeralContainers: true
+  extraConfig:
+    kubelet:
+      max-pods: "150"
+  addons:
+  - ingress
+  mounts:
+  - hostPath: /src
+    guestPath: /src
+`
+	c, err := Parse([]byte(valid))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if c.Metadata.Name != "dev" || c.Spec.CPUs != 4 || c.Spec.Nodes != 2 || c.Spec.Mounts[0].GuestPath != "/src" {
+		t.Errorf("Parse() = %+v", c)
+	}
+	if got := c.Spec.FeatureGatesString(); got != "EphemeralContainers=true" {
+		t.Errorf("FeatureGatesString() = %q", got)
+	}
+	if got := c.Spec.ExtraConfigStrings(); !cmp.Equal(got, []string{"kubelet.max-pods=150"}) {
+		t.Errorf("ExtraConfigStrings() = %v", got)
+	}
+
+	tests := []struct {
+		description string
+		definition  string
+		want        []string
+	}{
+		{
+			description: "json",
+			definition:  `{"apiVersion": "minikube.sigs.k8s.io/v1alpha1", "kind": "Cluster", "spec": {"cpus": 2}}`,
+		},
+		{
+			description: "unknown field",
+			definition:  "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Cluster\nspec:\n  cpu: 2\n",
+			want:        []string{"field cpu not found"},
+		},
+		{
+			description: "wrong version",
+			definition:  "apiVersion: v1\nkind: Pod\n",
+			want:        []string{`apiVersion: Unsupported value: "v1"`, `kind: Unsupported value: "Pod"`},
+		},
+		{
+			description: "invalid fields",
+			definition: `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+metadata:
+  name: -dev
+spec:
+  driver: vmware-workstation
+  kubernetesVersion: newest
+  containerRuntime: cri
+  runtimeClasses:
+    kata: kata.qemu
+  memory: lots
+  apiServerIPs: [10.0.0.300]
+  extraConfig:
+    kubelett:
+      max-pods: "150"
+  addons: [ingress, ingres]
+  mounts:
+  - hostPath: /src
+  - hostPath: /data
+    guestPath: /data
+  caCert: /certs/ca.crt
+  auth:
+    mode: ldap
+  nodePools:
+  - name: gpu
+    count: 0
+    memory: lots
+`,
+			want: []string{
+				`metadata.name: Invalid value: "-dev"`,
+				`spec.driver: Unsupported value: "vmware-workstation"`,
+				`spec.kubernetesVersion: Invalid value: "newest"`,
+				`spec.criSocket: Required value`,
+				`spec.runtimeClasses[kata]: Invalid value: "kata.qemu"`,
+				`spec.memory: Invalid value: "lots"`,
+				`spec.apiServerIPs[0]: Invalid value: "10.0.0.300"`,
+				`spec.extraConfig[kubelett]: Invalid value: "kubelett": unknown component`,
+				`spec.addons[1]: Not found: "ingres"`,
+				`spec.mounts: Too many: 2: must have at most 1 items`,
+				`spec.mounts[0].guestPath: Required value`,
+				`spec.caKey: Required value`,
+				`spec.auth.mode: Unsupported value: "ldap"`,
+				`spec.nodePools[0].count: Invalid value: 0`,
+				`spec.nodePools[0].memory: Invalid value: "lots"`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := Parse([]byte(tc.definition))
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error does not contain %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFromClusterConfig(t *testing.T) {
+	cc := &config.ClusterConfig{
+		Name:                  "dev",
+		Driver:                "docker",
+		CPUs:                  4,
+		Memory:                8192,
+		DiskSize:              20000,
+		RegistryMirror:        []string{"https://mirror.gcr.io"},
+		ContainerVolumeMounts: []string{"/src:/minikube-host"},
+		Addons:                map[string]bool{"ingress": true, "dashboard": false, "metrics-server": true},
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.19.2",
+			ContainerRuntime:  "containerd",
+			APIServerIPs:      []net.IP{net.ParseIP("192.168.1.10")},
+			FeatureGates:      "EphemeralContainers=true,CSIMigration=false",
+			ExtraOptions:      config.ExtraOptionSlice{{Component: "kubelet", Key: "max-pods", Value: "150"}},
+			CACertPath:        "/certs/ca.crt",
+			CAKeyPath:         "/certs/ca.key",
+			Auth:              config.AuthConfig{Mode: "oidc", OIDCIssuerURL: "https://192.168.49.2:32000", OIDCClientID: "minikube", LocalDex: true},
+			AuditPolicy:       "metadata",
+		},
+		Nodes: []config.Node{
+			{ControlPlane: true, Worker: true, Port: 8443},
+			{Name: "m02", Worker: true},
+			{Name: "m03", Worker: true, Pool: "gpu", Memory: 4096, Labels: map[string]string{"gpu": "true"}},
+			{Name: "m04", Worker: true, Pool: "gpu", Memory: 4096, Labels: map[string]string{"gpu": "true"}},
+			{Name: "m05", Worker: true, Taints: []string{"dedicated=db:NoSchedule"}},
+		},
+	}
+
+	c := FromClusterConfig(cc)
+	want := Spec{
+		Driver:            "docker",
+		KubernetesVersion: "v1.19.2",
+		ContainerRuntime:  "containerd",
+		CPUs:              4,
+		Memory:            "8192mb",
+		DiskSize:          "20000mb",
+		Nodes:             2,
+		APIServerIPs:      []string{"192.168.1.10"},
+		APIServerPort:     8443,
+		RegistryMirrors:   []string{"https://mirror.gcr.io"},
+		FeatureGates:      map[string]bool{"EphemeralContainers": true, "CSIMigration": false},
+		ExtraConfig:       map[string]map[string]string{"kubelet": {"max-pods": "150"}},
+		Addons:            []string{"ingress", "metrics-server"},
+		Mounts:            []Mount{{HostPath: "/src", GuestPath: "/minikube-host"}},
+		CACert:            "/certs/ca.crt",
+		CAKey:             "/certs/ca.key",
+		Auth:              &Auth{Mode: "oidc", OIDCClientID: "minikube"},
+		AuditPolicy:       "metadata",
+		NodePools: []NodePool{
+			{Name: "gpu", Count: 2, Memory: "4096mb", Labels: map[string]string{"gpu": "true"}},
+			{Count: 1, Taints: []string{"dedicated=db:NoSchedule"}},
+		},
+	}
+	if diff := cmp.Diff(want, c.Spec); diff != "" {
+		t.Errorf("FromClusterConfig() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Exported definitions must be accepted by start, in either format
+	for name, marshal := range map[string]func(interface{}) ([]byte, error){"yaml": yaml.Marshal, "json": json.Marshal} {
+		data, err := marshal(c)
+		if err != nil {
+			t.Fatalf("%s marshal: %v", name, err)
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s parse: %v", name, err)
+		}
+		if diff := cmp.Diff(c, parsed); diff != "" {
+			t.Errorf("%s round trip mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+}
+
+func TestFromClusterConfigRedactsSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		auth config.AuthConfig
+		want Auth
+	}{
+		{
+			name: "oidc",
+			auth: config.AuthConfig{Mode: "oidc", OIDCIssuerURL: "https://issuer.example.com", OIDCClientID: "minikube", OIDCClientSecret: "oidc-secret"},
+			want: Auth{Mode: "oidc", OIDCIssuerURL: "https://issuer.example.com", OIDCClientID: "minikube", OIDCClientSecret: Redacted},
+		},
+		{
+			name: "dex",
+			auth: config.AuthConfig{Mode: "oidc", OIDCIssuerURL: "https://192.168.49.2:32000", OIDCClientSecret: auth.DexClientSecret, LocalDex: true},
+			want: Auth{Mode: "oidc"},
+		},
+		{
+			name: "token",
+			auth: config.AuthConfig{Mode: "token", Token: "token-secret"},
+			want: Auth{Mode: "token", Token: Redacted},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cc := &config.ClusterConfig{Name: "dev", KubernetesConfig: config.KubernetesConfig{Auth: tc.auth}}
+			c := FromClusterConfig(cc)
+			if diff := cmp.Diff(&tc.want, c.Spec.Auth); diff != "" {
+				t.Errorf("FromClusterConfig() auth mismatch (-want +got):\n%s", diff)
+			}
+			for name, marshal := range map[string]func(interface{}) ([]byte, error){"yaml": yaml.Marshal, "json": json.Marshal} {
+				data, err := marshal(c)
+				if err != nil {
+					t.Fatalf("%s marshal: %v", name, err)
+				}
+				for _, secret := range []string{"oidc-secret", "token-secret", auth.DexClientSecret} {
+					if strings.Contains(string(data), secret) {
+						t.Errorf("%s export contains secret %q:\n%s", name, secret, data)
+					}
+				}
+			}
+		})
+	}
+}
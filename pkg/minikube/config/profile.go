@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
@@ -33,6 +34,9 @@ import (
 
 var keywords = []string{"start", "stop", "status", "delete", "config", "open", "profile", "addons", "cache", "logs"}
 
+// saveMu serializes the profile writes of nodes started in parallel
+var saveMu sync.Mutex
+
 // IsValid checks if the profile has the essential info needed for a profile
 func (p *Profile) IsValid() bool {
 	if p.Config == nil {
@@ -52,6 +56,21 @@ func (p *Profile) IsValid() bool {
 	return true
 }
 
+// NodeResources returns the CPUs, memory and disk size in MB of a node, which default to those of the cluster
+func NodeResources(cc ClusterConfig, n Node) (cpus int, memory int, diskSize int) {
+	cpus, memory, diskSize = cc.CPUs, cc.Memory, cc.DiskSize
+	if n.CPUs > 0 {
+		cpus = n.CPUs
+	}
+	if n.Memory > 0 {
+		memory = n.Memory
+	}
+	if n.DiskSize > 0 {
+		diskSize = n.DiskSize
+	}
+	return cpus, memory, diskSize
+}
+
 // PrimaryControlPlane gets the node specific config for the first created control plane
 func PrimaryControlPlane(cc *ClusterConfig) (Node, error) {
 	for _, n := range cc.Nodes {
@@ -148,6 +167,8 @@ func SaveProfile(name string, cfg *ClusterConfig, miniHome ...string) error {
 	}
 	path := profileFilePath(name, miniHome...)
 	klog.Infof("Saving config to %s ...", path)
+	saveMu.Lock()
+	defer saveMu.Unlock()
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return err
 	}
@@ -174,10 +195,7 @@ func SaveProfile(name string, cfg *ClusterConfig, miniHome ...string) error {
 		return err
 	}
 
-	if err = os.Remove(path); err != nil {
-		return err
-	}
-
+	// Rename replaces the config atomically, so that it is never missing for the nodes being started
 	if err = os.Rename(tf.Name(), path); err != nil {
 		return err
 	}
@@ -298,3 +298,22 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 	}
 
 }
+
+func TestNodeResources(t *testing.T) {
+	cc := ClusterConfig{CPUs: 2, Memory: 2200, DiskSize: 20000}
+	var tests = []struct {
+		description string
+		node        Node
+		expected    [3]int
+	}{
+		{"cluster defaults", Node{Name: "m02"}, [3]int{2, 2200, 20000}},
+		{"node overrides", Node{Name: "m02", CPUs: 4, Memory: 8192, DiskSize: 40000}, [3]int{4, 8192, 40000}},
+		{"partial override", Node{Name: "m02", Memory: 4096}, [3]int{2, 4096, 20000}},
+	}
+	for _, tc := range tests {
+		cpus, memory, disk := NodeResources(cc, tc.node)
+		if got := [3]int{cpus, memory, disk}; got != tc.expected {
+			t.Errorf("%s: NodeResources = %v, expected %v", tc.description, got, tc.expected)
+		}
+	}
+}
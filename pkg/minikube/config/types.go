@@ -72,6 +72,8 @@ type ClusterConfig struct {
 	VerifyComponents        map[string]bool // map of components to verify and wait for after start.
 	StartHostTimeout        time.Duration
 	ExposedPorts            []string // Only used by the docker and podman driver
+	ScheduledStop           *ScheduledStopConfig
+	HA                      bool // Whether the cluster runs multiple control planes behind a virtual IP
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -84,13 +86,19 @@ type KubernetesConfig struct {
 	DNSDomain           string
 	ContainerRuntime    string
 	CRISocket           string
+	RuntimeClasses      map[string]string // RuntimeClass name to CRI handler
 	NetworkPlugin       string
 	FeatureGates        string // https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/
 	ServiceCIDR         string // the subnet which Kubernetes services will be deployed to
 	ImageRepository     string
-	LoadBalancerStartIP string // currently only used by MetalLB addon
-	LoadBalancerEndIP   string // currently only used by MetalLB addon
+	LoadBalancerStartIP string // used by the MetalLB and loadbalancer-controller addons
+	LoadBalancerEndIP   string // used by the MetalLB and loadbalancer-controller addons
+	APIServerHAVIP      string // virtual IP fronting the API servers of a highly available cluster
+	CACertPath          string // CA to sign the certificates of the cluster with, instead of the minikube CA
+	CAKeyPath           string
 	ExtraOptions        ExtraOptionSlice
+	Auth                AuthConfig // how users authenticate to the API server, besides client certificates
+	AuditPolicy         string     // audit policy preset, or path of a policy file
 
 	ShouldLoadCachedImages bool
 
@@ -103,6 +111,21 @@ type KubernetesConfig struct {
 	NodeName string
 }
 
+// AuthConfig configures an authentication mode of the API server. Paths are on the host.
+type AuthConfig struct {
+	Mode              string // oidc, webhook or token
+	OIDCIssuerURL     string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCUsernameClaim string
+	OIDCGroupsClaim   string
+	OIDCCAFile        string
+	LocalDex          bool // the OIDC issuer is the dex addon
+	TokenFile         string
+	WebhookConfigFile string
+	Token             string // bearer token of the kubeconfig user
+}
+
 // Node contains information about specific nodes in a cluster
 type Node struct {
 	Name              string
@@ -111,6 +134,28 @@ type Node struct {
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	SSH               SSHConfig // Only used by the ssh driver
+	Pool              string
+	CPUs              int // Overrides the CPUs of the cluster if set
+	Memory            int // Overrides the memory of the cluster if set, in MB
+	DiskSize          int // Overrides the disk size of the cluster if set, in MB
+	Labels            map[string]string
+	Taints            []string // key[=value]:effect
+	Failure           string   // The failure simulated by 'minikube node fail', until the node is healed
+}
+
+// SSHConfig is how the ssh driver reaches an existing machine
+type SSHConfig struct {
+	IPAddress string
+	User      string
+	Key       string // path of the private key on the host, or empty for the default keys
+	Port      int
+}
+
+// ScheduledStopConfig contains information around scheduled stop
+type ScheduledStopConfig struct {
+	InitiationTime int64 // unix timestamp of when the stop was scheduled
+	Duration       time.Duration
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
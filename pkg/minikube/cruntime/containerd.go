@@ -236,6 +236,51 @@ func (r *Containerd) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this container runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from this runtime
+func (r *Containerd) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image into this runtime
+func (r *Containerd) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// BuildImage builds an image into this runtime
+func (r *Containerd) BuildImage(o BuildImageOptions) error {
+	if o.Tag == "" {
+		return fmt.Errorf("building an image for containerd requires a tag")
+	}
+	c := exec.Command("which", "podman")
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return NewErrISOFeature("podman")
+	}
+
+	// containerd has no builder of its own: build with podman, then import the result
+	if err := podmanBuildImage(r.Runner, o); err != nil {
+		return err
+	}
+	archive := path.Join(o.Dir, "..", path.Base(o.Dir)+".tar")
+	c = exec.Command("sudo", "podman", "save", "-o", archive, o.Tag)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "podman save")
+	}
+	defer func() {
+		if _, err := r.Runner.RunCmd(exec.Command("sudo", "rm", "-f", archive)); err != nil {
+			klog.Warningf("unable to remove %s: %v", archive, err)
+		}
+		if _, err := r.Runner.RunCmd(exec.Command("sudo", "podman", "rmi", o.Tag)); err != nil {
+			klog.Warningf("unable to remove podman image %s: %v", o.Tag, err)
+		}
+	}()
+	return r.LoadImage(archive)
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Containerd) CGroupDriver() (string, error) {
 	info, err := getCRIInfo(r.Runner)
@@ -299,9 +344,29 @@ func (r *Containerd) ContainerLogCmd(id string, len int, follow bool) string {
 	return criContainerLogCmd(r.Runner, id, len, follow)
 }
 
+// ContainerExecCmd returns the command to run a command within a container based on ID
+func (r *Containerd) ContainerExecCmd(id string, args []string) string {
+	return criContainerExecCmd(r.Runner, id, args)
+}
+
+// TimestampedContainerLogCmd returns the command to retrieve the timestamped log for a container based on ID
+func (r *Containerd) TimestampedContainerLogCmd(id string, len int, since time.Duration) string {
+	return criTimestampedContainerLogCmd(r.Runner, id, len, since)
+}
+
+// ContainerInspectCmd returns the command to retrieve the low-level information of a container based on ID
+func (r *Containerd) ContainerInspectCmd(id string) string {
+	return criContainerInspectCmd(r.Runner, id)
+}
+
 // SystemLogCmd returns the command to retrieve system logs
 func (r *Containerd) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u containerd -n %d", len)
+	return fmt.Sprintf("sudo journalctl -u %s -n %d", r.SystemLogUnit(), len)
+}
+
+// SystemLogUnit returns the systemd unit whose journal holds the system logs
+func (r *Containerd) SystemLogUnit() string {
+	return "containerd"
 }
 
 // Preload preloads the container runtime with k8s images
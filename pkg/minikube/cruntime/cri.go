@@ -24,6 +24,7 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
@@ -47,6 +48,11 @@ func crictlList(cr CommandRunner, root string, o ListOptions) (*command.RunResul
 		baseCmd = append(baseCmd, fmt.Sprintf("--name=%s", o.Name))
 	}
 
+	// The CRI reports paused containers as running, so this only narrows down the list
+	if o.State == Running {
+		baseCmd = append(baseCmd, "--state=running")
+	}
+
 	// shortcut for all namespaces
 	if len(o.Namespaces) == 0 {
 		return cr.RunCmd(exec.Command("sudo", baseCmd...))
@@ -203,6 +209,76 @@ func stopCRIContainers(cr CommandRunner, ids []string) error {
 	return nil
 }
 
+// listCRIImages lists images using crictl
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	c := exec.Command("sudo", "crictl", "images", "--output", "json")
+	rr, err := cr.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "crictl images")
+	}
+
+	var jsonImages struct {
+		Images []struct {
+			ID       string   `json:"id"`
+			RepoTags []string `json:"repoTags"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &jsonImages); err != nil {
+		return nil, errors.Wrap(err, "unmarshal images")
+	}
+
+	var images []string
+	for _, img := range jsonImages.Images {
+		images = append(images, img.RepoTags...)
+	}
+	return images, nil
+}
+
+// removeCRIImage removes an image using crictl
+func removeCRIImage(cr CommandRunner, name string) error {
+	klog.Infof("Removing image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "rmi", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
+// pullCRIImage pulls an image using crictl
+func pullCRIImage(cr CommandRunner, name string) error {
+	klog.Infof("Pulling image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "pull", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
+// podmanBuildImage builds an image into the containers storage using podman
+func podmanBuildImage(cr CommandRunner, o BuildImageOptions) error {
+	klog.Infof("Building image: %s", o.Dir)
+	args := []string{"podman", "build"}
+	if o.Tag != "" {
+		args = append(args, "-t", o.Tag)
+	}
+	if o.Dockerfile != "" {
+		args = append(args, "-f", path.Join(o.Dir, o.Dockerfile))
+	}
+	for _, a := range o.BuildArgs {
+		args = append(args, "--build-arg", a)
+	}
+	args = append(args, o.Dir)
+	c := exec.Command("sudo", args...)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "podman build")
+	}
+	return nil
+}
+
 // populateCRIConfig sets up /etc/crictl.yaml
 func populateCRIConfig(cr CommandRunner, socket string) error {
 	cPath := "/etc/crictl.yaml"
@@ -242,6 +318,28 @@ func getCRIInfo(cr CommandRunner) (map[string]interface{}, error) {
 	return jsonMap, nil
 }
 
+// criContainerInspectCmd returns the command to retrieve the low-level information of a container based on ID
+func criContainerInspectCmd(cr CommandRunner, id string) string {
+	return fmt.Sprintf("sudo %s inspect %s", getCrictlPath(cr), id)
+}
+
+// criTimestampedContainerLogCmd returns the command to retrieve the timestamped log for a container based on ID, limited to a number of lines or a period
+func criTimestampedContainerLogCmd(cr CommandRunner, id string, len int, since time.Duration) string {
+	crictl := getCrictlPath(cr)
+	var cmd strings.Builder
+	cmd.WriteString("sudo ")
+	cmd.WriteString(crictl)
+	cmd.WriteString(" logs --timestamps ")
+	if since > 0 {
+		cmd.WriteString(fmt.Sprintf("--since %ds ", int(since.Seconds())))
+	} else if len > 0 {
+		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
+	}
+
+	cmd.WriteString(id)
+	return cmd.String()
+}
+
 // criContainerLogCmd returns the command to retrieve the log for a container based on ID
 func criContainerLogCmd(cr CommandRunner, id string, len int, follow bool) string {
 	crictl := getCrictlPath(cr)
@@ -260,6 +358,11 @@ func criContainerLogCmd(cr CommandRunner, id string, len int, follow bool) strin
 	return cmd.String()
 }
 
+// criContainerExecCmd returns the command to run a command within a container based on ID
+func criContainerExecCmd(cr CommandRunner, id string, args []string) string {
+	return fmt.Sprintf("sudo %s exec %s %s", getCrictlPath(cr), id, strings.Join(args, " "))
+}
+
 // addRepoTagToImageName makes sure the image name has a repo tag in it.
 // in crictl images list have the repo tag prepended to them
 // for example "kubernetesui/dashboard:v2.0.0 will show up as "docker.io/kubernetesui/dashboard:v2.0.0"
@@ -157,6 +157,27 @@ func (r *CRIO) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this container runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from this runtime
+func (r *CRIO) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image into this runtime
+func (r *CRIO) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// BuildImage builds an image into this runtime
+func (r *CRIO) BuildImage(o BuildImageOptions) error {
+	// podman shares the containers storage with CRI-O
+	return podmanBuildImage(r.Runner, o)
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *CRIO) CGroupDriver() (string, error) {
 	c := exec.Command("crio", "config")
@@ -217,9 +238,29 @@ func (r *CRIO) ContainerLogCmd(id string, len int, follow bool) string {
 	return criContainerLogCmd(r.Runner, id, len, follow)
 }
 
+// ContainerExecCmd returns the command to run a command within a container based on ID
+func (r *CRIO) ContainerExecCmd(id string, args []string) string {
+	return criContainerExecCmd(r.Runner, id, args)
+}
+
+// TimestampedContainerLogCmd returns the command to retrieve the timestamped log for a container based on ID
+func (r *CRIO) TimestampedContainerLogCmd(id string, len int, since time.Duration) string {
+	return criTimestampedContainerLogCmd(r.Runner, id, len, since)
+}
+
+// ContainerInspectCmd returns the command to retrieve the low-level information of a container based on ID
+func (r *CRIO) ContainerInspectCmd(id string) string {
+	return criContainerInspectCmd(r.Runner, id)
+}
+
 // SystemLogCmd returns the command to retrieve system logs
 func (r *CRIO) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u crio -n %d", len)
+	return fmt.Sprintf("sudo journalctl -u %s -n %d", r.SystemLogUnit(), len)
+}
+
+// SystemLogUnit returns the systemd unit whose journal holds the system logs
+func (r *CRIO) SystemLogUnit() string {
+	return "crio"
 }
 
 // Preload preloads the container runtime with k8s images
@@ -20,6 +20,7 @@ package cruntime
 import (
 	"fmt"
 	"os/exec"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
@@ -49,7 +50,7 @@ func (cs ContainerState) String() string {
 
 // ValidRuntimes lists the supported container runtimes
 func ValidRuntimes() []string {
-	return []string{"docker", "cri-o", "containerd"}
+	return []string{"docker", "cri-o", "containerd", "cri"}
 }
 
 // CommandRunner is the subset of command.Runner this package consumes
@@ -90,6 +91,14 @@ type Manager interface {
 
 	// ImageExists takes image name and image sha checks if an it exists
 	ImageExists(string, string) bool
+	// ListImages returns a list of images managed by this container runtime
+	ListImages() ([]string, error)
+	// RemoveImage removes an image based on name
+	RemoveImage(string) error
+	// PullImage pulls an image from a registry into the runtime on a host
+	PullImage(string) error
+	// BuildImage builds an image from a context directory on a host
+	BuildImage(BuildImageOptions) error
 
 	// ListContainers returns a list of managed by this container runtime
 	ListContainers(ListOptions) ([]string, error)
@@ -103,8 +112,16 @@ type Manager interface {
 	UnpauseContainers([]string) error
 	// ContainerLogCmd returns the command to retrieve the log for a container based on ID
 	ContainerLogCmd(string, int, bool) string
+	// ContainerExecCmd returns the command to run a command within a container based on ID
+	ContainerExecCmd(string, []string) string
+	// ContainerInspectCmd returns the command to retrieve the low-level information of a container based on ID, as JSON
+	ContainerInspectCmd(string) string
+	// TimestampedContainerLogCmd returns the command to retrieve the log for a container based on ID, with the time of each line, limited to a number of lines or a period
+	TimestampedContainerLogCmd(string, int, time.Duration) string
 	// SystemLogCmd returns the command to return the system logs
 	SystemLogCmd(int) string
+	// SystemLogUnit returns the systemd unit whose journal holds the system logs
+	SystemLogUnit() string
 	// Preload preloads the container runtime with k8s images
 	Preload(config.KubernetesConfig) error
 	// ImagesPreloaded returns true if all images have been preloaded
@@ -135,6 +152,23 @@ type ListOptions struct {
 	Namespaces []string
 }
 
+// BuildImageOptions are the options to use for building images
+type BuildImageOptions struct {
+	// Dir is the build context directory on the host
+	Dir string
+	// Dockerfile is the path to the Dockerfile, relative to Dir
+	Dockerfile string
+	// Tag is the name and optionally a tag in the 'name:tag' format
+	Tag string
+	// BuildArgs are the build-time variables, in the 'KEY=VALUE' format
+	BuildArgs []string
+}
+
+// ForCluster returns the runtime of a cluster, including its custom socket, executing commands with runner
+func ForCluster(k8s config.KubernetesConfig, runner CommandRunner) (Manager, error) {
+	return New(Config{Type: k8s.ContainerRuntime, Socket: k8s.CRISocket, Runner: runner})
+}
+
 // New returns an appropriately configured runtime
 func New(c Config) (Manager, error) {
 	sm := sysinit.New(c.Runner)
@@ -162,11 +196,22 @@ func New(c Config) (Manager, error) {
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
 		}, nil
+	case "cri":
+		return &GenericCRI{
+			Socket: c.Socket,
+			Runner: c.Runner,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
 	}
 }
 
+// CanLoadImages returns whether the runtime can load image archives, which the CRI has no way to do
+func CanLoadImages(cr Manager) bool {
+	_, generic := cr.(*GenericCRI)
+	return !generic
+}
+
 // ContainerStatusCommand works across container runtimes with good formatting
 func ContainerStatusCommand() string {
 	// Fallback to 'docker ps' if it fails (none driver)
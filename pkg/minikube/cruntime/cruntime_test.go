@@ -41,6 +41,7 @@ func TestName(t *testing.T) {
 		{"crio", "CRI-O"},
 		{"cri-o", "CRI-O"},
 		{"containerd", "containerd"},
+		{"cri", "CRI"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -56,6 +57,18 @@ func TestName(t *testing.T) {
 	}
 }
 
+func TestCanLoadImages(t *testing.T) {
+	for rt, want := range map[string]bool{"docker": true, "crio": true, "containerd": true, "cri": false} {
+		r, err := New(Config{Type: rt, Socket: "/run/example.sock"})
+		if err != nil {
+			t.Fatalf("New(%s): %v", rt, err)
+		}
+		if got := CanLoadImages(r); got != want {
+			t.Errorf("CanLoadImages(%s) = %t, want %t", rt, got, want)
+		}
+	}
+}
+
 func TestImageExists(t *testing.T) {
 	var tests = []struct {
 		runtime string
@@ -83,6 +96,34 @@ func TestImageExists(t *testing.T) {
 	}
 }
 
+func TestListImages(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		want    []string
+	}{
+		{"docker", []string{"k8s.gcr.io/pause:3.2", "busybox:latest"}},
+		{"crio", []string{"k8s.gcr.io/pause:3.2", "docker.io/library/busybox:latest"}},
+		{"containerd", []string{"k8s.gcr.io/pause:3.2", "docker.io/library/busybox:latest"}},
+		{"cri", []string{"k8s.gcr.io/pause:3.2", "docker.io/library/busybox:latest"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			r, err := New(Config{Type: tc.runtime, Runner: NewFakeRunner(t)})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+
+			got, err := r.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ListImages(%s) returned diff (-want +got):\n%s", tc.runtime, diff)
+			}
+		})
+	}
+}
+
 func TestCGroupDriver(t *testing.T) {
 	var tests = []struct {
 		runtime string
@@ -91,6 +132,7 @@ func TestCGroupDriver(t *testing.T) {
 		{"docker", "cgroupfs"},
 		{"crio", "cgroupfs"},
 		{"containerd", "cgroupfs"},
+		{"cri", "cgroupfs"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -113,25 +155,32 @@ func TestCGroupDriver(t *testing.T) {
 func TestKubeletOptions(t *testing.T) {
 	var tests = []struct {
 		runtime string
+		socket  string
 		want    map[string]string
 	}{
-		{"docker", map[string]string{"container-runtime": "docker"}},
-		{"crio", map[string]string{
+		{"docker", "", map[string]string{"container-runtime": "docker"}},
+		{"crio", "", map[string]string{
 			"container-runtime":          "remote",
 			"container-runtime-endpoint": "/var/run/crio/crio.sock",
 			"image-service-endpoint":     "/var/run/crio/crio.sock",
 			"runtime-request-timeout":    "15m",
 		}},
-		{"containerd", map[string]string{
+		{"containerd", "", map[string]string{
 			"container-runtime":          "remote",
 			"container-runtime-endpoint": "unix:///run/containerd/containerd.sock",
 			"image-service-endpoint":     "unix:///run/containerd/containerd.sock",
 			"runtime-request-timeout":    "15m",
 		}},
+		{"cri", "/run/kata/kata.sock", map[string]string{
+			"container-runtime":          "remote",
+			"container-runtime-endpoint": "unix:///run/kata/kata.sock",
+			"image-service-endpoint":     "unix:///run/kata/kata.sock",
+			"runtime-request-timeout":    "15m",
+		}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
-			r, err := New(Config{Type: tc.runtime})
+			r, err := New(Config{Type: tc.runtime, Socket: tc.socket})
 			if err != nil {
 				t.Fatalf("New(%s): %v", tc.runtime, err)
 			}
@@ -303,6 +352,11 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	case "inspect":
 		return f.dockerInspect(args)
 
+	case "images":
+		if args[1] == "--format" && args[2] == "{{.Repository}}:{{.Tag}}" {
+			return "k8s.gcr.io/pause:3.2\n<none>:<none>\nbusybox:latest\n", nil
+		}
+
 	case "info":
 
 		if args[1] == "--format" && args[2] == "{{.CgroupDriver}}" {
@@ -357,6 +411,12 @@ func (f *FakeRunner) containerd(args []string, _ bool) (string, error) {
 func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 	f.t.Logf("crictl args: %s", args)
 	switch cmd := args[0]; cmd {
+	case "version":
+		return `Version:  0.1.0
+RuntimeName:  containerd
+RuntimeVersion:  v1.4.1
+RuntimeApiVersion:  v1alpha2
+`, nil
 	case "info":
 		return `{
 		  "status": {
@@ -366,6 +426,16 @@ func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 		  },
 		  "golang": "go1.11.13"
 		}`, nil
+	case "images":
+		if args[1] == "--output" && args[2] == "json" {
+			return `{
+			  "images": [
+			    {"id": "sha256:80d28bedfe5d", "repoTags": ["k8s.gcr.io/pause:3.2"]},
+			    {"id": "sha256:1c35c4412082", "repoTags": ["docker.io/library/busybox:latest"]},
+			    {"id": "sha256:9f8c0a4c1b2d", "repoTags": []}
+			  ]
+			}`, nil
+		}
 	case "ps":
 		fmt.Printf("args %d: %v\n", len(args), args)
 		if len(args) != 4 {
@@ -510,6 +580,7 @@ func TestVersion(t *testing.T) {
 		{"docker", "18.06.2-ce"},
 		{"cri-o", "1.13.0"},
 		{"containerd", "1.2.0"},
+		{"cri", "1.4.1"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -637,6 +708,7 @@ func TestContainerFunctions(t *testing.T) {
 		{"docker"},
 		{"crio"},
 		{"containerd"},
+		{"cri"},
 	}
 
 	sortSlices := cmpopts.SortSlices(func(a, b string) bool { return a < b })
@@ -157,6 +157,65 @@ func (r *Docker) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this container runtime
+func (r *Docker) ListImages() ([]string, error) {
+	c := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "docker images")
+	}
+	var images []string
+	for _, img := range strings.Split(rr.Stdout.String(), "\n") {
+		// dangling images have no repository and no tag
+		if img == "" || strings.Contains(img, "<none>") {
+			continue
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image from this runtime
+func (r *Docker) RemoveImage(name string) error {
+	klog.Infof("Removing image: %s", name)
+	c := exec.Command("docker", "rmi", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "remove image docker.")
+	}
+	return nil
+}
+
+// PullImage pulls an image into this runtime
+func (r *Docker) PullImage(name string) error {
+	klog.Infof("Pulling image: %s", name)
+	c := exec.Command("docker", "pull", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "pull image docker.")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime
+func (r *Docker) BuildImage(o BuildImageOptions) error {
+	klog.Infof("Building image: %s", o.Dir)
+	args := []string{"build"}
+	if o.Tag != "" {
+		args = append(args, "-t", o.Tag)
+	}
+	if o.Dockerfile != "" {
+		args = append(args, "-f", path.Join(o.Dir, o.Dockerfile))
+	}
+	for _, a := range o.BuildArgs {
+		args = append(args, "--build-arg", a)
+	}
+	args = append(args, o.Dir)
+	c := exec.Command("docker", args...)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "build image docker.")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Docker) CGroupDriver() (string, error) {
 	// Note: the server daemon has to be running, for this call to return successfully
@@ -278,9 +337,38 @@ func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
 	return cmd.String()
 }
 
+// TimestampedContainerLogCmd returns the command to retrieve the timestamped log for a container based on ID
+func (r *Docker) TimestampedContainerLogCmd(id string, len int, since time.Duration) string {
+	var cmd strings.Builder
+	cmd.WriteString("docker logs --timestamps ")
+	if since > 0 {
+		cmd.WriteString(fmt.Sprintf("--since %ds ", int(since.Seconds())))
+	} else if len > 0 {
+		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
+	}
+
+	cmd.WriteString(id)
+	return cmd.String()
+}
+
+// ContainerInspectCmd returns the command to retrieve the low-level information of a container based on ID
+func (r *Docker) ContainerInspectCmd(id string) string {
+	return fmt.Sprintf("docker inspect %s", id)
+}
+
+// ContainerExecCmd returns the command to run a command within a container based on ID
+func (r *Docker) ContainerExecCmd(id string, args []string) string {
+	return fmt.Sprintf("docker exec %s %s", id, strings.Join(args, " "))
+}
+
 // SystemLogCmd returns the command to retrieve system logs
 func (r *Docker) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
+	return fmt.Sprintf("sudo journalctl -u %s -n %d", r.SystemLogUnit(), len)
+}
+
+// SystemLogUnit returns the systemd unit whose journal holds the system logs
+func (r *Docker) SystemLogUnit() string {
+	return "docker"
 }
 
 // ForceSystemd forces the docker daemon to use systemd as cgroup manager
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// GenericCRI drives any runtime which serves the CRI on a socket, using crictl.
+// The runtime itself is installed and managed outside of minikube.
+type GenericCRI struct {
+	Socket string
+	Runner CommandRunner
+}
+
+// Name is a human readable name for a generic CRI runtime
+func (r *GenericCRI) Name() string {
+	return "CRI"
+}
+
+// Style is the console style for a generic CRI runtime
+func (r *GenericCRI) Style() style.Enum {
+	return style.ContainerRuntime
+}
+
+// Version retrieves the version of the runtime serving the CRI socket
+func (r *GenericCRI) Version() (string, error) {
+	c := exec.Command("sudo", "crictl", "version")
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return "", errors.Wrap(err, "crictl version")
+	}
+	// RuntimeVersion:  1.4.1
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		f := strings.Fields(line)
+		if len(f) == 2 && f[0] == "RuntimeVersion:" {
+			return strings.TrimPrefix(f[1], "v"), nil
+		}
+	}
+	return "", fmt.Errorf("unknown version: %q", rr.Stdout.String())
+}
+
+// SocketPath returns the path to the CRI socket
+func (r *GenericCRI) SocketPath() string {
+	return strings.TrimPrefix(r.Socket, "unix://")
+}
+
+// Active returns if the CRI socket is being served
+func (r *GenericCRI) Active() bool {
+	c := exec.Command("sudo", "test", "-S", r.SocketPath())
+	_, err := r.Runner.RunCmd(c)
+	return err == nil
+}
+
+// Available returns an error if it is not possible to use this runtime on a host
+func (r *GenericCRI) Available() error {
+	if r.Socket == "" {
+		return errors.New("a CRI socket is required")
+	}
+	c := exec.Command("which", "crictl")
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "check crictl available.")
+	}
+	if !r.Active() {
+		return fmt.Errorf("no CRI socket at %s", r.SocketPath())
+	}
+	return nil
+}
+
+// Enable points crictl at the CRI socket.
+// Other runtimes are left alone, as the CRI may well be served by one of them.
+func (r *GenericCRI) Enable(_, _ bool) error {
+	if err := r.Available(); err != nil {
+		return err
+	}
+	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
+		return err
+	}
+	return enableIPForwarding(r.Runner)
+}
+
+// Disable does nothing, as the runtime is not managed by minikube
+func (r *GenericCRI) Disable() error {
+	return nil
+}
+
+// ImageExists checks if an image exists
+func (r *GenericCRI) ImageExists(name string, sha string) bool {
+	c := exec.Command("sudo", "crictl", "inspecti", "--output", "json", name)
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(rr.Output(), sha)
+}
+
+// LoadImage is not supported, as the CRI has no way to import image archives
+func (r *GenericCRI) LoadImage(path string) error {
+	return fmt.Errorf("loading %s: the CRI does not support loading images", path)
+}
+
+// ListImages returns a list of images managed by this container runtime
+func (r *GenericCRI) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from this runtime
+func (r *GenericCRI) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image into this runtime
+func (r *GenericCRI) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// BuildImage is not supported, as the CRI has no way to build images
+func (r *GenericCRI) BuildImage(o BuildImageOptions) error {
+	return fmt.Errorf("building %s: the CRI does not support building images", o.Tag)
+}
+
+// CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
+func (r *GenericCRI) CGroupDriver() (string, error) {
+	info, err := getCRIInfo(r.Runner)
+	if err != nil {
+		return "", err
+	}
+	// runtimes are free to report their configuration as they see fit, so look for the containerd convention
+	if cfg, ok := info["config"].(map[string]interface{}); ok {
+		if systemd, ok := cfg["systemdCgroup"].(bool); ok && systemd {
+			return "systemd", nil
+		}
+	}
+	return "cgroupfs", nil
+}
+
+// KubeletOptions returns kubelet options for a runtime.
+func (r *GenericCRI) KubeletOptions() map[string]string {
+	endpoint := fmt.Sprintf("unix://%s", r.SocketPath())
+	return map[string]string{
+		"container-runtime":          "remote",
+		"container-runtime-endpoint": endpoint,
+		"image-service-endpoint":     endpoint,
+		"runtime-request-timeout":    "15m",
+	}
+}
+
+// ListContainers returns a list of containers managed by this container runtime
+func (r *GenericCRI) ListContainers(o ListOptions) ([]string, error) {
+	if o.State != Running {
+		return listCRIContainers(r.Runner, "", o)
+	}
+
+	// Not every runtime is built on runc, so let crictl filter running containers
+	rr, err := crictlList(r.Runner, "", o)
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl list")
+	}
+	var ids []string
+	for _, id := range strings.Fields(rr.Stdout.String()) {
+		klog.Infof("found id: %q", id)
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// PauseContainers pauses a running container based on ID
+func (r *GenericCRI) PauseContainers(ids []string) error {
+	return pauseCRIContainers(r.Runner, "", ids)
+}
+
+// UnpauseContainers unpauses a running container based on ID
+func (r *GenericCRI) UnpauseContainers(ids []string) error {
+	return unpauseCRIContainers(r.Runner, "", ids)
+}
+
+// KillContainers removes containers based on ID
+func (r *GenericCRI) KillContainers(ids []string) error {
+	return killCRIContainers(r.Runner, ids)
+}
+
+// StopContainers stops containers based on ID
+func (r *GenericCRI) StopContainers(ids []string) error {
+	return stopCRIContainers(r.Runner, ids)
+}
+
+// ContainerLogCmd returns the command to retrieve the log for a container based on ID
+func (r *GenericCRI) ContainerLogCmd(id string, len int, follow bool) string {
+	return criContainerLogCmd(r.Runner, id, len, follow)
+}
+
+// ContainerExecCmd returns the command to run a command within a container based on ID
+func (r *GenericCRI) ContainerExecCmd(id string, args []string) string {
+	return criContainerExecCmd(r.Runner, id, args)
+}
+
+// TimestampedContainerLogCmd returns the command to retrieve the timestamped log for a container based on ID
+func (r *GenericCRI) TimestampedContainerLogCmd(id string, len int, since time.Duration) string {
+	return criTimestampedContainerLogCmd(r.Runner, id, len, since)
+}
+
+// ContainerInspectCmd returns the command to retrieve the low-level information of a container based on ID
+func (r *GenericCRI) ContainerInspectCmd(id string) string {
+	return criContainerInspectCmd(r.Runner, id)
+}
+
+// SystemLogCmd returns the command to retrieve system logs.
+// The service behind the socket is unknown, so this is the kubelet, which reports the CRI errors it sees.
+func (r *GenericCRI) SystemLogCmd(len int) string {
+	return fmt.Sprintf("sudo journalctl -u %s -n %d", r.SystemLogUnit(), len)
+}
+
+// SystemLogUnit returns the systemd unit whose journal holds the system logs
+func (r *GenericCRI) SystemLogUnit() string {
+	return "kubelet"
+}
+
+// Preload does nothing, as there are no preloaded images for generic runtimes
+func (r *GenericCRI) Preload(cfg config.KubernetesConfig) error {
+	return nil
+}
+
+// ImagesPreloaded returns false, as there are no preloaded images for generic runtimes
+func (r *GenericCRI) ImagesPreloaded(images []string) bool {
+	return false
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/blang/semver"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RuntimeClassLabel marks the RuntimeClass objects registered by minikube
+const RuntimeClassLabel = "minikube.k8s.io/runtime-class"
+
+var runtimeClassTmpl = template.Must(template.New("runtimeclass").Parse(`{{range $i, $c := .Classes}}{{if $i}}---
+{{end}}apiVersion: {{$.APIVersion}}
+kind: RuntimeClass
+metadata:
+  name: {{$c.Name}}
+  labels:
+    {{$.Label}}: "true"
+handler: {{$c.Handler}}
+{{end}}`))
+
+// ParseRuntimeClasses parses runtime classes given as name=handler pairs
+func ParseRuntimeClasses(pairs []string) (map[string]string, error) {
+	classes := map[string]string{}
+	for _, p := range pairs {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid runtime class %q, expected NAME=HANDLER", p)
+		}
+		if err := ValidateRuntimeClass(kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+		classes[kv[0]] = kv[1]
+	}
+	return classes, nil
+}
+
+// ValidateRuntimeClass checks that a runtime class and its handler are acceptable to the API server
+func ValidateRuntimeClass(name string, handler string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid runtime class name %q: %s", name, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsDNS1123Label(handler); len(errs) > 0 {
+		return fmt.Errorf("invalid handler %q for runtime class %s: %s", handler, name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// RuntimeClassManifest returns the RuntimeClass objects for the given name to handler mapping
+func RuntimeClassManifest(classes map[string]string, kv semver.Version) ([]byte, error) {
+	if kv.LT(semver.MustParse("1.14.0")) {
+		return nil, fmt.Errorf("runtime classes require Kubernetes v1.14.0 or newer")
+	}
+	apiVersion := "node.k8s.io/v1beta1"
+	if kv.GTE(semver.MustParse("1.20.0")) {
+		apiVersion = "node.k8s.io/v1"
+	}
+
+	type class struct{ Name, Handler string }
+	var cs []class
+	for name, handler := range classes {
+		cs = append(cs, class{name, handler})
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Name < cs[j].Name })
+
+	var b bytes.Buffer
+	opts := struct {
+		APIVersion string
+		Label      string
+		Classes    []class
+	}{apiVersion, RuntimeClassLabel, cs}
+	if err := runtimeClassTmpl.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
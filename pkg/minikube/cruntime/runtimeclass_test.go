@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRuntimeClasses(t *testing.T) {
+	var tests = []struct {
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{[]string{"kata=kata", "gvisor=runsc"}, map[string]string{"kata": "kata", "gvisor": "runsc"}, false},
+		{[]string{"kata"}, nil, true},
+		{[]string{"Kata=kata"}, nil, true},
+		{[]string{"kata=kata.qemu"}, nil, true},
+	}
+	for _, tc := range tests {
+		got, err := ParseRuntimeClasses(tc.pairs)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseRuntimeClasses(%v) error = %v, wantErr %v", tc.pairs, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got); !tc.wantErr && diff != "" {
+			t.Errorf("ParseRuntimeClasses(%v) returned diff (-want +got):\n%s", tc.pairs, diff)
+		}
+	}
+}
+
+func TestRuntimeClassManifest(t *testing.T) {
+	classes := map[string]string{"kata": "kata", "gvisor": "runsc"}
+	want := `apiVersion: node.k8s.io/v1beta1
+kind: RuntimeClass
+metadata:
+  name: gvisor
+  labels:
+    minikube.k8s.io/runtime-class: "true"
+handler: runsc
+---
+apiVersion: node.k8s.io/v1beta1
+kind: RuntimeClass
+metadata:
+  name: kata
+  labels:
+    minikube.k8s.io/runtime-class: "true"
+handler: kata
+`
+	got, err := RuntimeClassManifest(classes, semver.MustParse("1.19.2"))
+	if err != nil {
+		t.Fatalf("RuntimeClassManifest: %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("RuntimeClassManifest returned diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := RuntimeClassManifest(classes, semver.MustParse("1.13.0")); err == nil {
+		t.Errorf("RuntimeClassManifest should reject Kubernetes v1.13.0")
+	}
+}
@@ -124,7 +124,7 @@ func PreloadExists(k8sVersion, containerRuntime string, forcePreload ...bool) bo
 }
 
 // Preload caches the preloaded images tarball on the host machine
-func Preload(k8sVersion, containerRuntime string) error {
+func Preload(k8sVersion, containerRuntime string, forcePreload ...bool) error {
 	targetPath := TarballPath(k8sVersion, containerRuntime)
 
 	if _, err := os.Stat(targetPath); err == nil {
@@ -133,7 +133,7 @@ func Preload(k8sVersion, containerRuntime string) error {
 	}
 
 	// Make sure we support this k8s version
-	if !PreloadExists(k8sVersion, containerRuntime) {
+	if !PreloadExists(k8sVersion, containerRuntime, forcePreload...) {
 		klog.Infof("Preloaded tarball for k8s version %s does not exist", k8sVersion)
 		return nil
 	}
@@ -53,6 +53,8 @@ const (
 	HyperV = "hyperv"
 	// Parallels driver
 	Parallels = "parallels"
+	// SSH driver
+	SSH = "ssh"
 )
 
 var (
@@ -94,6 +96,10 @@ func MachineType(name string) string {
 		return "container"
 	}
 
+	if IsSSH(name) {
+		return "existing machine"
+	}
+
 	if IsVM(name) {
 		return "VM"
 	}
@@ -128,9 +134,14 @@ func IsMock(name string) bool {
 	return name == Mock
 }
 
+// IsSSH checks if the driver adopts existing machines over SSH
+func IsSSH(name string) bool {
+	return name == SSH
+}
+
 // IsVM checks if the driver is a VM
 func IsVM(name string) bool {
-	if IsKIC(name) || BareMetal(name) {
+	if IsKIC(name) || BareMetal(name) || IsSSH(name) {
 		return false
 	}
 	return true
@@ -292,13 +303,23 @@ func SetLibvirtURI(v string) {
 
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc config.ClusterConfig, n config.Node) string {
-	// For single node cluster, default to back to old naming
-	if len(cc.Nodes) == 1 || n.ControlPlane {
+	// For single node cluster and the primary control plane, default to back to old naming
+	if len(cc.Nodes) == 1 || (n.ControlPlane && n.Name == primaryControlPlaneName(cc)) {
 		return cc.Name
 	}
 	return fmt.Sprintf("%s-%s", cc.Name, n.Name)
 }
 
+// primaryControlPlaneName returns the name of the first control plane of the cluster
+func primaryControlPlaneName(cc config.ClusterConfig) string {
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			return n.Name
+		}
+	}
+	return ""
+}
+
 // IndexFromMachineName returns the order of the container based on it is name
 func IndexFromMachineName(machineName string) int {
 	// minikube-m02
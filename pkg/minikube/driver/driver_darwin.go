@@ -27,6 +27,7 @@ var supportedDrivers = []string{
 	VMware,
 	Docker,
 	Podman,
+	SSH,
 }
 
 func VBoxManagePath() string {
@@ -59,6 +59,15 @@ func TestBareMetal(t *testing.T) {
 	}
 }
 
+func TestIsSSH(t *testing.T) {
+	if !IsSSH(SSH) {
+		t.Errorf("IsSSH(%s) is false", SSH)
+	}
+	if IsVM(SSH) || BareMetal(SSH) {
+		t.Errorf("the %s driver should be neither a VM nor bare metal", SSH)
+	}
+}
+
 func TestMachineType(t *testing.T) {
 	types := map[string]string{
 		Podman:       "container",
@@ -72,6 +81,7 @@ func TestMachineType(t *testing.T) {
 		VMwareFusion: "VM",
 		HyperV:       "VM",
 		Parallels:    "VM",
+		SSH:          "existing machine",
 	}
 
 	drivers := SupportedDrivers()
@@ -247,6 +257,30 @@ func TestMachineName(t *testing.T) {
 			},
 			Want: "p2-m2",
 		},
+
+		{
+			ClusterConfig: config.ClusterConfig{Name: "p3",
+				Nodes: []config.Node{
+					{
+						Name:              "",
+						IP:                "172.17.0.3",
+						Port:              8443,
+						KubernetesVersion: "v1.19.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+					{
+						Name:              "m02",
+						IP:                "172.17.0.4",
+						Port:              8443,
+						KubernetesVersion: "v1.19.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+				},
+			},
+			Want: "p3-m02",
+		},
 	}
 
 	for _, tc := range testsCases {
@@ -28,7 +28,7 @@ import (
 // ControlPlaneEndpoint returns the location where callers can reach this cluster
 func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
 	if NeedsPortForward(driverName) {
-		port, err := oci.ForwardedPort(cc.Driver, cc.Name, cp.Port)
+		port, err := oci.ForwardedPort(cc.Driver, MachineName(*cc, *cp), cp.Port)
 		hostname := oci.DefaultBindIPV4
 		ip := net.ParseIP(hostname)
 		if ip == nil {
@@ -53,3 +53,23 @@ func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName
 	}
 	return hostname, ip, cp.Port, nil
 }
+
+// APIServerEndpoint returns the location where callers can reach the API server of this cluster,
+// which is the virtual IP for highly available clusters whenever the host can route to it.
+// Drivers which need a port forward fall back to the first control plane, without failover.
+func APIServerEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
+	vip := cc.KubernetesConfig.APIServerHAVIP
+	if vip == "" || NeedsPortForward(driverName) {
+		return ControlPlaneEndpoint(cc, cp, driverName)
+	}
+
+	hostname := vip
+	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
+		hostname = cc.KubernetesConfig.APIServerName
+	}
+	ip := net.ParseIP(vip)
+	if ip == nil {
+		return hostname, ip, cp.Port, fmt.Errorf("failed to parse ip for %q", vip)
+	}
+	return hostname, ip, cp.Port, nil
+}
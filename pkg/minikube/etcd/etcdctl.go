@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package etcd manages the etcd data of the control planes of a cluster
+package etcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// clientPort is the port etcd serves clients on
+	clientPort = 2379
+	// peerPort is the port etcd serves its peers on
+	peerPort = 2380
+)
+
+// certsDir is where kubeadm keeps the etcd certificates, alongside the ones from bootstrapper.SetupCerts.
+// The etcd static pod mounts it, and the data directory, at the same paths as the node.
+var certsDir = path.Join(vmpath.GuestKubernetesCertsDir, "etcd")
+
+// Member is the etcd member of a control plane
+type Member struct {
+	// Name is the etcd name of the member, which kubeadm sets to the node name
+	Name string
+	// Machine is the name of the machine running the member
+	Machine string
+	IP      string
+	Runner  command.Runner
+	CR      cruntime.Manager
+}
+
+// Status is the status of an etcd member, as reported by 'etcdctl endpoint status'
+type Status struct {
+	Endpoint string `json:"Endpoint"`
+	Status   struct {
+		Header struct {
+			ClusterID uint64 `json:"cluster_id"`
+			MemberID  uint64 `json:"member_id"`
+			Revision  int64  `json:"revision"`
+		} `json:"header"`
+		Version   string `json:"version"`
+		DBSize    int64  `json:"dbSize"`
+		Leader    uint64 `json:"leader"`
+		RaftIndex uint64 `json:"raftIndex"`
+		RaftTerm  uint64 `json:"raftTerm"`
+	} `json:"Status"`
+}
+
+// IsLeader returns whether the member is the leader of the cluster
+func (s Status) IsLeader() bool {
+	return s.Status.Leader != 0 && s.Status.Leader == s.Status.Header.MemberID
+}
+
+// Members returns the etcd members of a running cluster, starting with the primary control plane
+func Members(api libmachine.API, cc config.ClusterConfig) ([]Member, error) {
+	cp, err := config.PrimaryControlPlane(&cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "primary control plane")
+	}
+	nodes := []config.Node{cp}
+	for _, n := range cc.Nodes {
+		if n.ControlPlane && n.Name != cp.Name {
+			nodes = append(nodes, n)
+		}
+	}
+
+	var members []Member
+	for _, n := range nodes {
+		name := driver.MachineName(cc, n)
+		h, err := machine.LoadHost(api, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading host %s", name)
+		}
+		r, err := machine.CommandRunner(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "command runner for %s", name)
+		}
+		cr, err := cruntime.ForCluster(cc.KubernetesConfig, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "runtime")
+		}
+		members = append(members, Member{Name: bsutil.KubeNodeName(cc, n), Machine: name, IP: n.IP, Runner: r, CR: cr})
+	}
+	return members, nil
+}
+
+// SaveSnapshot takes a snapshot of the keyspace of a member, and writes it to w
+func SaveSnapshot(m Member, w io.Writer) error {
+	snapshot := snapshotPath()
+	if _, err := etcdctl(m, "snapshot", "save", snapshot); err != nil {
+		return errors.Wrap(err, "snapshot save")
+	}
+	defer func() {
+		if _, err := m.Runner.RunCmd(exec.Command("sudo", "rm", "-f", snapshot)); err != nil {
+			klog.Warningf("unable to remove %s: %v", snapshot, err)
+		}
+	}()
+
+	c := exec.Command("sudo", "cat", snapshot)
+	c.Stdout = w
+	if rr, err := m.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "reading snapshot: %s", rr.Stderr.String())
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the data of every member with a snapshot from the host, so that they form a new cluster holding its keyspace.
+// etcd, along with the rest of the control plane, is restarted on each member.
+func RestoreSnapshot(members []Member, src string) error {
+	snapshot := snapshotPath()
+	restoreDir := path.Join(bsutil.EtcdDataDir(), "restore")
+
+	var peers []string
+	for _, m := range members {
+		peers = append(peers, fmt.Sprintf("%s=%s", m.Name, peerURL(m)))
+	}
+
+	// etcdctl restores into a new data directory beside the live one, so that etcd keeps running until every member is ready
+	for _, m := range members {
+		klog.Infof("restoring snapshot %s on %s", src, m.Machine)
+		if err := machine.CopyToNode(m.Runner, src, snapshot); err != nil {
+			return errors.Wrapf(err, "copying snapshot to %s", m.Machine)
+		}
+		if rr, err := m.Runner.RunCmd(exec.Command("sudo", "rm", "-rf", restoreDir)); err != nil {
+			return errors.Wrapf(err, "removing %s: %s", restoreDir, rr.Output())
+		}
+		_, err := etcdctl(m, "snapshot", "restore", snapshot,
+			"--data-dir", restoreDir,
+			"--name", m.Name,
+			"--initial-cluster", strings.Join(peers, ","),
+			"--initial-advertise-peer-urls", peerURL(m))
+		if err != nil {
+			return errors.Wrapf(err, "snapshot restore on %s", m.Machine)
+		}
+	}
+
+	for _, m := range members {
+		sm := sysinit.New(m.Runner)
+		if err := stop(sm, m.CR); err != nil {
+			return errors.Wrapf(err, "stopping etcd on %s", m.Machine)
+		}
+		member := path.Join(bsutil.EtcdDataDir(), "member")
+		c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo rm -rf %s && sudo mv %s %s && sudo rm -rf %s %s", member, path.Join(restoreDir, "member"), member, restoreDir, snapshot))
+		if rr, err := m.Runner.RunCmd(c); err != nil {
+			return errors.Wrapf(err, "replacing data on %s: %s", m.Machine, rr.Output())
+		}
+		if err := sm.Start("kubelet"); err != nil {
+			return errors.Wrapf(err, "starting kubelet on %s", m.Machine)
+		}
+	}
+	return nil
+}
+
+// MemberStatus returns the status of a member
+func MemberStatus(m Member) (*Status, error) {
+	rr, err := etcdctl(m, "endpoint", "status", "--write-out=json")
+	if err != nil {
+		return nil, errors.Wrap(err, "endpoint status")
+	}
+	return parseStatus(rr.Stdout.Bytes())
+}
+
+// parseStatus parses the output of 'etcdctl endpoint status --write-out=json' for a single endpoint
+func parseStatus(b []byte) (*Status, error) {
+	var st []Status
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, errors.Wrapf(err, "parsing %q", b)
+	}
+	if len(st) != 1 {
+		return nil, fmt.Errorf("expected the status of 1 endpoint, got %d", len(st))
+	}
+	return &st[0], nil
+}
+
+// etcdctl runs etcdctl within the etcd container of a member, authenticating with the kubeadm healthcheck client certificate
+func etcdctl(m Member, args ...string) (*command.RunResult, error) {
+	ids, err := m.CR.ListContainers(cruntime.ListOptions{State: cruntime.Running, Name: "etcd", Namespaces: []string{"kube-system"}})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing etcd containers")
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("etcd is not running on %s", m.Machine)
+	}
+
+	args = append([]string{
+		"etcdctl",
+		fmt.Sprintf("--endpoints=https://127.0.0.1:%d", clientPort),
+		"--cacert=" + path.Join(certsDir, "ca.crt"),
+		"--cert=" + path.Join(certsDir, "healthcheck-client.crt"),
+		"--key=" + path.Join(certsDir, "healthcheck-client.key"),
+	}, args...)
+	rr, err := m.Runner.RunCmd(exec.Command("/bin/bash", "-c", m.CR.ContainerExecCmd(ids[0], args)))
+	if err != nil {
+		return rr, errors.Wrapf(err, "etcdctl: %s", rr.Output())
+	}
+	return rr, nil
+}
+
+// peerURL returns the URL a member serves its peers on
+func peerURL(m Member) string {
+	return fmt.Sprintf("https://%s:%d", m.IP, peerPort)
+}
+
+// snapshotPath is where snapshots are kept on a node. It is within the data directory, so that it is visible to etcdctl in the etcd container.
+func snapshotPath() string {
+	return path.Join(bsutil.EtcdDataDir(), "minikube-snapshot.db")
+}
+
+// stop stops the kubelet, so that it does not restart etcd, then etcd
+func stop(sm sysinit.Manager, cr cruntime.Manager) error {
+	if err := sm.Stop("kubelet"); err != nil {
+		return errors.Wrap(err, "stopping kubelet")
+	}
+	ids, err := cr.ListContainers(cruntime.ListOptions{Name: "etcd", Namespaces: []string{"kube-system"}})
+	if err != nil {
+		return errors.Wrap(err, "listing etcd containers")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return cr.StopContainers(ids)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	out := `[{"Endpoint":"https://127.0.0.1:2379","Status":{"header":{"cluster_id":9938541356018441022,"member_id":12593026477526642892,"revision":721,"raft_term":2},"version":"3.4.13","dbSize":2134016,"leader":12593026477526642892,"raftIndex":789,"raftTerm":2,"raftAppliedIndex":789,"dbSizeInUse":2134016}}]`
+	st, err := parseStatus([]byte(out))
+	if err != nil {
+		t.Fatalf("parseStatus: %v", err)
+	}
+	if st.Status.Version != "3.4.13" || st.Status.DBSize != 2134016 || st.Status.RaftIndex != 789 || st.Status.Header.Revision != 721 {
+		t.Errorf("parseStatus returned %+v", st)
+	}
+	if !st.IsLeader() {
+		t.Errorf("IsLeader() = false, expected true")
+	}
+
+	st.Status.Leader = 1
+	if st.IsLeader() {
+		t.Errorf("IsLeader() = true, expected false")
+	}
+
+	for _, bad := range []string{"", "[]", "Error: context deadline exceeded"} {
+		if _, err := parseStatus([]byte(bad)); err == nil {
+			t.Errorf("parseStatus(%q) should fail", bad)
+		}
+	}
+}
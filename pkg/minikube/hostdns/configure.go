@@ -0,0 +1,390 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// The modes of resolving the names of a cluster on the host
+const (
+	// ModeHosts writes the names to a block of the hosts file, which can not resolve every name of the domain
+	ModeHosts = "hosts"
+	// ModeResolved forwards the domains from systemd-resolved to the DNS server of minikube
+	ModeResolved = "systemd-resolved"
+	// ModeResolver forwards the domains from the macOS resolver to the DNS server of minikube
+	ModeResolver = "resolver"
+)
+
+// Modes are the modes of resolving the names of a cluster on the host
+var Modes = []string{ModeHosts, ModeResolved, ModeResolver}
+
+// resolvedDir holds the configuration drop-ins of systemd-resolved
+const resolvedDir = "/etc/systemd/resolved.conf.d"
+
+// resolverDir holds the configuration of the macOS resolver, one file per domain
+const resolverDir = "/etc/resolver"
+
+// minResolvedVersion is the first version of systemd-resolved supporting DNS servers on other ports than 53
+const minResolvedVersion = 246
+
+// Configurator configures the host to resolve the names of a cluster. Configuring the host requires root privileges.
+type Configurator interface {
+	// Apply configures the host for the current records
+	Apply(r *Records) error
+	// Configured returns whether the host is configured for the current records
+	Configured(r *Records) bool
+	// Cleanup removes the configuration of the host
+	Cleanup() error
+}
+
+// NewConfigurator returns the configurator of a mode, forwarding to the DNS server of minikube on port
+func NewConfigurator(mode string, profile string, port int) (Configurator, error) {
+	switch mode {
+	case ModeHosts:
+		return &hostsFile{path: hostsPath(), profile: profile}, nil
+	case ModeResolved:
+		return &resolved{profile: profile, port: port}, nil
+	case ModeResolver:
+		return &resolver{dir: resolverDir, profile: profile, port: port}, nil
+	}
+	return nil, fmt.Errorf("unknown mode %q, expected one of %s", mode, strings.Join(Modes, ", "))
+}
+
+// DetectMode returns the mode supported by the host: the resolver of macOS, systemd-resolved when it manages
+// /etc/resolv.conf on Linux, and the hosts file on other systems. Linux without systemd-resolved is unsupported, as
+// the hosts file can not resolve the names within the domain of the cluster.
+func DetectMode() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return ModeResolver, nil
+	case "linux":
+		b, err := ioutil.ReadFile("/etc/resolv.conf")
+		if err != nil || !strings.Contains(string(b), "nameserver 127.0.0.53") {
+			return "", errors.Errorf("/etc/resolv.conf is not managed by systemd-resolved. Linux hosts without systemd-resolved are not supported, as nothing else forwards the domain of the cluster. --mode=%s is a partial fallback, which resolves neither the names within the domain nor wildcard hosts", ModeHosts)
+		}
+		if err := Check(ModeResolved); err != nil {
+			return "", err
+		}
+		return ModeResolved, nil
+	}
+	return ModeHosts, nil
+}
+
+// Check returns an error if the host does not support a mode
+func Check(mode string) error {
+	if mode != ModeResolved {
+		return nil
+	}
+	out, err := exec.Command("systemctl", "--version").Output()
+	if err != nil {
+		return errors.Wrap(err, "systemctl --version")
+	}
+	v, err := systemdVersion(string(out))
+	if err != nil {
+		return err
+	}
+	if v < minResolvedVersion {
+		return errors.Errorf("systemd-resolved %d does not support DNS servers on other ports than 53, which requires systemd %d. Older versions are not supported. --mode=%s is a partial fallback, which resolves neither the names within the domain nor wildcard hosts", v, minResolvedVersion, ModeHosts)
+	}
+	return nil
+}
+
+// systemdVersion returns the version of systemd from the output of 'systemctl --version', such as "systemd 245 (245.4-4ubuntu3)"
+func systemdVersion(out string) (int, error) {
+	fields := strings.Fields(out)
+	if len(fields) < 2 || fields[0] != "systemd" {
+		return 0, errors.Errorf("unexpected output of systemctl --version: %q", out)
+	}
+	v, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, errors.Wrapf(err, "systemd version %q", fields[1])
+	}
+	return v, nil
+}
+
+// Cleanup removes the configuration of the host for a profile, in every mode
+func Cleanup(profile string) error {
+	if err := Stop(profile, stopTimeout); err != nil {
+		klog.Warningf("unable to stop the DNS server of %s: %v", profile, err)
+	}
+	var errs []string
+	for _, mode := range Modes {
+		c, err := NewConfigurator(mode, profile, 0)
+		if err != nil {
+			return err
+		}
+		if err := c.Cleanup(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", mode, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// marker marks the configuration written for a profile
+func marker(profile string) string {
+	return "# minikube " + profile
+}
+
+// hostsFile resolves the names in a block of the hosts file
+type hostsFile struct {
+	path    string
+	profile string
+}
+
+func (h *hostsFile) Apply(r *Records) error {
+	return h.update(r.Hosts())
+}
+
+func (h *hostsFile) Configured(r *Records) bool {
+	b, err := ioutil.ReadFile(h.path)
+	return err == nil && hostsBlock(string(b), h.profile, r.Hosts()) == string(b)
+}
+
+func (h *hostsFile) Cleanup() error {
+	return h.update(nil)
+}
+
+func (h *hostsFile) update(hosts map[string]net.IP) error {
+	b, err := ioutil.ReadFile(h.path)
+	if err != nil {
+		if os.IsNotExist(err) && hosts == nil {
+			return nil
+		}
+		return errors.Wrap(err, "read hosts")
+	}
+	updated := hostsBlock(string(b), h.profile, hosts)
+	if updated == string(b) {
+		return nil
+	}
+	klog.Infof("updating the hosts of %s in %s", h.profile, h.path)
+	return writeFile(h.path, updated)
+}
+
+// hostsBlock replaces the block of a profile in the content of a hosts file with the entries of hosts, or removes it
+// without any. Wildcard hosts are left out, as hosts files do not support them.
+func hostsBlock(content string, profile string, hosts map[string]net.IP) string {
+	begin := marker(profile) + " begin"
+	end := marker(profile) + " end"
+
+	var lines []string
+	in := false
+	for _, l := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		switch {
+		case l == begin:
+			in = true
+		case l == end:
+			in = false
+		case !in:
+			lines = append(lines, l)
+		}
+	}
+
+	var names []string
+	for h := range hosts {
+		if !strings.HasPrefix(h, "*.") {
+			names = append(names, h)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		lines = append(lines, begin)
+		for _, n := range names {
+			lines = append(lines, fmt.Sprintf("%s\t%s", hosts[n], n))
+		}
+		lines = append(lines, end)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func hostsPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// resolved forwards the domains from systemd-resolved, which supports DNS servers on other ports than 53 from v246
+type resolved struct {
+	profile string
+	port    int
+}
+
+func (c *resolved) path() string {
+	return filepath.Join(resolvedDir, fmt.Sprintf("minikube-%s.conf", c.profile))
+}
+
+func (c *resolved) Apply(r *Records) error {
+	if c.Configured(r) {
+		return nil
+	}
+	klog.Infof("forwarding %s from systemd-resolved to port %d", r.Domains(), c.port)
+	if err := sudo("mkdir", "-p", resolvedDir); err != nil {
+		return err
+	}
+	if err := writeFile(c.path(), resolvedConf(c.profile, c.port, r.Domains())); err != nil {
+		return err
+	}
+	return sudo("systemctl", "restart", "systemd-resolved")
+}
+
+func (c *resolved) Configured(r *Records) bool {
+	b, err := ioutil.ReadFile(c.path())
+	return err == nil && string(b) == resolvedConf(c.profile, c.port, r.Domains())
+}
+
+func (c *resolved) Cleanup() error {
+	if _, err := os.Stat(c.path()); os.IsNotExist(err) {
+		return nil
+	}
+	if err := sudo("rm", "-f", c.path()); err != nil {
+		return err
+	}
+	return sudo("systemctl", "restart", "systemd-resolved")
+}
+
+// resolvedConf returns the drop-in routing the domains to the DNS server of minikube
+func resolvedConf(profile string, port int, domains []string) string {
+	var routes []string
+	for _, d := range domains {
+		routes = append(routes, "~"+d)
+	}
+	return fmt.Sprintf("%s\n[Resolve]\nDNS=127.0.0.1:%d\nDomains=%s\n", marker(profile), port, strings.Join(routes, " "))
+}
+
+// resolver forwards the domains from the macOS resolver, with a file per domain
+type resolver struct {
+	dir     string
+	profile string
+	port    int
+}
+
+func (c *resolver) conf() string {
+	return fmt.Sprintf("%s\nnameserver 127.0.0.1\nport %d\n", marker(c.profile), c.port)
+}
+
+// configured returns whether the file of a domain forwards it to the DNS server of minikube
+func (c *resolver) configured(domain string) bool {
+	b, err := ioutil.ReadFile(filepath.Join(c.dir, domain))
+	return err == nil && string(b) == c.conf()
+}
+
+func (c *resolver) Apply(r *Records) error {
+	domains := map[string]bool{}
+	for _, d := range r.Domains() {
+		domains[d] = true
+		if c.configured(d) {
+			continue
+		}
+		klog.Infof("forwarding %s from the resolver to port %d", d, c.port)
+		if err := sudo("mkdir", "-p", c.dir); err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(c.dir, d), c.conf()); err != nil {
+			return err
+		}
+	}
+	return c.remove(func(d string) bool { return !domains[d] })
+}
+
+func (c *resolver) Configured(r *Records) bool {
+	for _, d := range r.Domains() {
+		if !c.configured(d) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *resolver) Cleanup() error {
+	return c.remove(func(string) bool { return true })
+}
+
+// remove removes the files of the profile for the domains matching
+func (c *resolver) remove(match func(domain string) bool) error {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, f := range files {
+		p := filepath.Join(c.dir, f.Name())
+		b, err := ioutil.ReadFile(p)
+		if err != nil || !strings.HasPrefix(string(b), marker(c.profile)+"\n") || !match(f.Name()) {
+			continue
+		}
+		if err := sudo("rm", "-f", p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile replaces the content of a file owned by root, keeping its permissions
+func writeFile(path string, content string) error {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		return ioutil.WriteFile(path, []byte(content), 0644)
+	}
+
+	tf, err := ioutil.TempFile("", "minikube-dns-")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tf.Name())
+	if _, err := tf.WriteString(content); err != nil {
+		return errors.Wrap(err, "write")
+	}
+	if err := tf.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+	if err := os.Chmod(tf.Name(), 0644); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	// cp writes to the existing file rather than replacing it, which keeps its owner
+	return sudo("cp", tf.Name(), path)
+}
+
+// sudo runs a command as root
+func sudo(args ...string) error {
+	var cmd *exec.Cmd
+	if os.Geteuid() == 0 {
+		cmd = exec.Command(args[0], args[1:]...)
+	} else {
+		cmd = exec.Command("sudo", args...)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%s: %s", strings.Join(cmd.Args, " "), out)
+	}
+	return nil
+}
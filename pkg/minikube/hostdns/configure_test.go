@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHostsBlock(t *testing.T) {
+	hosts := map[string]net.IP{
+		"minikube.test":      net.ParseIP("192.168.49.2"),
+		"web.example.com":    net.ParseIP("192.168.49.200"),
+		"*.apps.example.com": net.ParseIP("192.168.49.2"),
+		"shop.minikube.test": net.ParseIP("192.168.49.2"),
+	}
+	content := "127.0.0.1\tlocalhost\n# minikube other begin\n192.168.58.2\tother.test\n# minikube other end\n"
+
+	got := hostsBlock(content, "minikube", hosts)
+	want := content + "# minikube minikube begin\n192.168.49.2\tminikube.test\n192.168.49.2\tshop.minikube.test\n192.168.49.200\tweb.example.com\n# minikube minikube end\n"
+	if got != want {
+		t.Errorf("hostsBlock() =\n%s\nwant:\n%s", got, want)
+	}
+
+	// the block is replaced rather than appended again
+	hosts = map[string]net.IP{"minikube.test": net.ParseIP("192.168.49.3")}
+	want = content + "# minikube minikube begin\n192.168.49.3\tminikube.test\n# minikube minikube end\n"
+	if got = hostsBlock(got, "minikube", hosts); got != want {
+		t.Errorf("hostsBlock() of an updated block =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got = hostsBlock(got, "minikube", nil); got != content {
+		t.Errorf("hostsBlock() without hosts =\n%s\nwant:\n%s", got, content)
+	}
+}
+
+func TestResolvedConf(t *testing.T) {
+	got := resolvedConf("minikube", 35353, []string{"example.com", "minikube.test"})
+	want := "# minikube minikube\n[Resolve]\nDNS=127.0.0.1:35353\nDomains=~example.com ~minikube.test\n"
+	if got != want {
+		t.Errorf("resolvedConf() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSystemdVersion(t *testing.T) {
+	var tests = []struct {
+		out      string
+		expected int
+		err      bool
+	}{
+		{"systemd 245 (245.4-4ubuntu3)\n+PAM +AUDIT +SELINUX", 245, false},
+		{"systemd 252 (252.39-1~deb12u1)\n", 252, false},
+		{"", 0, true},
+		{"systemd unknown", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := systemdVersion(tc.out)
+		if (err != nil) != tc.err || got != tc.expected {
+			t.Errorf("systemdVersion(%q) = %d, %v, expected %d (error: %v)", tc.out, got, err, tc.expected, tc.err)
+		}
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minikube-dns")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	records := NewRecords("minikube", net.ParseIP("192.168.49.2"))
+	records.SetHosts(map[string]net.IP{"web.example.com": net.ParseIP("192.168.49.2")})
+	conf := "# minikube minikube\nnameserver 127.0.0.1\nport 35353\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "minikube.test"), []byte(conf), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	c := &resolver{dir: dir, profile: "minikube", port: 35353}
+	if c.Configured(records) {
+		t.Errorf("Configured() = true, expected false as example.com is not forwarded")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "web.example.com"), []byte(conf), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !c.Configured(records) {
+		t.Errorf("Configured() = false, expected true once every domain is forwarded")
+	}
+	// a DNS server enabled again listens on another port
+	c.port = 35354
+	if c.Configured(records) {
+		t.Errorf("Configured() = true, expected false for another port")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"net"
+	"time"
+
+	networking "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// WatchIngresses calls update with the hosts of the Ingresses of a cluster whenever they change, until stop is closed.
+// Hosts resolve to the address of their Ingress if useStatus is set and it has one, and to ip otherwise.
+func WatchIngresses(client kubernetes.Interface, ip net.IP, useStatus bool, stop <-chan struct{}, update func(map[string]net.IP)) {
+	factory := informers.NewSharedInformerFactory(client, time.Minute)
+	ingresses := factory.Networking().V1beta1().Ingresses()
+	sync := func(interface{}) {
+		ings, err := ingresses.Lister().List(labels.Everything())
+		if err != nil {
+			klog.Warningf("unable to list ingresses: %v", err)
+			return
+		}
+		update(IngressHosts(ings, ip, useStatus))
+	}
+	ingresses.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sync,
+		UpdateFunc: func(_, obj interface{}) { sync(obj) },
+		DeleteFunc: sync,
+	})
+	factory.Start(stop)
+}
+
+// IngressHosts returns the IPs of the hosts of Ingresses
+func IngressHosts(ings []*networking.Ingress, ip net.IP, useStatus bool) map[string]net.IP {
+	hosts := map[string]net.IP{}
+	for _, ing := range ings {
+		target := ip
+		if useStatus {
+			for _, lb := range ing.Status.LoadBalancer.Ingress {
+				if lbIP := net.ParseIP(lb.IP); lbIP != nil {
+					target = lbIP
+					break
+				}
+			}
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != "" {
+				hosts[normalize(rule.Host)] = target
+			}
+		}
+	}
+	return hosts
+}
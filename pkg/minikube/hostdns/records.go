@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Records are the names of a cluster resolved on the host
+type Records struct {
+	// Domain is resolved to the IP of the cluster, along with every name within it
+	Domain string
+	IP     net.IP
+
+	mu    sync.RWMutex
+	hosts map[string]net.IP
+}
+
+// NewRecords returns the records of a cluster, whose domain is <profile>.test
+func NewRecords(profile string, ip net.IP) *Records {
+	return &Records{Domain: profile + ".test", IP: ip, hosts: map[string]net.IP{}}
+}
+
+// SetHosts replaces the hosts resolved besides the domain, returning whether they changed. Hosts starting with *.
+// match a single label.
+func (r *Records) SetHosts(hosts map[string]net.IP) bool {
+	normalized := map[string]net.IP{}
+	for h, ip := range hosts {
+		normalized[normalize(h)] = ip
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := len(normalized) != len(r.hosts)
+	for h, ip := range normalized {
+		if !ip.Equal(r.hosts[h]) {
+			changed = true
+		}
+	}
+	r.hosts = normalized
+	return changed
+}
+
+// Hosts returns every name resolved, with their IP
+func (r *Records) Hosts() map[string]net.IP {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	hosts := map[string]net.IP{r.Domain: r.IP}
+	for h, ip := range r.hosts {
+		hosts[h] = ip
+	}
+	return hosts
+}
+
+// Domains returns the domains the host forwards to the DNS server of minikube: the domain of the cluster, and the
+// hosts outside of it
+func (r *Records) Domains() []string {
+	domains := map[string]bool{r.Domain: true}
+	for h := range r.Hosts() {
+		h = strings.TrimPrefix(h, "*.")
+		if h != r.Domain && !strings.HasSuffix(h, "."+r.Domain) {
+			domains[h] = true
+		}
+	}
+	var ds []string
+	for d := range domains {
+		ds = append(ds, d)
+	}
+	sort.Strings(ds)
+	return ds
+}
+
+// Lookup returns the IP of a name
+func (r *Records) Lookup(name string) (net.IP, bool) {
+	name = normalize(name)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if ip, ok := r.hosts[name]; ok {
+		return ip, true
+	}
+	if i := strings.Index(name, "."); i > 0 {
+		if ip, ok := r.hosts["*"+name[i:]]; ok {
+			return ip, true
+		}
+	}
+	if name == r.Domain || strings.HasSuffix(name, "."+r.Domain) {
+		return r.IP, true
+	}
+	return nil, false
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecords(t *testing.T) {
+	r := NewRecords("minikube", net.ParseIP("192.168.49.2"))
+	if !r.SetHosts(map[string]net.IP{"Web.Example.com.": net.ParseIP("192.168.49.200"), "*.apps.example.com": net.ParseIP("192.168.49.2")}) {
+		t.Errorf("SetHosts() of new hosts reported no change")
+	}
+	if r.SetHosts(map[string]net.IP{"web.example.com": net.ParseIP("192.168.49.200"), "*.apps.example.com": net.ParseIP("192.168.49.2")}) {
+		t.Errorf("SetHosts() of the same hosts reported a change")
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"minikube.test.", "192.168.49.2"},
+		{"dashboard.minikube.test", "192.168.49.2"},
+		{"a.b.MINIKUBE.test.", "192.168.49.2"},
+		{"web.example.com.", "192.168.49.200"},
+		{"shop.apps.example.com", "192.168.49.2"},
+		{"a.shop.apps.example.com", ""},
+		{"example.com", ""},
+		{"other.test", ""},
+		{"xminikube.test", ""},
+	}
+	for _, tc := range tests {
+		ip, ok := r.Lookup(tc.name)
+		if ok != (tc.want != "") || (ok && ip.String() != tc.want) {
+			t.Errorf("Lookup(%q) = %v, %t, want %q", tc.name, ip, ok, tc.want)
+		}
+	}
+
+	r.SetHosts(map[string]net.IP{"web.example.com": net.ParseIP("192.168.49.200"), "*.apps.example.com": net.ParseIP("192.168.49.2"), "web.minikube.test": net.ParseIP("192.168.49.2")})
+	if got, want := r.Domains(), []string{"apps.example.com", "minikube.test", "web.example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Domains() = %v, want %v", got, want)
+	}
+}
+
+func TestIngressHosts(t *testing.T) {
+	ing := func(name string, lb string, hosts ...string) *networking.Ingress {
+		i := &networking.Ingress{ObjectMeta: meta.ObjectMeta{Name: name}}
+		for _, h := range hosts {
+			i.Spec.Rules = append(i.Spec.Rules, networking.IngressRule{Host: h})
+		}
+		if lb != "" {
+			i.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: lb}}
+		}
+		return i
+	}
+	ings := []*networking.Ingress{
+		ing("web", "", "web.example.com", ""),
+		ing("shop", "192.168.49.200", "Shop.example.com", "*.shop.example.com"),
+	}
+	ip := net.ParseIP("192.168.49.2")
+
+	got := IngressHosts(ings, ip, true)
+	want := map[string]net.IP{
+		"web.example.com":    ip,
+		"shop.example.com":   net.ParseIP("192.168.49.200"),
+		"*.shop.example.com": net.ParseIP("192.168.49.200"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IngressHosts() = %v, want %v", got, want)
+	}
+
+	for h, hip := range IngressHosts(ings, net.ParseIP("127.0.0.1"), false) {
+		if hip.String() != "127.0.0.1" {
+			t.Errorf("IngressHosts() without their status resolves %s to %s, want 127.0.0.1", h, hip)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+	"k8s.io/klog/v2"
+)
+
+// ttl is short, so that the host notices changes of the Ingresses quickly
+const ttl = 5
+
+// Serve answers the DNS queries for the records received on conn, until it is closed
+func Serve(conn net.PacketConn, r *Records) error {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		resp, err := answer(buf[:n], r)
+		if err != nil {
+			klog.Warningf("invalid DNS query from %s: %v", addr, err)
+			continue
+		}
+		if _, err := conn.WriteTo(resp, addr); err != nil {
+			klog.Warningf("answering %s: %v", addr, err)
+		}
+	}
+}
+
+// answer returns the response to a query. Names outside of the records are refused, so that the host asks its
+// other DNS servers.
+func answer(query []byte, r *Records) ([]byte, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "header")
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, errors.Wrap(err, "question")
+	}
+
+	ip, ok := r.Lookup(q.Name.String())
+	rh := dnsmessage.Header{ID: h.ID, Response: true, Authoritative: ok, RecursionDesired: h.RecursionDesired, RCode: dnsmessage.RCodeSuccess}
+	if !ok {
+		rh.RCode = dnsmessage.RCodeRefused
+	}
+	klog.V(2).Infof("%s %s: %v", q.Type, q.Name, ip)
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), rh)
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if ok && ip.To4() != nil && (q.Type == dnsmessage.TypeA || q.Type == dnsmessage.TypeALL) {
+		if err := b.StartAnswers(); err != nil {
+			return nil, err
+		}
+		var a dnsmessage.AResource
+		copy(a.A[:], ip.To4())
+		if err := b.AResource(dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: ttl}, a); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func query(t *testing.T, conn net.Conn, name string, typ dnsmessage.Type) dnsmessage.Message {
+	t.Helper()
+	q := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: dnsmessage.MustNewName(name), Type: typ, Class: dnsmessage.ClassINET}},
+	}
+	b, err := q.Pack()
+	if err != nil {
+		t.Fatalf("pack: %v", err)
+	}
+	if _, err := conn.Write(b); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, 512)
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("deadline: %v", err)
+	}
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var m dnsmessage.Message
+	if err := m.Unpack(buf[:n]); err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	if m.Header.ID != 42 || !m.Header.Response {
+		t.Errorf("header = %+v, want a response to 42", m.Header)
+	}
+	return m
+}
+
+func TestServe(t *testing.T) {
+	r := NewRecords("minikube", net.ParseIP("192.168.49.2"))
+	r.SetHosts(map[string]net.IP{"web.example.com": net.ParseIP("192.168.49.200")})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer pc.Close()
+	go func() {
+		_ = Serve(pc, r)
+	}()
+	conn, err := net.Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		name  string
+		typ   dnsmessage.Type
+		rcode dnsmessage.RCode
+		want  string
+	}{
+		{"dashboard.minikube.test.", dnsmessage.TypeA, dnsmessage.RCodeSuccess, "192.168.49.2"},
+		{"web.example.com.", dnsmessage.TypeA, dnsmessage.RCodeSuccess, "192.168.49.200"},
+		{"web.example.com.", dnsmessage.TypeAAAA, dnsmessage.RCodeSuccess, ""},
+		{"google.com.", dnsmessage.TypeA, dnsmessage.RCodeRefused, ""},
+	}
+	for _, tc := range tests {
+		m := query(t, conn, tc.name, tc.typ)
+		if m.Header.RCode != tc.rcode {
+			t.Errorf("%s %s: rcode = %s, want %s", tc.typ, tc.name, m.Header.RCode, tc.rcode)
+		}
+		got := ""
+		if len(m.Answers) > 0 {
+			a, ok := m.Answers[0].Body.(*dnsmessage.AResource)
+			if !ok {
+				t.Fatalf("%s %s: answer %v is not an A record", tc.typ, tc.name, m.Answers[0])
+			}
+			got = net.IP(a.A[:]).String()
+		}
+		if got != tc.want {
+			t.Errorf("%s %s = %q, want %q", tc.typ, tc.name, got, tc.want)
+		}
+	}
+}
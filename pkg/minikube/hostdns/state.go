@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostdns
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/background"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// stopTimeout is how long the DNS server has to exit
+const stopTimeout = 30 * time.Second
+
+// State is the state of the running DNS server of a profile
+type State struct {
+	Pid     int
+	Started time.Time
+	Mode    string
+	// Port is the port of the DNS server on 127.0.0.1, which the hosts mode does not use
+	Port int
+	IP   string
+}
+
+// StatePath returns the path of the state of the DNS server of a profile
+func StatePath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "dns.json")
+}
+
+// LogPath returns the path of the log of the DNS server of a profile
+func LogPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "dns.log")
+}
+
+// SaveState records the state of the running DNS server of a profile
+func SaveState(profile string, s State) error {
+	return background.SaveState(StatePath(profile), s)
+}
+
+// RemoveState removes the state of the DNS server of a profile, once it exits
+func RemoveState(profile string) error {
+	return background.RemoveState(StatePath(profile))
+}
+
+// LoadState returns the state of the running DNS server of a profile, or nil if none is running
+func LoadState(profile string) (*State, error) {
+	var s State
+	running, err := background.LoadState(StatePath(profile), &s)
+	if err != nil || !running {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Stop stops the running DNS server of a profile, waiting up to timeout for it to exit
+func Stop(profile string, timeout time.Duration) error {
+	return errors.Wrap(background.Stop(StatePath(profile), timeout), "DNS server")
+}
@@ -79,6 +79,17 @@ func DeleteContext(machineName string, configPath ...string) error {
 	delete(kcfg.AuthInfos, machineName)
 	delete(kcfg.Contexts, machineName)
 
+	// contexts of the cluster named after their users were added for its authentication mode
+	for name, c := range kcfg.Contexts {
+		if c.Cluster == machineName && c.AuthInfo == name {
+			delete(kcfg.AuthInfos, name)
+			delete(kcfg.Contexts, name)
+			if kcfg.CurrentContext == name {
+				kcfg.CurrentContext = ""
+			}
+		}
+	}
+
 	if kcfg.CurrentContext == machineName {
 		kcfg.CurrentContext = ""
 	}
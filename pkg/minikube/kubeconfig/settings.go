@@ -51,6 +51,12 @@ type Settings struct {
 	// Should the certificate files be embedded instead of referenced by path
 	EmbedCerts bool
 
+	// AuthUser is the name of an additional user, and of a context for it, which is authenticated by AuthInfo rather than the client certificate
+	AuthUser string
+
+	// AuthInfo authenticates AuthUser
+	AuthInfo *api.AuthInfo
+
 	// kubeConfigFile is the path where the kube config is stored
 	// Only access this with atomic ops
 	kubeConfigFile atomic.Value
@@ -107,6 +113,14 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 	context.AuthInfo = userName
 	apiCfg.Contexts[contextName] = context
 
+	if cfg.AuthUser != "" && cfg.AuthInfo != nil {
+		apiCfg.AuthInfos[cfg.AuthUser] = cfg.AuthInfo
+		authContext := api.NewContext()
+		authContext.Cluster = cfg.ClusterName
+		authContext.AuthInfo = cfg.AuthUser
+		apiCfg.Contexts[cfg.AuthUser] = authContext
+	}
+
 	// Only set current context to minikube if the user has not used the keepContext flag
 	if !cfg.KeepContext {
 		apiCfg.CurrentContext = cfg.ClusterName
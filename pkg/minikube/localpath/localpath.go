@@ -66,6 +66,21 @@ func EventLog(name string) string {
 	return filepath.Join(Profile(name), "events.json")
 }
 
+// DaemonSocket returns the path of the socket served by 'minikube daemon'
+func DaemonSocket() string {
+	return filepath.Join(MiniPath(), "daemon.sock")
+}
+
+// DexCert returns the path of the serving certificate of the dex addon of a cluster
+func DexCert(name string) string {
+	return filepath.Join(Profile(name), "dex.crt")
+}
+
+// DexKey returns the path of the serving key of the dex addon of a cluster
+func DexKey(name string) string {
+	return filepath.Join(Profile(name), "dex.key")
+}
+
 // ClientCert returns client certificate path, used by kubeconfig
 func ClientCert(name string) string {
 	new := filepath.Join(Profile(name), "client.crt")
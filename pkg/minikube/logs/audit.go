@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// AuditFilter selects the events of the audit log to show. Empty fields match every event.
+type AuditFilter struct {
+	User     string
+	Verb     string
+	Resource string
+}
+
+// auditEvent is the part of an audit.k8s.io/v1 Event shown by OutputAudit
+type auditEvent struct {
+	Verb       string `json:"verb"`
+	RequestURI string `json:"requestURI"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	SourceIPs []string `json:"sourceIPs"`
+	ObjectRef *struct {
+		Resource    string `json:"resource"`
+		Namespace   string `json:"namespace"`
+		Name        string `json:"name"`
+		APIGroup    string `json:"apiGroup"`
+		Subresource string `json:"subresource"`
+	} `json:"objectRef"`
+	ResponseStatus *struct {
+		Code int `json:"code"`
+	} `json:"responseStatus"`
+	StageTimestamp time.Time `json:"stageTimestamp"`
+}
+
+// empty returns whether the filter matches every event
+func (f AuditFilter) empty() bool {
+	return f == AuditFilter{}
+}
+
+// matches returns whether an event is selected by the filter.
+// Resources match by name, as "pods", optionally with their subresource or API group, as "pods/log" or "deployments.apps".
+func (f AuditFilter) matches(e auditEvent) bool {
+	if f.User != "" && f.User != e.User.Username {
+		return false
+	}
+	if f.Verb != "" && f.Verb != e.Verb {
+		return false
+	}
+	if f.Resource == "" {
+		return true
+	}
+	if e.ObjectRef == nil {
+		return false
+	}
+	r := e.ObjectRef
+	for _, name := range []string{r.Resource, r.Resource + "/" + r.Subresource, r.Resource + "." + r.APIGroup} {
+		if f.Resource == name {
+			return true
+		}
+	}
+	return false
+}
+
+// formatAuditEvent formats an event as a single line: its time, user, verb, object and response code
+func formatAuditEvent(e auditEvent) string {
+	object := e.RequestURI
+	if r := e.ObjectRef; r != nil {
+		object = r.Resource
+		if r.APIGroup != "" {
+			object += "." + r.APIGroup
+		}
+		if r.Subresource != "" {
+			object += "/" + r.Subresource
+		}
+		name := r.Name
+		if r.Namespace != "" {
+			name = r.Namespace + "/" + name
+		}
+		if name != "" {
+			object += " " + strings.TrimSuffix(name, "/")
+		}
+	}
+	code := "-"
+	if e.ResponseStatus != nil {
+		code = strconv.Itoa(e.ResponseStatus.Code)
+	}
+	return fmt.Sprintf("%s %s %s %s %s", e.StageTimestamp.UTC().Format(time.RFC3339), e.User.Username, e.Verb, object, code)
+}
+
+// filterAuditLine formats a line of the audit log, returning false if it is not selected by the filter.
+// Lines which are not audit events are returned as they are.
+func filterAuditLine(line string, f AuditFilter) (string, bool) {
+	if strings.TrimSpace(line) == "" {
+		return "", false
+	}
+	var e auditEvent
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		if !f.empty() {
+			return "", false
+		}
+		return line, true
+	}
+	if !f.matches(e) {
+		return "", false
+	}
+	return formatAuditEvent(e), true
+}
+
+// OutputAudit displays the last events of the audit log of the API server which are selected by the filter
+func OutputAudit(cr logRunner, lines int, f AuditFilter) error {
+	// Filtering needs the whole log, which is rotated before it grows large
+	from := strconv.Itoa(lines)
+	if !f.empty() {
+		from = "+1"
+	}
+	rr, err := cr.RunCmd(exec.Command("sudo", "tail", "-n", from, bsutil.AuditLogPath))
+	if err != nil {
+		return errors.Wrap(err, "reading audit log")
+	}
+
+	var selected []string
+	scanner := bufio.NewScanner(&rr.Stdout)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if l, ok := filterAuditLine(scanner.Text(), f); ok {
+			selected = append(selected, l)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "scanning audit log")
+	}
+	if len(selected) > lines {
+		selected = selected[len(selected)-lines:]
+	}
+	for _, l := range selected {
+		out.T(style.Empty, l)
+	}
+	return nil
+}
+
+// FollowAudit follows the audit log of the API server, displaying the events selected by the filter
+func FollowAudit(cr logRunner, lines int, f AuditFilter) error {
+	cmd := exec.Command("sudo", "tail", "-n", strconv.Itoa(lines), "-F", bsutil.AuditLogPath)
+	cmd.Stdout = &lineWriter{fn: func(line string) {
+		if l, ok := filterAuditLine(line, f); ok {
+			out.T(style.Empty, l)
+		}
+	}}
+	if _, err := cr.RunCmd(cmd); err != nil {
+		return errors.Wrap(err, "audit log follow")
+	}
+	return nil
+}
+
+// lineWriter calls fn with every complete line written to it
+type lineWriter struct {
+	buf bytes.Buffer
+	fn  func(string)
+}
+
+// Write buffers p, calling fn for every line it completes
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.buf.Next(i + 1))
+		w.fn(strings.TrimSuffix(line, "\n"))
+	}
+}
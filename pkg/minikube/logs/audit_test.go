@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	auditGetPod       = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/api/v1/namespaces/default/pods/nginx","verb":"get","user":{"username":"admin","groups":["system:masters"]},"sourceIPs":["192.168.39.1"],"objectRef":{"resource":"pods","namespace":"default","name":"nginx","apiVersion":"v1"},"responseStatus":{"metadata":{},"code":200},"stageTimestamp":"2020-10-16T15:02:18.123456Z"}`
+	auditPodLogs      = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/api/v1/namespaces/default/pods/nginx/log","verb":"get","user":{"username":"admin"},"objectRef":{"resource":"pods","namespace":"default","name":"nginx","subresource":"log"},"responseStatus":{"code":200},"stageTimestamp":"2020-10-16T15:02:19Z"}`
+	auditDeleteDeploy = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/apis/apps/v1/namespaces/default/deployments/web","verb":"delete","user":{"username":"system:serviceaccount:default:ci"},"objectRef":{"resource":"deployments","namespace":"default","name":"web","apiGroup":"apps"},"responseStatus":{"code":403},"stageTimestamp":"2020-10-16T15:02:20Z"}`
+	auditNonResource  = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/metrics","verb":"get","user":{"username":"admin"},"responseStatus":{"code":200},"stageTimestamp":"2020-10-16T15:02:21Z"}`
+)
+
+func TestFilterAuditLine(t *testing.T) {
+	var tests = []struct {
+		description string
+		line        string
+		filter      AuditFilter
+		expected    string
+		selected    bool
+	}{
+		{"unfiltered", auditGetPod, AuditFilter{}, "2020-10-16T15:02:18Z admin get pods default/nginx 200", true},
+		{"user", auditGetPod, AuditFilter{User: "admin"}, "2020-10-16T15:02:18Z admin get pods default/nginx 200", true},
+		{"other user", auditDeleteDeploy, AuditFilter{User: "admin"}, "", false},
+		{"verb", auditDeleteDeploy, AuditFilter{Verb: "delete"}, "2020-10-16T15:02:20Z system:serviceaccount:default:ci delete deployments.apps default/web 403", true},
+		{"other verb", auditGetPod, AuditFilter{Verb: "delete"}, "", false},
+		{"resource", auditPodLogs, AuditFilter{Resource: "pods"}, "2020-10-16T15:02:19Z admin get pods/log default/nginx 200", true},
+		{"subresource", auditPodLogs, AuditFilter{Resource: "pods/log"}, "2020-10-16T15:02:19Z admin get pods/log default/nginx 200", true},
+		{"other subresource", auditGetPod, AuditFilter{Resource: "pods/log"}, "", false},
+		{"resource with group", auditDeleteDeploy, AuditFilter{Resource: "deployments.apps"}, "2020-10-16T15:02:20Z system:serviceaccount:default:ci delete deployments.apps default/web 403", true},
+		{"non-resource", auditNonResource, AuditFilter{}, "2020-10-16T15:02:21Z admin get /metrics 200", true},
+		{"non-resource with resource filter", auditNonResource, AuditFilter{Resource: "pods"}, "", false},
+		{"not an event", "tail: file truncated", AuditFilter{}, "tail: file truncated", true},
+		{"not an event filtered", "tail: file truncated", AuditFilter{User: "admin"}, "", false},
+		{"empty", "", AuditFilter{}, "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, selected := filterAuditLine(tc.line, tc.filter)
+			if selected != tc.selected || got != tc.expected {
+				t.Errorf("filterAuditLine() = %q, %v, expected %q, %v", got, selected, tc.expected, tc.selected)
+			}
+		})
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := &lineWriter{fn: func(l string) { lines = append(lines, l) }}
+	for _, chunk := range []string{"first", " line\nsecond line\n", "third", ""} {
+		if _, err := fmt.Fprint(w, chunk); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if diff := cmp.Diff([]string{"first line", "second line"}, lines); diff != "" {
+		t.Errorf("lines diff (-want +got):\n%s", diff)
+	}
+}
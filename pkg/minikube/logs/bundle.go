@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// bundleLines is how many lines of each journal and container log are bundled
+const bundleLines = 5000
+
+// redacted replaces secrets in the bundled cluster config
+const redacted = "REDACTED"
+
+// secretKey matches the names of options and variables that hold secrets
+var secretKey = regexp.MustCompile(`(?i)(secret|password|passwd|token|credential|private.?key)`)
+
+// Bundle is a gzipped tarball of diagnostics. Collectors that fail are listed in its errors.txt instead of failing the bundle.
+type Bundle struct {
+	gw     *gzip.Writer
+	tw     *tar.Writer
+	now    time.Time
+	failed []string
+}
+
+// NewBundle returns a bundle writing to w
+func NewBundle(w io.Writer) *Bundle {
+	gw := gzip.NewWriter(w)
+	return &Bundle{gw: gw, tw: tar.NewWriter(gw), now: time.Now()}
+}
+
+// Add adds a file to the bundle
+func (b *Bundle) Add(name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: b.now, Typeflag: tar.TypeReg}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "header %s", name)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	return nil
+}
+
+// Fail records that the file name could not be collected
+func (b *Bundle) Fail(name string, err error) {
+	klog.Warningf("bundle: %s: %v", name, err)
+	b.failed = append(b.failed, fmt.Sprintf("%s: %v", name, err))
+}
+
+// AddCommand adds the combined output of a shell command run by r. The output of failed commands is bundled too, as it usually explains the failure.
+func (b *Bundle) AddCommand(name string, r command.Runner, cmd string) error {
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", cmd+" 2>&1"))
+	if err != nil {
+		b.Fail(name, err)
+		if rr == nil || rr.Stdout.Len() == 0 {
+			return nil
+		}
+	}
+	return b.Add(name, rr.Stdout.Bytes())
+}
+
+// Close writes errors.txt and flushes the bundle
+func (b *Bundle) Close() error {
+	if len(b.failed) > 0 {
+		if err := b.Add("errors.txt", []byte(strings.Join(b.failed, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return errors.Wrap(err, "tar")
+	}
+	return b.gw.Close()
+}
+
+// AddNode adds the diagnostics of a node to the bundle, under dir: the journals of the kubelet and container runtime,
+// the logs and inspect output of the kube-system containers and, on control plane nodes, the kubeadm config.
+func (b *Bundle) AddNode(dir string, r command.Runner, cr cruntime.Manager, controlPlane bool) error {
+	type bundleCmd struct {
+		name string
+		cmd  string
+	}
+	cmds := []bundleCmd{
+		{"kubelet.log", fmt.Sprintf("sudo journalctl -u kubelet --no-pager -n %d", bundleLines)},
+	}
+	// the generic CRI has no unit of its own, and reports the kubelet instead
+	if unit := cr.SystemLogUnit(); unit != kubeletComponent {
+		cmds = append(cmds, bundleCmd{unit + ".log", fmt.Sprintf("sudo journalctl -u %s --no-pager -n %d", unit, bundleLines)})
+	}
+	cmds = append(cmds,
+		bundleCmd{"dmesg.log", fmt.Sprintf("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg | tail -n %d", bundleLines)},
+		bundleCmd{"container-status.txt", cruntime.ContainerStatusCommand()},
+	)
+	if controlPlane {
+		cmds = append(cmds, bundleCmd{"kubeadm.yaml", "sudo cat " + bsutil.KubeadmYamlPath})
+	}
+	for _, c := range cmds {
+		if err := b.AddCommand(path.Join(dir, c.name), r, c.cmd); err != nil {
+			return err
+		}
+	}
+
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.All, Namespaces: []string{"kube-system"}})
+	if err != nil {
+		b.Fail(path.Join(dir, "containers"), err)
+		return nil
+	}
+	for _, id := range ids {
+		if err := b.AddCommand(path.Join(dir, "containers", id+".log"), r, cr.ContainerLogCmd(id, bundleLines, false)); err != nil {
+			return err
+		}
+		if err := b.AddCommand(path.Join(dir, "containers", id+".json"), r, cr.ContainerInspectCmd(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddEvents adds the events of all namespaces, using the kubectl binary and kubeconfig of the control plane node r runs on
+func (b *Bundle) AddEvents(name string, r command.Runner, cc config.ClusterConfig) error {
+	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
+	kubeconfig := path.Join(vmpath.GuestPersistentDir, "kubeconfig")
+	return b.AddCommand(name, r, fmt.Sprintf("sudo KUBECONFIG=%s %s get events -A -o wide --request-timeout=10s", kubeconfig, kubectl))
+}
+
+// AddConfig adds the cluster config as JSON, with its secrets redacted
+func (b *Bundle) AddConfig(name string, cc config.ClusterConfig) error {
+	data, err := json.MarshalIndent(RedactConfig(cc), "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	return b.Add(name, data)
+}
+
+// RedactConfig returns a copy of the cluster config whose secrets are redacted, to be shared in bug reports
+func RedactConfig(cc config.ClusterConfig) config.ClusterConfig {
+	if cc.KubernetesConfig.Auth.OIDCClientSecret != "" {
+		cc.KubernetesConfig.Auth.OIDCClientSecret = redacted
+	}
+	if cc.KubernetesConfig.Auth.Token != "" {
+		cc.KubernetesConfig.Auth.Token = redacted
+	}
+
+	var opts config.ExtraOptionSlice
+	for _, o := range cc.KubernetesConfig.ExtraOptions {
+		if secretKey.MatchString(o.Key) {
+			o.Value = redacted
+		}
+		opts = append(opts, o)
+	}
+	cc.KubernetesConfig.ExtraOptions = opts
+
+	cc.DockerEnv = redactEnv(cc.DockerEnv)
+	cc.DockerOpt = redactEnv(cc.DockerOpt)
+	return cc
+}
+
+// redactEnv redacts the values of secret KEY=VALUE entries, and the credentials of URLs such as those of proxies
+func redactEnv(env []string) []string {
+	var redactedEnv []string
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 {
+			switch {
+			case secretKey.MatchString(kv[0]):
+				e = kv[0] + "=" + redacted
+			default:
+				if u, err := url.Parse(kv[1]); err == nil && u.User != nil {
+					u.User = url.User(redacted)
+					e = kv[0] + "=" + u.String()
+				}
+			}
+		}
+		redactedEnv = append(redactedEnv, e)
+	}
+	return redactedEnv
+}
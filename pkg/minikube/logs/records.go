@@ -0,0 +1,364 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// Levels of records, from least to most severe
+const (
+	LevelDebug   = "debug"
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+	LevelError   = "error"
+	LevelFatal   = "fatal"
+)
+
+// Record is a line of the log of a component
+type Record struct {
+	Time      time.Time `json:"time"`
+	Node      string    `json:"node"`
+	Component string    `json:"component"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+}
+
+// String formats a record as a single line
+func (r Record) String() string {
+	level := "I"
+	if r.Level != "" {
+		level = strings.ToUpper(r.Level[:1])
+	}
+	return fmt.Sprintf("%s %s %s %s %s", r.Time.UTC().Format("2006-01-02T15:04:05.000000Z"), r.Node, r.Component, level, r.Message)
+}
+
+// RecordOptions selects the records to return
+type RecordOptions struct {
+	// Components are those to return the records of, or all components if empty
+	Components []string
+	// Lines is the number of records to return per component and container, unless Since is set
+	Lines int
+	// Since is how far back to return records
+	Since time.Duration
+	// Grep selects the records whose message matches, if set
+	Grep *regexp.Regexp
+}
+
+const (
+	// kubeletComponent is the component logging to the journal of the kubelet
+	kubeletComponent = "kubelet"
+	// runtimeComponent is the component logging to the journal of the container runtime
+	runtimeComponent = "runtime"
+)
+
+// componentContainers maps the components which run in containers to the names of their containers
+var componentContainers = map[string]string{
+	"apiserver":           "kube-apiserver",
+	"controller-manager":  "kube-controller-manager",
+	"coredns":             "coredns",
+	"etcd":                "etcd",
+	"kube-proxy":          "kube-proxy",
+	"scheduler":           "kube-scheduler",
+	"storage-provisioner": "storage-provisioner",
+}
+
+// Components returns the components whose logs are parsed into records
+func Components() []string {
+	components := []string{kubeletComponent, runtimeComponent}
+	for c := range componentContainers {
+		components = append(components, c)
+	}
+	sort.Strings(components[2:])
+	return components
+}
+
+// recordSource is a log parsed into records
+type recordSource struct {
+	name      string
+	component string
+	cmd       string
+	journal   bool
+}
+
+// recordSources returns the logs of the components of a node
+func recordSources(r cruntime.Manager, components []string, o RecordOptions) ([]recordSource, error) {
+	var sources []recordSource
+	for _, c := range components {
+		switch c {
+		case kubeletComponent:
+			sources = append(sources, recordSource{name: c, component: c, cmd: journalCmd(c, o), journal: true})
+		case runtimeComponent:
+			// runtimes which log to the journal of the kubelet, such as the generic CRI, would repeat it
+			if r.SystemLogUnit() == kubeletComponent && contains(components, kubeletComponent) {
+				continue
+			}
+			sources = append(sources, recordSource{name: r.SystemLogUnit(), component: c, cmd: journalCmd(r.SystemLogUnit(), o), journal: true})
+		default:
+			container, ok := componentContainers[c]
+			if !ok {
+				return nil, fmt.Errorf("unknown component %q, valid components are: %s", c, strings.Join(Components(), ", "))
+			}
+			ids, err := r.ListContainers(cruntime.ListOptions{Name: container})
+			if err != nil {
+				klog.Errorf("Failed to list containers for %q: %v", container, err)
+				continue
+			}
+			for _, id := range ids {
+				sources = append(sources, recordSource{name: fmt.Sprintf("%s [%s]", container, id), component: c, cmd: r.TimestampedContainerLogCmd(id, o.Lines, o.Since)})
+			}
+		}
+	}
+	return sources, nil
+}
+
+// contains returns whether a list of strings contains s
+func contains(sl []string, s string) bool {
+	for _, v := range sl {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Records returns the records of the components of a node, in chronological order.
+// Logs which cannot be read are skipped, and returned in the error.
+func Records(node string, r cruntime.Manager, cr logRunner, o RecordOptions) ([]Record, error) {
+	components := o.Components
+	if len(components) == 0 {
+		components = Components()
+	}
+
+	sources, err := recordSources(r, components, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	var failed []string
+	for _, s := range sources {
+		var b bytes.Buffer
+		c := exec.Command("/bin/bash", "-c", s.cmd)
+		c.Stdout = &b
+		if rr, err := cr.RunCmd(c); err != nil {
+			klog.Errorf("command %s failed with error: %v output: %q", rr.Command(), err, rr.Output())
+			failed = append(failed, s.name)
+			continue
+		}
+		scanner := bufio.NewScanner(&b)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			parse := parseTimestampedLine
+			if s.journal {
+				parse = parseJournalLine
+			}
+			rec, ok := parse(scanner.Text())
+			if !ok {
+				continue
+			}
+			if o.Grep != nil && !o.Grep.MatchString(rec.Message) {
+				continue
+			}
+			rec.Node = node
+			rec.Component = s.component
+			records = append(records, rec)
+		}
+	}
+
+	records = MergeRecords(records)
+	if len(failed) > 0 {
+		return records, fmt.Errorf("unable to fetch logs for: %s", strings.Join(failed, ", "))
+	}
+	return records, nil
+}
+
+// MergeRecords merges sets of records, such as those of several nodes, in chronological order
+func MergeRecords(sets ...[]Record) []Record {
+	var merged []Record
+	for _, s := range sets {
+		merged = append(merged, s...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	return merged
+}
+
+// journalCmd returns the command to retrieve the journal of a systemd unit as JSON
+func journalCmd(unit string, o RecordOptions) string {
+	if o.Since > 0 {
+		return fmt.Sprintf("sudo journalctl -u %s -o json --no-pager --since=-%ds", unit, int(o.Since.Seconds()))
+	}
+	return fmt.Sprintf("sudo journalctl -u %s -o json --no-pager -n %d", unit, o.Lines)
+}
+
+// journalEntry is the part of a journal entry, as exported by journalctl -o json, parsed into a record
+type journalEntry struct {
+	Timestamp string          `json:"__REALTIME_TIMESTAMP"`
+	Priority  string          `json:"PRIORITY"`
+	Message   json.RawMessage `json:"MESSAGE"`
+}
+
+// parseJournalLine parses a journal entry exported by journalctl -o json
+func parseJournalLine(line string) (Record, bool) {
+	var e journalEntry
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return Record{}, false
+	}
+	usec, err := strconv.ParseInt(e.Timestamp, 10, 64)
+	if err != nil {
+		return Record{}, false
+	}
+
+	// Messages which are not valid UTF-8 are exported as arrays of bytes
+	var msg string
+	if err := json.Unmarshal(e.Message, &msg); err != nil {
+		var b []byte
+		var ints []int
+		if err := json.Unmarshal(e.Message, &ints); err != nil {
+			return Record{}, false
+		}
+		for _, i := range ints {
+			b = append(b, byte(i))
+		}
+		msg = string(b)
+	}
+
+	level, msg := parseMessage(msg)
+	if level == "" {
+		level = priorityLevel(e.Priority)
+	}
+	return Record{Time: time.Unix(0, usec*int64(time.Microsecond)).UTC(), Level: level, Message: msg}, true
+}
+
+// priorityLevel returns the level of a syslog priority
+func priorityLevel(priority string) string {
+	switch priority {
+	case "0", "1", "2":
+		return LevelFatal
+	case "3":
+		return LevelError
+	case "4":
+		return LevelWarning
+	case "7":
+		return LevelDebug
+	default:
+		return LevelInfo
+	}
+}
+
+// parseTimestampedLine parses a line of a container log prefixed by its RFC3339 time, as output by the container runtime
+func parseTimestampedLine(line string) (Record, bool) {
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) != 2 {
+		return Record{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return Record{}, false
+	}
+	level, msg := parseMessage(fields[1])
+	if level == "" {
+		level = LevelInfo
+	}
+	return Record{Time: t.UTC(), Level: level, Message: msg}, true
+}
+
+var (
+	// klogRe matches the header of klog lines, as "I1016 15:02:18.937590    7122 server.go:121] message"
+	klogRe = regexp.MustCompile(`^([IWEF])\d{4} \d{2}:\d{2}:\d{2}\.\d+\s+\d+ [^\]]+\] ?(.*)$`)
+	// capnslogRe matches the header of the lines of older etcd releases, as "2020-10-16 15:02:18.937590 I | etcdserver: message"
+	capnslogRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d+ ([DINWEC]) \| (.*)$`)
+	// bracketRe matches the level of coredns lines, as "[INFO] message"
+	bracketRe = regexp.MustCompile(`^\[(DEBUG|INFO|WARNING|ERROR|FATAL)\] (.*)$`)
+)
+
+// levelLetters maps the letters of klog and capnslog levels to levels
+var levelLetters = map[string]string{
+	"D": LevelDebug,
+	"I": LevelInfo,
+	"N": LevelInfo,
+	"W": LevelWarning,
+	"E": LevelError,
+	"F": LevelFatal,
+	"C": LevelFatal,
+}
+
+// parseMessage returns the level and message of a log line in the klog, capnslog, zap JSON or coredns format.
+// The level is empty if the format is unknown, in which case the line is returned as the message.
+func parseMessage(line string) (string, string) {
+	if m := klogRe.FindStringSubmatch(line); m != nil {
+		return levelLetters[m[1]], m[2]
+	}
+	if m := capnslogRe.FindStringSubmatch(line); m != nil {
+		return levelLetters[m[1]], m[2]
+	}
+	if m := bracketRe.FindStringSubmatch(line); m != nil {
+		return strings.ToLower(m[1]), m[2]
+	}
+	if strings.HasPrefix(line, "{") {
+		if level, msg, ok := parseZapLine(line); ok {
+			return level, msg
+		}
+	}
+	return "", line
+}
+
+// parseZapLine parses a JSON line of the zap logger, as used by etcd, appending its fields to the message
+func parseZapLine(line string) (string, string, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return "", "", false
+	}
+	level, ok := fields["level"].(string)
+	if !ok {
+		return "", "", false
+	}
+	msg, _ := fields["msg"].(string)
+
+	var keys []string
+	for k := range fields {
+		switch k {
+		case "level", "ts", "caller", "msg":
+		default:
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		msg += fmt.Sprintf(" %s=%v", k, fields[k])
+	}
+
+	switch level {
+	case "warn":
+		level = LevelWarning
+	case "dpanic", "panic":
+		level = LevelFatal
+	}
+	return level, msg, true
+}
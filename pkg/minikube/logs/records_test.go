@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func TestRecordSources(t *testing.T) {
+	tests := []struct {
+		runtime    string
+		components []string
+		want       []string
+	}{
+		{"docker", []string{"kubelet", "runtime"}, []string{"kubelet", "docker"}},
+		{"cri", []string{"kubelet", "runtime"}, []string{"kubelet"}},
+		{"cri", []string{"runtime"}, []string{"kubelet"}},
+	}
+	for _, tc := range tests {
+		r, err := cruntime.New(cruntime.Config{Type: tc.runtime, Socket: "/run/example.sock"})
+		if err != nil {
+			t.Fatalf("New(%s): %v", tc.runtime, err)
+		}
+		sources, err := recordSources(r, tc.components, RecordOptions{})
+		if err != nil {
+			t.Fatalf("recordSources: %v", err)
+		}
+		var got []string
+		for _, s := range sources {
+			got = append(got, s.name)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("recordSources(%s, %v) mismatch (-want +got):\n%s", tc.runtime, tc.components, diff)
+		}
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	var tests = []struct {
+		description string
+		line        string
+		level       string
+		message     string
+	}{
+		{"klog", "I1016 15:02:18.937590    7122 server.go:121] Starting kubelet", LevelInfo, "Starting kubelet"},
+		{"klog error", `E1016 15:02:19.000001       1 reflector.go:127] Failed to watch *v1.Pod: unknown`, LevelError, "Failed to watch *v1.Pod: unknown"},
+		{"capnslog", "2020-10-16 15:02:18.937590 W | etcdserver: read-only range request took too long", LevelWarning, "etcdserver: read-only range request took too long"},
+		{"zap", `{"level":"warn","ts":"2020-10-16T15:02:18.937Z","caller":"etcdserver/util.go:163","msg":"apply request took too long","took":"101ms"}`, LevelWarning, "apply request took too long took=101ms"},
+		{"coredns", "[INFO] plugin/reload: Running configuration MD5 = 4e235fcc", LevelInfo, "plugin/reload: Running configuration MD5 = 4e235fcc"},
+		{"unknown", "Flag --port has been deprecated", "", "Flag --port has been deprecated"},
+		{"json without level", `{"msg":"hello"}`, "", `{"msg":"hello"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			level, message := parseMessage(tc.line)
+			if level != tc.level || message != tc.message {
+				t.Errorf("parseMessage(%q) = %q, %q, expected %q, %q", tc.line, level, message, tc.level, tc.message)
+			}
+		})
+	}
+}
+
+func TestParseJournalLine(t *testing.T) {
+	var tests = []struct {
+		description string
+		line        string
+		expected    Record
+		ok          bool
+	}{
+		{
+			description: "klog",
+			line:        `{"__REALTIME_TIMESTAMP":"1602860538937590","PRIORITY":"6","_SYSTEMD_UNIT":"kubelet.service","MESSAGE":"W1016 15:02:18.937590    7122 server.go:121] Using a deprecated flag"}`,
+			expected:    Record{Time: time.Date(2020, 10, 16, 15, 2, 18, 937590000, time.UTC), Level: LevelWarning, Message: "Using a deprecated flag"},
+			ok:          true,
+		},
+		{
+			description: "priority",
+			line:        `{"__REALTIME_TIMESTAMP":"1602860538000000","PRIORITY":"3","MESSAGE":"kubelet.service: Main process exited, code=exited, status=255/EXCEPTION"}`,
+			expected:    Record{Time: time.Date(2020, 10, 16, 15, 2, 18, 0, time.UTC), Level: LevelError, Message: "kubelet.service: Main process exited, code=exited, status=255/EXCEPTION"},
+			ok:          true,
+		},
+		{
+			description: "binary message",
+			line:        `{"__REALTIME_TIMESTAMP":"1602860538000000","PRIORITY":"6","MESSAGE":[104,105]}`,
+			expected:    Record{Time: time.Date(2020, 10, 16, 15, 2, 18, 0, time.UTC), Level: LevelInfo, Message: "hi"},
+			ok:          true,
+		},
+		{
+			description: "not json",
+			line:        "-- Logs begin at Fri 2020-10-16 15:00:00 UTC. --",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, ok := parseJournalLine(tc.line)
+			if ok != tc.ok {
+				t.Fatalf("parseJournalLine(%q) ok = %v, expected %v", tc.line, ok, tc.ok)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("parseJournalLine diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseTimestampedLine(t *testing.T) {
+	got, ok := parseTimestampedLine("2020-10-16T15:02:18.937590123Z E1016 15:02:18.937590       1 status.go:71] apiserver received an error that is not an metav1.Status")
+	if !ok {
+		t.Fatalf("parseTimestampedLine failed")
+	}
+	expected := Record{Time: time.Date(2020, 10, 16, 15, 2, 18, 937590123, time.UTC), Level: LevelError, Message: "apiserver received an error that is not an metav1.Status"}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("parseTimestampedLine diff (-want +got):\n%s", diff)
+	}
+
+	if _, ok := parseTimestampedLine("Flag --port has been deprecated"); ok {
+		t.Errorf("parseTimestampedLine parsed a line without a timestamp")
+	}
+}
+
+func TestMergeRecords(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2020, 10, 16, 15, 2, sec, 0, time.UTC) }
+	kubelet := []Record{{Time: at(1), Component: "kubelet"}, {Time: at(4), Component: "kubelet"}}
+	apiserver := []Record{{Time: at(2), Component: "apiserver"}, {Time: at(3), Component: "apiserver"}, {Time: at(4), Component: "apiserver"}}
+
+	got := MergeRecords(kubelet, apiserver)
+	expected := []Record{kubelet[0], apiserver[0], apiserver[1], kubelet[1], apiserver[2]}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("MergeRecords diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestRecordString(t *testing.T) {
+	at := time.Date(2020, 10, 16, 15, 2, 18, 937590000, time.UTC)
+	tests := []struct {
+		record   Record
+		expected string
+	}{
+		{Record{Time: at, Node: "minikube", Component: "kubelet", Level: LevelWarning, Message: "hi"}, "2020-10-16T15:02:18.937590Z minikube kubelet W hi"},
+		{Record{Time: at, Node: "minikube", Component: "apiserver", Message: "hi"}, "2020-10-16T15:02:18.937590Z minikube apiserver I hi"},
+	}
+	for _, tc := range tests {
+		if got := tc.record.String(); got != tc.expected {
+			t.Errorf("String() = %q, want %q", got, tc.expected)
+		}
+	}
+}
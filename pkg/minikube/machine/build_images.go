@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// buildRoot is where build contexts are extracted to within the guest VM
+var buildRoot = path.Join(vmpath.GuestPersistentDir, "build")
+
+// BuildImage builds an image from a directory on the host in every running node of the cluster
+func BuildImage(api libmachine.API, cc *config.ClusterConfig, src string, o cruntime.BuildImageOptions) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("build context %q is not a directory", src)
+	}
+
+	tmp, err := ioutil.TempFile("", "build.*.tar")
+	if err != nil {
+		return errors.Wrap(err, "temp file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tarDirectory(src, tmp); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "archiving %s", src)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return ForEachRunningNode(api, cc, func(n config.Node, runner command.Runner, cr cruntime.Manager) error {
+		return transferAndBuildImage(runner, cr, tmp.Name(), o)
+	})
+}
+
+// transferAndBuildImage transfers a build context archive and builds it in the container runtime
+func transferAndBuildImage(runner command.Runner, cr cruntime.Manager, archive string, o cruntime.BuildImageOptions) error {
+	filename := filepath.Base(archive)
+	f, err := assets.NewFileAsset(archive, buildRoot, filename, "0644")
+	if err != nil {
+		return errors.Wrapf(err, "creating copyable file asset: %s", filename)
+	}
+	if err := runner.Copy(f); err != nil {
+		return errors.Wrap(err, "transferring build context")
+	}
+	defer func() {
+		if err := runner.Remove(f); err != nil {
+			klog.Warningf("error removing build context archive: %v", err)
+		}
+	}()
+
+	dir := path.Join(buildRoot, fmt.Sprintf("build.%d", time.Now().UnixNano()))
+	if rr, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", dir)); err != nil {
+		return errors.Wrapf(err, "mkdir: %s", rr.Output())
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-rf", dir)); err != nil {
+			klog.Warningf("error removing build context: %v", err)
+		}
+	}()
+	if rr, err := runner.RunCmd(exec.Command("sudo", "tar", "-C", dir, "-xf", path.Join(buildRoot, filename))); err != nil {
+		return errors.Wrapf(err, "extracting build context: %s", rr.Output())
+	}
+
+	o.Dir = dir
+	if err := cr.BuildImage(o); err != nil {
+		return errors.Wrapf(err, "%s build", cr.Name())
+	}
+	klog.Infof("Built %s from %s", o.Tag, archive)
+	return nil
+}
+
+// tarDirectory writes the contents of dir as a tar archive to w
+func tarDirectory(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -21,11 +21,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -64,11 +66,16 @@ func CacheImagesForBootstrapper(imageRepository string, version string, clusterB
 
 // LoadImages loads previously cached images into the container runtime
 func LoadImages(cc *config.ClusterConfig, runner command.Runner, images []string, cacheDir string) error {
-	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	cr, err := cruntime.ForCluster(cc.KubernetesConfig, runner)
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
 
+	if !cruntime.CanLoadImages(cr) {
+		klog.Infof("%s cannot load images, skipping loading", cr.Name())
+		return nil
+	}
+
 	// Skip loading images if images already exist
 	if cr.ImagesPreloaded(images) {
 		klog.Infof("Images are preloaded, skipping loading")
@@ -232,13 +239,18 @@ func CacheAndLoadImages(images []string) error {
 
 // transferAndLoadImage transfers and loads a single image from the cache
 func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, cacheDir string) error {
-	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
-	if err != nil {
-		return errors.Wrap(err, "runtime")
-	}
 	src := filepath.Join(cacheDir, imgName)
 	src = localpath.SanitizeCacheDir(src)
 	klog.Infof("Loading image from cache: %s", src)
+	return transferAndLoadArchive(cr, k8s, src)
+}
+
+// transferAndLoadArchive transfers and loads a single image archive from the host
+func transferAndLoadArchive(cr command.Runner, k8s config.KubernetesConfig, src string) error {
+	r, err := cruntime.ForCluster(k8s, cr)
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
 	filename := filepath.Base(src)
 	if _, err := os.Stat(src); err != nil {
 		return err
@@ -260,6 +272,130 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 		return errors.Wrapf(err, "%s load %s", r.Name(), dst)
 	}
 
-	klog.Infof("Transferred and loaded %s from cache", src)
+	klog.Infof("Transferred and loaded %s", src)
+	return nil
+}
+
+// ForEachRunningNode calls fn with a container runtime for each running node of the cluster
+func ForEachRunningNode(api libmachine.API, cc *config.ClusterConfig, fn func(n config.Node, runner command.Runner, cr cruntime.Manager) error) error {
+	failed := []string{}
+	for _, n := range cc.Nodes {
+		m := driver.MachineName(*cc, n)
+
+		status, err := Status(api, m)
+		if err != nil {
+			klog.Warningf("error getting status for %s: %v", m, err)
+			failed = append(failed, m)
+			continue
+		}
+		if status != state.Running.String() {
+			klog.Infof("skipping %s: host is %s", m, status)
+			continue
+		}
+
+		h, err := api.Load(m)
+		if err != nil {
+			klog.Warningf("Failed to load machine %q: %v", m, err)
+			failed = append(failed, m)
+			continue
+		}
+		runner, err := CommandRunner(h)
+		if err != nil {
+			return err
+		}
+		cr, err := cruntime.ForCluster(cc.KubernetesConfig, runner)
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		if err := fn(n, runner, cr); err != nil {
+			klog.Warningf("Failed on node %s: %v", m, err)
+			failed = append(failed, m)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed on nodes: %s", strings.Join(failed, " "))
+	}
 	return nil
 }
+
+// DoLoadImages loads images and image archives into every running node of the cluster
+func DoLoadImages(api libmachine.API, cc *config.ClusterConfig, images []string) error {
+	if cr, err := cruntime.ForCluster(cc.KubernetesConfig, nil); err == nil && !cruntime.CanLoadImages(cr) {
+		return fmt.Errorf("the %s container runtime cannot load images, pull them in the nodes instead", cr.Name())
+	}
+
+	var refs, archives []string
+	for _, img := range images {
+		if fi, err := os.Stat(img); err == nil && !fi.IsDir() {
+			archives = append(archives, img)
+			continue
+		}
+		refs = append(refs, img)
+	}
+
+	if err := image.SaveToDir(refs, constants.ImageCacheDir); err != nil {
+		return errors.Wrap(err, "save to dir")
+	}
+
+	return ForEachRunningNode(api, cc, func(n config.Node, runner command.Runner, cr cruntime.Manager) error {
+		if err := LoadImages(cc, runner, refs, constants.ImageCacheDir); err != nil {
+			return err
+		}
+		for _, a := range archives {
+			if err := transferAndLoadArchive(runner, cc.KubernetesConfig, a); err != nil {
+				return errors.Wrapf(err, "loading %s", a)
+			}
+		}
+		return nil
+	})
+}
+
+// ListImages returns the images present on any running node of the cluster
+func ListImages(api libmachine.API, cc *config.ClusterConfig) ([]string, error) {
+	seen := map[string]bool{}
+	err := ForEachRunningNode(api, cc, func(n config.Node, runner command.Runner, cr cruntime.Manager) error {
+		list, err := cr.ListImages()
+		if err != nil {
+			return err
+		}
+		for _, img := range list {
+			seen[img] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images := []string{}
+	for img := range seen {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// RemoveImages removes images from every running node of the cluster
+func RemoveImages(api libmachine.API, cc *config.ClusterConfig, images []string) error {
+	return ForEachRunningNode(api, cc, func(n config.Node, runner command.Runner, cr cruntime.Manager) error {
+		for _, img := range images {
+			if err := cr.RemoveImage(img); err != nil {
+				return errors.Wrapf(err, "removing %s", img)
+			}
+		}
+		return nil
+	})
+}
+
+// PullImages pulls images into every running node of the cluster
+func PullImages(api libmachine.API, cc *config.ClusterConfig, images []string) error {
+	return ForEachRunningNode(api, cc, func(n config.Node, runner command.Runner, cr cruntime.Manager) error {
+		for _, img := range images {
+			if err := cr.PullImage(img); err != nil {
+				return errors.Wrapf(err, "pulling %s", img)
+			}
+		}
+		return nil
+	})
+}
@@ -478,3 +478,38 @@ func TestGuestClockDelta(t *testing.T) {
 		t.Errorf("unexpectedly negative delta (remote too far behind): %s", got)
 	}
 }
+
+func TestAcquireMachinesLockQueues(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	first, err := acquireMachinesLock("minikube-m02", driver.VirtualBox)
+	if err != nil {
+		t.Fatalf("acquireMachinesLock: %v", err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		second, err := acquireMachinesLock("minikube-m03", driver.VirtualBox)
+		if err == nil {
+			second.Release()
+		}
+		acquired <- err
+	}()
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("acquired the machines lock of the driver while another node held it: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	first.Release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("acquireMachinesLock: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("the machines lock was not handed over once released")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// CopyToNode copies a file or directory from the host to dst within the node, preserving permissions
+func CopyToNode(cr command.Runner, src string, dst string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	// Like cp, copying into an existing directory keeps the source name
+	if strings.HasSuffix(dst, "/") || guestIsDir(cr, dst) {
+		dst = path.Join(dst, filepath.Base(src))
+	}
+	dst = path.Clean(dst)
+
+	var fs []assets.CopyableFile
+	if fi.IsDir() {
+		fs, err = assetsFromDir(src, dst, false)
+		if err != nil {
+			return errors.Wrapf(err, "scanning %s", src)
+		}
+	} else {
+		f, err := assets.NewFileAsset(src, path.Dir(dst), path.Base(dst), permString(fi.Mode()))
+		if err != nil {
+			return errors.Wrapf(err, "creating file asset for %s", src)
+		}
+		fs = append(fs, f)
+	}
+
+	klog.Infof("copying %d files from %s to %s", len(fs), src, dst)
+	return copyAssets(cr, fs)
+}
+
+// CopyFromNode copies a file or directory from within the node to dst on the host, preserving permissions
+func CopyFromNode(cr command.Runner, src string, dst string) error {
+	src = path.Clean(src)
+	rr, err := cr.RunCmd(exec.Command("sudo", "tar", "-C", path.Dir(src), "-cf", "-", path.Base(src)))
+	if err != nil {
+		return errors.Wrapf(err, "archiving %s", src)
+	}
+
+	// Like cp, copying into an existing directory keeps the source name
+	if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, path.Base(src))
+	}
+	return untar(&rr.Stdout, path.Base(src), dst)
+}
+
+// guestIsDir returns whether p is an existing directory within the node
+func guestIsDir(cr command.Runner, p string) bool {
+	_, err := cr.RunCmd(exec.Command("sudo", "test", "-d", p))
+	return err == nil
+}
+
+// untar extracts an archive whose entries are rooted at name, renaming that root to dst
+func untar(r io.Reader, name string, dst string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading archive")
+		}
+
+		rel := strings.TrimPrefix(strings.TrimPrefix(path.Clean(hdr.Name), name), "/")
+		if strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("refusing to extract %q outside of %s", hdr.Name, dst)
+		}
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr, mode); err != nil {
+				return errors.Wrapf(err, "writing %s", target)
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			klog.Warningf("skipping %s: unsupported file type %q", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// writeFile writes the contents of r to a file with the given permissions
+func writeFile(name string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// OpenFile only applies the mode to new files, and is subject to the umask
+	return os.Chmod(name, mode)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestCopyFromNode(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	entries := []struct {
+		name    string
+		mode    int64
+		content string
+	}{
+		{"data/", 0o755, ""},
+		{"data/a.txt", 0o600, "a"},
+		{"data/sub/", 0o700, ""},
+		{"data/sub/b.sh", 0o755, "#!/bin/sh\n"},
+	}
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: e.mode, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.content == "" {
+			hdr.Typeflag = tar.TypeDir
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo tar -C /var/lib -cf - data": buf.String(),
+	})
+
+	dir, err := ioutil.TempDir("", "cp")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		description string
+		dst         string
+		root        string
+	}{
+		{"new path", filepath.Join(dir, "copied"), filepath.Join(dir, "copied")},
+		{"existing directory", dir, filepath.Join(dir, "data")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if err := CopyFromNode(f, "/var/lib/data", tc.dst); err != nil {
+				t.Fatalf("CopyFromNode: %v", err)
+			}
+
+			for _, e := range entries[1:] {
+				if e.content == "" {
+					continue
+				}
+				p := filepath.Join(tc.root, filepath.FromSlash(e.name[len("data/"):]))
+				fi, err := os.Stat(p)
+				if err != nil {
+					t.Fatalf("stat: %v", err)
+				}
+				if got := int64(fi.Mode().Perm()); got != e.mode {
+					t.Errorf("%s mode = %o, want %o", p, got, e.mode)
+				}
+				b, err := ioutil.ReadFile(p)
+				if err != nil {
+					t.Fatalf("read: %v", err)
+				}
+				if string(b) != e.content {
+					t.Errorf("%s = %q, want %q", p, b, e.content)
+				}
+			}
+		})
+	}
+}
@@ -40,13 +40,17 @@ var guaranteed = map[string]bool{
 	"/tmp": true,
 }
 
-// syncLocalAssets syncs files from MINIKUBE_HOME into the cluster
-func syncLocalAssets(cr command.Runner) error {
+// SyncLocalAssets syncs files from MINIKUBE_HOME into the cluster
+func SyncLocalAssets(cr command.Runner) error {
 	fs, err := localAssets()
 	if err != nil {
 		return err
 	}
+	return copyAssets(cr, fs)
+}
 
+// copyAssets copies assets into place, creating their target directories as needed
+func copyAssets(cr command.Runner, fs []assets.CopyableFile) error {
 	if len(fs) == 0 {
 		return nil
 	}
@@ -59,6 +63,7 @@ func syncLocalAssets(cr command.Runner) error {
 		if guaranteed[dir] || seen[dir] {
 			continue
 		}
+		seen[dir] = true
 		create = append(create, dir)
 	}
 
@@ -125,12 +130,7 @@ func assetsFromDir(localRoot string, destRoot string, flatten bool) ([]assets.Co
 			return nil
 		}
 
-		// The conversion will strip the leading 0 if present, so add it back if necessary
-		ps := fmt.Sprintf("%o", fi.Mode().Perm())
-		if len(ps) == 3 {
-			ps = fmt.Sprintf("0%s", ps)
-		}
-
+		ps := permString(fi.Mode())
 		dest, err := syncDest(localRoot, localPath, destRoot, flatten)
 		if err != nil {
 			return err
@@ -148,3 +148,13 @@ func assetsFromDir(localRoot string, destRoot string, flatten bool) ([]assets.Co
 	})
 	return fs, err
 }
+
+// permString returns the permissions of a file mode in the octal form assets expect
+func permString(m os.FileMode) string {
+	// The conversion will strip the leading 0 if present, so add it back if necessary
+	ps := fmt.Sprintf("%o", m.Perm())
+	if len(ps) == 3 {
+		ps = fmt.Sprintf("0%s", ps)
+	}
+	return ps
+}
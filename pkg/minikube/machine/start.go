@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/machine/libmachine"
@@ -136,7 +137,7 @@ func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (
 			See https://minikube.sigs.k8s.io/docs/reference/drivers/vmware/ for more information.
 			To disable this message, run [minikube config set ShowDriverDeprecationNotification false]`)
 	}
-	showHostInfo(*cfg)
+	showHostInfo(*cfg, *n)
 	def := registry.Driver(cfg.Driver)
 	if def.Empty() {
 		return nil, fmt.Errorf("unsupported/missing driver: %s", cfg.Driver)
@@ -290,10 +291,10 @@ func postStartSetup(h *host.Host, mc config.ClusterConfig) error {
 	if driver.BareMetal(mc.Driver) {
 		showLocalOsRelease()
 	}
-	if driver.IsVM(mc.Driver) || driver.IsKIC(mc.Driver) {
+	if driver.IsVM(mc.Driver) || driver.IsKIC(mc.Driver) || driver.IsSSH(mc.Driver) {
 		logRemoteOsRelease(r)
 	}
-	return syncLocalAssets(r)
+	return SyncLocalAssets(r)
 }
 
 // acquireMachinesLock protects against code that is not parallel-safe (libmachine, cert setup)
@@ -311,18 +312,55 @@ func acquireMachinesLock(name string, drv string) (mutex.Releaser, error) {
 		spec.Timeout = 10 * time.Minute
 	}
 
-	klog.Infof("acquiring machines lock for %s: %+v", name, spec)
+	// Nodes provisioned concurrently by this process queue up first, so that the timeout only applies to other processes
+	local := localMachinesLock(lockPath)
 	start := time.Now()
+	local.Lock()
+	klog.Infof("acquiring machines lock for %s: %+v", name, spec)
 	r, err := mutex.Acquire(spec)
-	if err == nil {
-		klog.Infof("acquired machines lock for %q in %s", name, time.Since(start))
+	if err != nil {
+		local.Unlock()
+		return nil, err
+	}
+	klog.Infof("acquired machines lock for %q in %s", name, time.Since(start))
+	return machinesLock{Releaser: r, local: local}, nil
+}
+
+// machinesLock is a machines lock held by a goroutine of this process
+type machinesLock struct {
+	mutex.Releaser
+	local *sync.Mutex
+}
+
+// Release releases the machines lock, then lets the next goroutine of this process waiting on it acquire it
+func (l machinesLock) Release() {
+	l.Releaser.Release()
+	l.local.Unlock()
+}
+
+var (
+	localMachinesLocksMu sync.Mutex
+	localMachinesLocks   = map[string]*sync.Mutex{}
+)
+
+// localMachinesLock returns the mutex queueing the goroutines of this process waiting on the machines lock at lockPath
+func localMachinesLock(lockPath string) *sync.Mutex {
+	localMachinesLocksMu.Lock()
+	defer localMachinesLocksMu.Unlock()
+	if _, ok := localMachinesLocks[lockPath]; !ok {
+		localMachinesLocks[lockPath] = &sync.Mutex{}
 	}
-	return r, err
+	return localMachinesLocks[lockPath]
 }
 
 // showHostInfo shows host information
-func showHostInfo(cfg config.ClusterConfig) {
+func showHostInfo(cfg config.ClusterConfig, n config.Node) {
 	machineType := driver.MachineType(cfg.Driver)
+	if driver.IsSSH(cfg.Driver) {
+		register.Reg.SetStep(register.CreatingVM)
+		out.T(style.StartingVM, "Provisioning {{.machine_type}} {{.address}} over SSH ...", out.V{"machine_type": machineType, "address": n.SSH.IPAddress})
+		return
+	}
 	if driver.BareMetal(cfg.Driver) {
 		info, cpuErr, memErr, DiskErr := CachedHostInfo()
 		if cpuErr == nil && memErr == nil && DiskErr == nil {
@@ -331,13 +369,14 @@ func showHostInfo(cfg config.ClusterConfig) {
 		}
 		return
 	}
+	cpus, memory, diskSize := config.NodeResources(cfg, n)
 	if driver.IsKIC(cfg.Driver) { // TODO:medyagh add free disk space on docker machine
 		register.Reg.SetStep(register.CreatingContainer)
-		out.T(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": cfg.CPUs, "memory_size": cfg.Memory, "machine_type": machineType})
+		out.T(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": cpus, "memory_size": memory, "machine_type": machineType})
 		return
 	}
 	register.Reg.SetStep(register.CreatingVM)
-	out.T(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": cfg.CPUs, "memory_size": cfg.Memory, "disk_size": cfg.DiskSize, "machine_type": machineType})
+	out.T(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": cpus, "memory_size": memory, "disk_size": diskSize, "machine_type": machineType})
 }
 
 // AddHostAlias makes fine adjustments to pod resources that aren't possible via kubeadm config.
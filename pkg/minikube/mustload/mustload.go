@@ -121,7 +121,7 @@ func Running(name string) ClusterController {
 		exit.Error(reason.InternalCommandRunner, "Unable to get command runner", err)
 	}
 
-	hostname, ip, port, err := driver.ControlPlaneEndpoint(cc, &cp, host.DriverName)
+	hostname, ip, port, err := driver.APIServerEndpoint(cc, &cp, host.DriverName)
 	if err != nil {
 		exit.Error(reason.DrvCPEndpoint, "Unable to get forwarded endpoint", err)
 	}
@@ -28,6 +28,8 @@ import (
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/download"
@@ -44,6 +46,8 @@ import (
 const (
 	cacheImages         = "cache-images"
 	cacheImageConfigKey = "cache"
+	// loadBalancerAddon assigns addresses reserved on start to LoadBalancer services
+	loadBalancerAddon = "loadbalancer-controller"
 )
 
 // BeginCacheKubernetesImages caches images required for Kubernetes version in the background
@@ -68,6 +72,27 @@ func beginCacheKubernetesImages(g *errgroup.Group, imageRepository string, k8sVe
 	})
 }
 
+// beginCacheExtraImages caches the images which are not part of the preloaded images, but that highly available
+// clusters and the loadbalancer-controller addon need
+func beginCacheExtraImages(g *errgroup.Group, cc *config.ClusterConfig) {
+	if !viper.GetBool(cacheImages) {
+		return
+	}
+	var imgs []string
+	if cc.HA {
+		imgs = append(imgs, images.KubeVIP(cc.KubernetesConfig.ImageRepository))
+	}
+	if assets.Addons[loadBalancerAddon].IsRequested(cc) {
+		imgs = append(imgs, images.LoadBalancerController(cc.KubernetesConfig.ImageRepository))
+	}
+	if len(imgs) == 0 {
+		return
+	}
+	g.Go(func() error {
+		return image.SaveToDir(imgs, constants.ImageCacheDir)
+	})
+}
+
 // HandleDownloadOnly caches appropariate binaries and images
 func handleDownloadOnly(cacheGroup, kicGroup *errgroup.Group, k8sVersion string) {
 	// If --download-only, complete the remaining downloads and exit.
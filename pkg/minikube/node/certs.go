@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// HostCertsName is the name certificates kept on the host are listed under
+const HostCertsName = "host"
+
+// Certs are the certificates of a node
+type Certs struct {
+	Name  string
+	Certs []bootstrapper.CertExpiry
+}
+
+// CertExpiries returns when the certificates kept on the host, then those of every node of a running cluster, expire
+func CertExpiries(api libmachine.API, cc *config.ClusterConfig) ([]Certs, error) {
+	host, err := bootstrapper.HostCertExpiries(cc.KubernetesConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "host certs")
+	}
+	certs := []Certs{{Name: HostCertsName, Certs: host}}
+
+	members, err := clusterMembers(api, cc)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		name := driver.MachineName(*cc, m.node)
+		e, err := bootstrapper.NodeCertExpiries(m.runner, m.node)
+		if err != nil {
+			return nil, errors.Wrapf(err, "certs of %s", name)
+		}
+		certs = append(certs, Certs{Name: name, Certs: e})
+	}
+	return certs, nil
+}
+
+// RotateCerts renews the certificates of a running cluster, signed by its CA, and redistributes them to every node: control planes first, then workers.
+func RotateCerts(api libmachine.API, cc *config.ClusterConfig) error {
+	members, err := clusterMembers(api, cc)
+	if err != nil {
+		return err
+	}
+	primary := members[0]
+
+	if err := bootstrapper.RemoveProfileCerts(cc.Name); err != nil {
+		return errors.Wrap(err, "removing profile certs")
+	}
+	for _, m := range members {
+		bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, m.runner)
+		if err != nil {
+			return errors.Wrap(err, "bootstrapper")
+		}
+		out.T(style.Restarting, "Rotating the certificates of {{.name}} ...", out.V{"name": driver.MachineName(*cc, m.node)})
+		if err := bs.RotateCerts(*cc, m.node, m.cr); err != nil {
+			return errors.Wrapf(err, "rotating certs of %s", m.node.Name)
+		}
+	}
+
+	if cc.EmbedCerts {
+		h, err := machine.LoadHost(api, driver.MachineName(*cc, primary.node))
+		if err != nil {
+			return errors.Wrap(err, "loading host")
+		}
+		if err := kubeconfig.Update(setupKubeconfig(h, cc, &primary.node, cc.Name)); err != nil {
+			return errors.Wrap(err, "updating kubeconfig")
+		}
+	}
+
+	out.T(style.Verifying, "Verifying the cluster with its new certificates ...")
+	start := time.Now()
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, primary.runner)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	cs, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "client")
+	}
+	// the API server is unavailable while it restarts
+	serverUp := func() error {
+		_, err := cs.Discovery().ServerVersion()
+		return err
+	}
+	if err := retry.Expo(serverUp, time.Second, upgradeTimeout); err != nil {
+		return errors.Wrap(err, "apiserver")
+	}
+	return waitHealthy(bs, cc, primary, cs, start)
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// The modes of simulated node failures
+const (
+	// FailKill kills the machine of the node, as if it crashed
+	FailKill = "kill"
+	// FailFreeze pauses the machine of the node, as if it hung
+	FailFreeze = "freeze"
+	// FailPartition drops the traffic between the node and the other nodes
+	FailPartition = "partition"
+	// FailLatency delays the traffic of the node
+	FailLatency = "latency"
+	// FailLoss drops a share of the traffic of the node
+	FailLoss = "loss"
+)
+
+// chaosChain is the iptables chain of the rules partitioning a node
+const chaosChain = "MINIKUBE-CHAOS"
+
+// Failure is a simulated failure of a node
+type Failure struct {
+	Mode string
+	// Value is the latency, such as 200ms, or the share of packets lost, such as 5%
+	Value string
+}
+
+func (f Failure) String() string {
+	if f.Value == "" {
+		return f.Mode
+	}
+	return f.Mode + "=" + f.Value
+}
+
+// ParseFailure parses a failure mode: kill, freeze, partition, latency=<duration> or loss=<percentage>%
+func ParseFailure(s string) (Failure, error) {
+	kv := strings.SplitN(s, "=", 2)
+	f := Failure{Mode: kv[0]}
+	if len(kv) == 2 {
+		f.Value = kv[1]
+	}
+
+	switch f.Mode {
+	case FailKill, FailFreeze, FailPartition:
+		if f.Value != "" {
+			return f, fmt.Errorf("the %s mode takes no value", f.Mode)
+		}
+	case FailLatency:
+		d, err := time.ParseDuration(f.Value)
+		if err != nil {
+			return f, fmt.Errorf("invalid latency %q: %v", f.Value, err)
+		}
+		if d < time.Millisecond {
+			return f, fmt.Errorf("latency %s is less than 1ms", d)
+		}
+	case FailLoss:
+		p, err := strconv.ParseFloat(strings.TrimSuffix(f.Value, "%"), 64)
+		if err != nil || !strings.HasSuffix(f.Value, "%") {
+			return f, fmt.Errorf("invalid loss %q, expected a percentage such as 5%%", f.Value)
+		}
+		// Losing every packet would also cut the SSH connection needed to heal the node
+		if p <= 0 || p >= 100 {
+			return f, fmt.Errorf("loss %s is not between 0%% and 100%%, exclusive", f.Value)
+		}
+	default:
+		return f, fmt.Errorf("unknown mode %q, expected kill, freeze, partition, latency=<duration> or loss=<percentage>", f.Mode)
+	}
+	return f, nil
+}
+
+// Fail simulates the failure of a node, which is recorded in the cluster config until the node is healed
+func Fail(api libmachine.API, cc *config.ClusterConfig, n *config.Node, f Failure) error {
+	if n.Failure != "" {
+		return errors.Errorf("node %s already fails with %s", n.Name, n.Failure)
+	}
+	name := driver.MachineName(*cc, *n)
+
+	switch f.Mode {
+	case FailKill:
+		if err := kill(api, *cc, name); err != nil {
+			return errors.Wrap(err, "kill")
+		}
+	case FailFreeze:
+		if err := freeze(*cc, name, true); err != nil {
+			return errors.Wrap(err, "freeze")
+		}
+	default:
+		r, err := nodeRunner(api, name)
+		if err != nil {
+			return err
+		}
+		cmds, err := networkFailureCmds(r, *cc, *n, f)
+		if err != nil {
+			return err
+		}
+		for _, c := range cmds {
+			if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+				return errors.Wrapf(err, "%s", f.Mode)
+			}
+		}
+	}
+
+	n.Failure = f.String()
+	return config.SaveNode(cc, n)
+}
+
+// Heal undoes the simulated failure of a node. Nodes which were killed are not started again.
+func Heal(api libmachine.API, cc *config.ClusterConfig, n *config.Node) error {
+	if n.Failure == "" {
+		return errors.Errorf("node %s has no simulated failure", n.Name)
+	}
+	f, err := ParseFailure(n.Failure)
+	if err != nil {
+		return err
+	}
+	name := driver.MachineName(*cc, *n)
+
+	switch f.Mode {
+	case FailKill:
+	case FailFreeze:
+		if err := freeze(*cc, name, false); err != nil {
+			return errors.Wrap(err, "unfreeze")
+		}
+	default:
+		r, err := nodeRunner(api, name)
+		if err != nil {
+			return err
+		}
+		c, err := healNetworkCmd(r, *n, f)
+		if err != nil {
+			return err
+		}
+		if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+			return errors.Wrapf(err, "heal %s", f.Mode)
+		}
+	}
+
+	n.Failure = ""
+	return config.SaveNode(cc, n)
+}
+
+// nodeRunner returns a command runner for the machine of a node
+func nodeRunner(api libmachine.API, name string) (command.Runner, error) {
+	h, err := machine.LoadHost(api, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "load host")
+	}
+	return machine.CommandRunner(h)
+}
+
+// kill kills the machine of a node without shutting it down
+func kill(api libmachine.API, cc config.ClusterConfig, name string) error {
+	if driver.IsKIC(cc.Driver) {
+		// The driver stops the kubelet before killing the container, which a crash would not
+		return oci.KillContainer(cc.Driver, name)
+	}
+	h, err := machine.LoadHost(api, name)
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	return h.Driver.Kill()
+}
+
+// freeze pauses or resumes the machine of a node: containers with their runtime, and VMs with their hypervisor
+func freeze(cc config.ClusterConfig, name string, pause bool) error {
+	if driver.IsKIC(cc.Driver) {
+		if pause {
+			return oci.PauseContainer(cc.Driver, name)
+		}
+		return oci.UnpauseContainer(cc.Driver, name)
+	}
+
+	c, err := hypervisorFreezeCmd(cc, name, pause)
+	if err != nil {
+		return err
+	}
+	klog.Infof("running %s", c.Args)
+	if out, err := c.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%s: %s", c.Args, out)
+	}
+	return nil
+}
+
+// hypervisorFreezeCmd returns the command which pauses or resumes a VM
+func hypervisorFreezeCmd(cc config.ClusterConfig, name string, pause bool) (*exec.Cmd, error) {
+	switch cc.Driver {
+	case driver.KVM2:
+		uri := cc.KVMQemuURI
+		if uri == "" {
+			uri = "qemu:///system"
+		}
+		if pause {
+			return exec.Command("virsh", "-c", uri, "suspend", name), nil
+		}
+		return exec.Command("virsh", "-c", uri, "resume", name), nil
+	case driver.VirtualBox:
+		if pause {
+			return exec.Command(driver.VBoxManagePath(), "controlvm", name, "pause"), nil
+		}
+		return exec.Command(driver.VBoxManagePath(), "controlvm", name, "resume"), nil
+	case driver.HyperV:
+		if pause {
+			return exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "Hyper-V\\Suspend-VM", name), nil
+		}
+		return exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "Hyper-V\\Resume-VM", name), nil
+	case driver.HyperKit:
+		pid, err := ioutil.ReadFile(filepath.Join(localpath.MiniPath(), "machines", name, "hyperkit.pid"))
+		if err != nil {
+			return nil, errors.Wrap(err, "hyperkit pid")
+		}
+		if pause {
+			return exec.Command("sudo", "kill", "-STOP", strings.TrimSpace(string(pid))), nil
+		}
+		return exec.Command("sudo", "kill", "-CONT", strings.TrimSpace(string(pid))), nil
+	}
+	return nil, errors.Errorf("the %s driver does not support freezing nodes", cc.Driver)
+}
+
+// networkFailureCmds returns the commands which degrade the network of a node
+func networkFailureCmds(r command.Runner, cc config.ClusterConfig, n config.Node, f Failure) ([]string, error) {
+	// The nodes of these drivers are the host itself, or a machine minikube does not own
+	if driver.BareMetal(cc.Driver) || driver.IsSSH(cc.Driver) {
+		return nil, errors.Errorf("the %s driver does not support %s failures, as they would degrade the network of a machine minikube does not own", cc.Driver, f.Mode)
+	}
+	if f.Mode == FailPartition {
+		cmds := []string{
+			fmt.Sprintf("sudo iptables -N %s || sudo iptables -F %s", chaosChain, chaosChain),
+		}
+		for _, hook := range []string{"INPUT", "OUTPUT", "FORWARD"} {
+			cmds = append(cmds, fmt.Sprintf("sudo iptables -C %s -j %s || sudo iptables -I %s -j %s", hook, chaosChain, hook, chaosChain))
+		}
+		peers := []string{}
+		for _, p := range cc.Nodes {
+			if p.Name != n.Name && p.IP != "" {
+				peers = append(peers, p.IP)
+			}
+		}
+		if cc.KubernetesConfig.APIServerHAVIP != "" {
+			peers = append(peers, cc.KubernetesConfig.APIServerHAVIP)
+		}
+		for _, ip := range peers {
+			cmds = append(cmds, fmt.Sprintf("sudo iptables -A %s -s %s -j DROP", chaosChain, ip), fmt.Sprintf("sudo iptables -A %s -d %s -j DROP", chaosChain, ip))
+		}
+		return cmds, nil
+	}
+
+	dev, err := nodeInterface(r, n.IP)
+	if err != nil {
+		return nil, err
+	}
+	netem := fmt.Sprintf("loss %s", f.Value)
+	if f.Mode == FailLatency {
+		d, err := time.ParseDuration(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		netem = fmt.Sprintf("delay %dms", d/time.Millisecond)
+	}
+	return []string{fmt.Sprintf("sudo tc qdisc replace dev %s root netem %s", dev, netem)}, nil
+}
+
+// healNetworkCmd returns the command which undoes the degradation of the network of a node
+func healNetworkCmd(r command.Runner, n config.Node, f Failure) (string, error) {
+	if f.Mode == FailPartition {
+		var cmds []string
+		for _, hook := range []string{"INPUT", "OUTPUT", "FORWARD"} {
+			cmds = append(cmds, fmt.Sprintf("sudo iptables -D %s -j %s", hook, chaosChain))
+		}
+		cmds = append(cmds, fmt.Sprintf("sudo iptables -F %s", chaosChain), fmt.Sprintf("sudo iptables -X %s", chaosChain))
+		return strings.Join(cmds, "; "), nil
+	}
+
+	dev, err := nodeInterface(r, n.IP)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sudo tc qdisc del dev %s root", dev), nil
+}
+
+// nodeInterface returns the network interface of a node with its IP
+func nodeInterface(r command.Runner, ip string) (string, error) {
+	rr, err := r.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show", "to", ip))
+	if err != nil {
+		return "", errors.Wrap(err, "ip addr")
+	}
+	fields := strings.Fields(rr.Stdout.String())
+	if len(fields) < 2 {
+		return "", errors.Errorf("no interface has the IP %s", ip)
+	}
+	return fields[1], nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+func TestParseFailure(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    Failure
+		wantErr bool
+	}{
+		{mode: "kill", want: Failure{Mode: FailKill}},
+		{mode: "freeze", want: Failure{Mode: FailFreeze}},
+		{mode: "partition", want: Failure{Mode: FailPartition}},
+		{mode: "latency=200ms", want: Failure{Mode: FailLatency, Value: "200ms"}},
+		{mode: "loss=5%", want: Failure{Mode: FailLoss, Value: "5%"}},
+		{mode: "kill=now", wantErr: true},
+		{mode: "latency", wantErr: true},
+		{mode: "latency=10us", wantErr: true},
+		{mode: "loss=5", wantErr: true},
+		{mode: "loss=0%", wantErr: true},
+		{mode: "loss=100%", wantErr: true},
+		{mode: "loss=101%", wantErr: true},
+		{mode: "explode", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.mode, func(t *testing.T) {
+			got, err := ParseFailure(tc.mode)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseFailure(%q) error = %v, wantErr %v", tc.mode, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got != tc.want {
+				t.Errorf("ParseFailure(%q) = %+v, want %+v", tc.mode, got, tc.want)
+			}
+			if got.String() != tc.mode {
+				t.Errorf("String() = %q, want %q", got.String(), tc.mode)
+			}
+		})
+	}
+}
+
+func TestNetworkFailureCmds(t *testing.T) {
+	cc := config.ClusterConfig{
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.49.2", ControlPlane: true},
+			{Name: "m02", IP: "192.168.49.3"},
+			{Name: "m03", IP: "192.168.49.4"},
+		},
+	}
+	n := cc.Nodes[1]
+	r := command.NewFakeCommandRunner()
+	r.SetCommandToOutput(map[string]string{
+		"ip -o -4 addr show to 192.168.49.3": "2: eth0    inet 192.168.49.3/24 brd 192.168.49.255 scope global eth0",
+	})
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{mode: "latency=200ms", want: []string{"sudo tc qdisc replace dev eth0 root netem delay 200ms"}},
+		{mode: "loss=5%", want: []string{"sudo tc qdisc replace dev eth0 root netem loss 5%"}},
+		{mode: "partition", want: []string{
+			"sudo iptables -A MINIKUBE-CHAOS -s 192.168.49.2 -j DROP",
+			"sudo iptables -A MINIKUBE-CHAOS -d 192.168.49.4 -j DROP",
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.mode, func(t *testing.T) {
+			f, err := ParseFailure(tc.mode)
+			if err != nil {
+				t.Fatalf("ParseFailure: %v", err)
+			}
+			cmds, err := networkFailureCmds(r, cc, n, f)
+			if err != nil {
+				t.Fatalf("networkFailureCmds: %v", err)
+			}
+			got := strings.Join(cmds, "\n")
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("commands do not contain %q:\n%s", w, got)
+				}
+			}
+			if strings.Contains(got, n.IP+" ") {
+				t.Errorf("commands drop the traffic of the node itself:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestNetworkFailureCmdsUnsupportedDrivers(t *testing.T) {
+	for _, drv := range []string{driver.None, driver.SSH} {
+		t.Run(drv, func(t *testing.T) {
+			cc := config.ClusterConfig{Driver: drv, Nodes: []config.Node{{Name: "", IP: "192.168.49.2", ControlPlane: true}}}
+			for _, f := range []Failure{{Mode: FailPartition}, {Mode: FailLatency, Value: "200ms"}, {Mode: FailLoss, Value: "5%"}} {
+				if cmds, err := networkFailureCmds(command.NewFakeCommandRunner(), cc, cc.Nodes[0], f); err == nil {
+					t.Errorf("networkFailureCmds(%s) = %v, expected an error", f, cmds)
+				}
+			}
+		})
+	}
+}
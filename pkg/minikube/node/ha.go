@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// virtualIP returns a free virtual IP fronting the API servers of a highly available cluster.
+// It is taken from the top of the /24 network of the primary control plane, above the addresses
+// handed out by the DHCP servers used by minikube drivers and by the load balancer pool,
+// and only if nothing on the network already answers for it.
+func virtualIP(runner command.Runner, primary string) (string, error) {
+	ip := net.ParseIP(primary).To4()
+	if ip == nil {
+		return "", fmt.Errorf("%q is not an IPv4 address", primary)
+	}
+
+	vip := make(net.IP, len(ip))
+	copy(vip, ip)
+	for last := 254; last >= 250; last-- {
+		vip[3] = byte(last)
+		if vip.Equal(ip) {
+			continue
+		}
+		if inUse(runner, vip.String()) {
+			klog.Infof("%s is in use, trying the next address", vip)
+			continue
+		}
+		return vip.String(), nil
+	}
+	return "", fmt.Errorf("no free address for the virtual IP in %s.250-254", strings.Join(strings.Split(ip.String(), ".")[:3], "."))
+}
+
+// inUse returns whether a host on the network of the node answers for ip, to ping or to ARP
+func inUse(runner command.Runner, ip string) bool {
+	rr, err := runner.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("ping -c 1 -W 1 %s >/dev/null 2>&1 && echo alive; ip neigh show %s", ip, ip)))
+	if err != nil {
+		klog.Warningf("unable to check whether %s is in use: %v", ip, err)
+		return false
+	}
+	return strings.Contains(rr.Stdout.String(), "alive") || strings.Contains(rr.Stdout.String(), "lladdr")
+}
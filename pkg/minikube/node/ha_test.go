@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// probe returns the command inUse runs for ip
+func probe(ip string) string {
+	return fmt.Sprintf(`/bin/bash -c "ping -c 1 -W 1 %s >/dev/null 2>&1 && echo alive; ip neigh show %s"`, ip, ip)
+}
+
+func TestVirtualIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary string
+		probes  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "free",
+			primary: "192.168.49.2",
+			probes:  map[string]string{probe("192.168.49.254"): "192.168.49.254 dev eth0  FAILED\n"},
+			want:    "192.168.49.254",
+		},
+		{
+			name:    "answers ping",
+			primary: "192.168.49.2",
+			probes: map[string]string{
+				probe("192.168.49.254"): "alive\n",
+				probe("192.168.49.253"): "",
+			},
+			want: "192.168.49.253",
+		},
+		{
+			name:    "answers arp",
+			primary: "192.168.49.2",
+			probes: map[string]string{
+				probe("192.168.49.254"): "192.168.49.254 dev eth0 lladdr 02:42:c0:a8:31:fe STALE\n",
+				probe("192.168.49.253"): "",
+			},
+			want: "192.168.49.253",
+		},
+		{
+			name:    "primary",
+			primary: "192.168.49.254",
+			probes:  map[string]string{probe("192.168.49.253"): ""},
+			want:    "192.168.49.253",
+		},
+		{
+			name:    "all taken",
+			primary: "192.168.49.2",
+			probes: map[string]string{
+				probe("192.168.49.254"): "alive\n",
+				probe("192.168.49.253"): "alive\n",
+				probe("192.168.49.252"): "alive\n",
+				probe("192.168.49.251"): "alive\n",
+				probe("192.168.49.250"): "alive\n",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "ipv6",
+			primary: "fd00::2",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := command.NewFakeCommandRunner()
+			runner.SetCommandToOutput(tc.probes)
+			got, err := virtualIP(runner, tc.primary)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("virtualIP(%s) error = %v, want error %t", tc.primary, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("virtualIP(%s) = %q, want %q", tc.primary, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/logs"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/version"
+)
+
+// LogRecords returns the log records of the nodes of a running cluster, or of the named node only, merged in chronological order.
+// Nodes whose logs cannot be read are skipped, and returned in the error.
+func LogRecords(api libmachine.API, cc *config.ClusterConfig, name string, o logs.RecordOptions) ([]logs.Record, error) {
+	members, err := clusterMembers(api, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets [][]logs.Record
+	var failed []string
+	found := false
+	for _, m := range members {
+		if name != "" && m.node.Name != name && driver.MachineName(*cc, m.node) != name {
+			continue
+		}
+		found = true
+		machineName := driver.MachineName(*cc, m.node)
+		records, err := logs.Records(machineName, m.cr, m.runner, o)
+		if err != nil {
+			klog.Warningf("logs of %s: %v", machineName, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", machineName, err))
+		}
+		sets = append(sets, records)
+	}
+	if !found {
+		return nil, fmt.Errorf("node %q not found", name)
+	}
+
+	records := logs.MergeRecords(sets...)
+	if len(failed) > 0 {
+		return records, fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return records, nil
+}
+
+// bundleHostInfo is the host info of a diagnostic bundle
+type bundleHostInfo struct {
+	MinikubeVersion string
+	Commit          string
+	OS              string
+	Arch            string
+	CPUs            int
+	MemoryMB        int64
+	DiskSizeMB      int64
+	Errors          []string `json:",omitempty"`
+}
+
+// DiagnosticBundle writes a gzipped tarball of the diagnostics of every node of a cluster to w.
+// Nodes that are not running, and an API server that is down, are recorded in the bundle rather than failing it.
+func DiagnosticBundle(api libmachine.API, cc *config.ClusterConfig, w io.Writer) error {
+	b := logs.NewBundle(w)
+	if err := b.AddConfig("cluster/config.json", *cc); err != nil {
+		return err
+	}
+	if err := addHostInfo(b, "host/info.json"); err != nil {
+		return err
+	}
+
+	nodes, err := orderedNodes(cc)
+	if err != nil {
+		return err
+	}
+	events := false
+	for _, n := range nodes {
+		name := driver.MachineName(*cc, n)
+		dir := path.Join("nodes", name)
+		if driver.IsKIC(cc.Driver) {
+			debug := oci.ContainerDebug(cc.Driver, name)
+			files := make([]string, 0, len(debug))
+			for file := range debug {
+				files = append(files, file)
+			}
+			sort.Strings(files)
+			for _, file := range files {
+				if err := b.Add(path.Join("kic", name, file), debug[file]); err != nil {
+					return err
+				}
+			}
+		}
+
+		h, err := machine.LoadHost(api, name)
+		if err != nil {
+			b.Fail(dir, errors.Wrap(err, "loading host"))
+			continue
+		}
+		if s, err := h.Driver.GetState(); err != nil || s != state.Running {
+			b.Fail(dir, fmt.Errorf("host is not running: %v (%v)", s, err))
+			continue
+		}
+		r, err := machine.CommandRunner(h)
+		if err != nil {
+			b.Fail(dir, errors.Wrap(err, "command runner"))
+			continue
+		}
+		cr, err := cruntime.ForCluster(cc.KubernetesConfig, r)
+		if err != nil {
+			b.Fail(dir, errors.Wrap(err, "runtime"))
+			continue
+		}
+		if err := b.AddNode(dir, r, cr, n.ControlPlane); err != nil {
+			return err
+		}
+		// Events are read through the first running control plane, as the API server may be down on the others
+		if n.ControlPlane && !events {
+			if err := b.AddEvents("cluster/events.txt", r, *cc); err != nil {
+				return err
+			}
+			events = true
+		}
+	}
+	if !events {
+		b.Fail("cluster/events.txt", fmt.Errorf("no control plane node is running"))
+	}
+	return b.Close()
+}
+
+// addHostInfo adds the version of minikube and the resources of the host to the bundle
+func addHostInfo(b *logs.Bundle, name string) error {
+	info := bundleHostInfo{
+		MinikubeVersion: version.GetVersion(),
+		Commit:          version.GetGitCommitID(),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+	}
+	hi, cpuErr, memErr, diskErr := machine.CachedHostInfo()
+	for _, err := range []error{cpuErr, memErr, diskErr} {
+		if err != nil {
+			info.Errors = append(info.Errors, err.Error())
+		}
+	}
+	if hi != nil {
+		info.CPUs = hi.CPUs
+		info.MemoryMB = hi.Memory
+		info.DiskSizeMB = hi.DiskSize
+	}
+	data, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	return b.Add(name, data)
+}
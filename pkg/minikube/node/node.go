@@ -18,13 +18,17 @@ package node
 
 import (
 	"fmt"
-	"os/exec"
+	"time"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"k8s.io/klog/v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -60,8 +64,9 @@ func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
 	return err
 }
 
-// Delete stops and deletes the given node from the given cluster
-func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
+// Delete stops and deletes the given node from the given cluster, which it should have been drained from.
+// If force is set, the node is deleted even if Kubernetes can not be told to forget it.
+func Delete(cc config.ClusterConfig, name string, force bool) (*config.Node, error) {
 	n, index, err := Retrieve(cc, name)
 	if err != nil {
 		return n, errors.Wrap(err, "retrieve")
@@ -73,44 +78,74 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		return n, err
 	}
 
-	// grab control plane to use kubeconfig
-	host, err := machine.LoadHost(api, cc.Name)
-	if err != nil {
-		return n, err
+	if err := deleteNodeObject(cc, m); err != nil {
+		if !force {
+			return n, err
+		}
+		klog.Warningf("unable to delete node object %s: %v", m, err)
+	}
+
+	if driver.IsSSH(cc.Driver) {
+		// The machines of the ssh driver outlive the cluster, so Kubernetes is uninstalled from them
+		if err := uninstall(api, cc, *n); err != nil {
+			klog.Warningf("unable to uninstall Kubernetes from %s: %v", m, err)
+		}
 	}
 
-	runner, err := machine.CommandRunner(host)
+	err = machine.DeleteHost(api, m)
 	if err != nil {
 		return n, err
 	}
 
-	// kubectl drain
-	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
-	cmd := exec.Command("sudo", "KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl, "drain", m)
-	if _, err := runner.RunCmd(cmd); err != nil {
-		klog.Warningf("unable to scale coredns replicas to 1: %v", err)
-	} else {
-		klog.Infof("successfully scaled coredns replicas to 1")
-	}
+	cc.Nodes = append(cc.Nodes[:index], cc.Nodes[index+1:]...)
+	return n, config.SaveProfile(viper.GetString(config.ProfileName), &cc)
+}
 
-	// kubectl delete
+// Drain cordons a node and evicts its pods, so that they are rescheduled on the other nodes of the cluster
+func Drain(cc config.ClusterConfig, n config.Node, force bool, timeout time.Duration) error {
 	client, err := kapi.Client(cc.Name)
 	if err != nil {
-		return n, err
+		return errors.Wrap(err, "kubernetes client")
 	}
+	return kapi.DrainNode(client, driver.MachineName(cc, n), force, timeout)
+}
 
-	err = client.CoreV1().Nodes().Delete(m, nil)
+// Uncordon allows pods to be scheduled on a node again
+func Uncordon(cc config.ClusterConfig, n config.Node) error {
+	client, err := kapi.Client(cc.Name)
 	if err != nil {
-		return n, err
+		return errors.Wrap(err, "kubernetes client")
 	}
+	return kapi.UncordonNode(client, driver.MachineName(cc, n))
+}
 
-	err = machine.DeleteHost(api, m)
+// deleteNodeObject deletes the node object of a machine, so that Kubernetes forgets the node
+func deleteNodeObject(cc config.ClusterConfig, name string) error {
+	client, err := kapi.Client(cc.Name)
 	if err != nil {
-		return n, err
+		return errors.Wrap(err, "kubernetes client")
 	}
+	if err := client.CoreV1().Nodes().Delete(name, nil); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "delete node %s", name)
+	}
+	return nil
+}
 
-	cc.Nodes = append(cc.Nodes[:index], cc.Nodes[index+1:]...)
-	return n, config.SaveProfile(viper.GetString(config.ProfileName), &cc)
+// uninstall uninstalls Kubernetes from the machine of a node
+func uninstall(api libmachine.API, cc config.ClusterConfig, n config.Node) error {
+	h, err := machine.LoadHost(api, driver.MachineName(cc, n))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), cc, r)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	return bs.DeleteCluster(cc.KubernetesConfig)
 }
 
 // Retrieve finds the node by name in the given cluster
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// AddNodes adds new nodes to an existing cluster. Control plane nodes are added one at a time, then up to parallel
+// workers are provisioned concurrently. Workers which fail are deleted again, so that the cluster is left with those
+// which joined.
+func AddNodes(cc *config.ClusterConfig, nodes []config.Node, parallel int, delOnFail bool) error {
+	return startNodes(cc, nodes, parallel, delOnFail, true)
+}
+
+// RestartNodes restarts the existing nodes of a cluster, provisioning up to parallel workers concurrently
+func RestartNodes(cc *config.ClusterConfig, nodes []config.Node, parallel int, delOnFail bool) error {
+	return startNodes(cc, nodes, parallel, delOnFail, false)
+}
+
+// startNodes starts nodes of a cluster, deleting the workers which fail if rollback is set
+func startNodes(cc *config.ClusterConfig, nodes []config.Node, parallel int, delOnFail bool, rollback bool) error {
+	var workers []config.Node
+	for _, n := range nodes {
+		if !n.ControlPlane {
+			workers = append(workers, n)
+			continue
+		}
+		// Control plane nodes join etcd, which only tolerates one member being added at a time
+		out.Ln("")
+		if err := Add(cc, n, delOnFail); err != nil {
+			return errors.Wrapf(err, "adding node %s", n.Name)
+		}
+	}
+	if len(workers) == 0 {
+		return nil
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	// Every worker is saved before any is started, so that the copies of the config they update agree on the nodes
+	for i := range workers {
+		if err := config.SaveNode(cc, &workers[i]); err != nil {
+			return errors.Wrap(err, "save node")
+		}
+	}
+	if err := prepareMachines(cc, workers[0].KubernetesVersion); err != nil {
+		return err
+	}
+	waitCacheRequiredImages(&cacheGroup)
+
+	out.Ln("")
+	out.T(style.ThumbsUp, "Starting {{.count}} worker nodes in cluster {{.cluster}}, {{.parallel}} at a time ...", out.V{"count": len(workers), "cluster": cc.Name, "parallel": parallel})
+
+	started := make([]*config.Node, len(workers))
+	errs := make([]error, len(workers))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			started[i], errs[i] = startWorker(copyConfig(cc), workers[i], delOnFail)
+		}(i)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, n := range workers {
+		if errs[i] == nil {
+			if err := config.SaveNode(cc, started[i]); err != nil {
+				return errors.Wrap(err, "save node")
+			}
+			continue
+		}
+		klog.Errorf("failed to start node %s: %v", n.Name, errs[i])
+		out.FailureT("Failed to start node {{.name}}: {{.error}}", out.V{"name": n.Name, "error": errs[i]})
+		failed = append(failed, fmt.Sprintf("%s: %v", n.Name, errs[i]))
+		if rollback {
+			if err := removeWorker(cc, n); err != nil {
+				klog.Warningf("unable to remove node %s: %v", n.Name, err)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d nodes failed to start:\n%s", len(failed), len(workers), strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// startWorker provisions a worker node and joins it to the cluster, returning the node with its IP
+func startWorker(cc *config.ClusterConfig, n config.Node, delOnFail bool) (*config.Node, error) {
+	name := driver.MachineName(*cc, n)
+	out.Step(n.Name, register.StartingNode, style.ThumbsUp, "Starting node {{.name}} in cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
+	r, p, m, h, err := startMachine(cc, &n, delOnFail)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	out.Step(n.Name, register.PreparingKubernetes, style.Waiting, "Joining node {{.name}} to cluster {{.cluster}} ...", out.V{"name": name, "cluster": cc.Name})
+	s := Starter{
+		Runner:     r,
+		PreExists:  p,
+		MachineAPI: m,
+		Host:       h,
+		Cfg:        cc,
+		Node:       &n,
+	}
+	if _, err := Start(s, false); err != nil {
+		return nil, err
+	}
+	out.Step(n.Name, register.VerifyingKubernetes, style.Ready, "Node {{.name}} joined cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
+	return &n, nil
+}
+
+// removeWorker deletes a worker which failed to join the cluster, along with its machine
+func removeWorker(cc *config.ClusterConfig, n config.Node) error {
+	m := driver.MachineName(*cc, n)
+	out.T(style.DeletingHost, "Removing node {{.name}} ...", out.V{"name": m})
+
+	if err := deleteNodeObject(*cc, m); err != nil {
+		klog.Infof("unable to delete node object %s: %v", m, err)
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	if err := machine.DeleteHost(api, m); err != nil {
+		// The machine may never have been created
+		klog.Warningf("unable to delete host %s: %v", m, err)
+	}
+
+	for i := range cc.Nodes {
+		if cc.Nodes[i].Name == n.Name {
+			cc.Nodes = append(cc.Nodes[:i], cc.Nodes[i+1:]...)
+			break
+		}
+	}
+	return config.SaveProfile(viper.GetString(config.ProfileName), cc)
+}
+
+// copyConfig copies a cluster config, so that the nodes started in parallel do not update each other's
+func copyConfig(cc *config.ClusterConfig) *config.ClusterConfig {
+	c := *cc
+	c.Nodes = append([]config.Node(nil), cc.Nodes...)
+	return &c
+}
@@ -37,6 +37,8 @@ import (
 	"k8s.io/minikube/pkg/addons"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/auth"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/cluster"
@@ -104,6 +106,25 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	var bs bootstrapper.Bootstrapper
 	var kcs *kubeconfig.Settings
 	if apiServer {
+		if starter.Cfg.HA && starter.Cfg.KubernetesConfig.APIServerHAVIP == "" {
+			vip, err := virtualIP(starter.Runner, starter.Node.IP)
+			if err != nil {
+				return nil, errors.Wrap(err, "virtual ip")
+			}
+			starter.Cfg.KubernetesConfig.APIServerHAVIP = vip
+			out.T(style.Connectivity, "Using virtual IP {{.vip}} for the API servers of highly available cluster {{.cluster}}", out.V{"vip": vip, "cluster": starter.Cfg.Name})
+		}
+		// Only reserved for the addon, as the metallb addon takes the range as its default
+		if assets.Addons[loadBalancerAddon].IsRequested(starter.Cfg) {
+			if err := addons.ReserveLoadBalancerPool(starter.Cfg); err != nil {
+				out.WarningT("Unable to reserve addresses for LoadBalancer services: {{.error}}", out.V{"error": err})
+			}
+		}
+
+		if err := auth.Setup(starter.Cfg, starter.Node.IP); err != nil {
+			return nil, errors.Wrap(err, "authentication")
+		}
+
 		// Must be written before bootstrap, otherwise health checks may flake due to stale IP
 		kcs = setupKubeconfig(starter.Host, starter.Cfg, starter.Node, starter.Cfg.Name)
 		if err != nil {
@@ -176,7 +197,7 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 			return nil, errors.Wrap(err, "getting control plane bootstrapper")
 		}
 
-		joinCmd, err := cpBs.GenerateToken(*starter.Cfg)
+		joinCmd, err := cpBs.GenerateToken(*starter.Cfg, *starter.Node)
 		if err != nil {
 			return nil, errors.Wrap(err, "generating join token")
 		}
@@ -217,30 +238,40 @@ func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFa
 		out.T(style.ThumbsUp, "Starting node {{.name}} in cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
 	}
 
+	if err := prepareMachines(cc, n.KubernetesVersion); err != nil {
+		return nil, false, nil, nil, err
+	}
+
+	return startMachine(cc, n, delOnFail)
+}
+
+// prepareMachines downloads what the machines of a cluster are created from
+func prepareMachines(cc *config.ClusterConfig, k8sVersion string) error {
 	if driver.IsKIC(cc.Driver) {
 		beginDownloadKicBaseImage(&kicGroup, cc, viper.GetBool("download-only"))
 	}
 
 	if !driver.BareMetal(cc.Driver) {
-		beginCacheKubernetesImages(&cacheGroup, cc.KubernetesConfig.ImageRepository, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime)
+		beginCacheKubernetesImages(&cacheGroup, cc.KubernetesConfig.ImageRepository, k8sVersion, cc.KubernetesConfig.ContainerRuntime)
+		beginCacheExtraImages(&cacheGroup, cc)
 	}
 
 	// Abstraction leakage alert: startHost requires the config to be saved, to satistfy pkg/provision/buildroot.
 	// Hence, SaveProfile must be called before startHost, and again afterwards when we know the IP.
 	if err := config.SaveProfile(viper.GetString(config.ProfileName), cc); err != nil {
-		return nil, false, nil, nil, errors.Wrap(err, "Failed to save config")
+		return errors.Wrap(err, "Failed to save config")
 	}
 
-	handleDownloadOnly(&cacheGroup, &kicGroup, n.KubernetesVersion)
+	handleDownloadOnly(&cacheGroup, &kicGroup, k8sVersion)
 	waitDownloadKicBaseImage(&kicGroup)
-
-	return startMachine(cc, n, delOnFail)
+	return nil
 }
 
 // ConfigureRuntimes does what needs to happen to get a runtime going.
 func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, kv semver.Version) cruntime.Manager {
 	co := cruntime.Config{
 		Type:              cc.KubernetesConfig.ContainerRuntime,
+		Socket:            cc.KubernetesConfig.CRISocket,
 		Runner:            runner,
 		ImageRepository:   cc.KubernetesConfig.ImageRepository,
 		KubernetesVersion: kv,
@@ -321,17 +352,25 @@ func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clu
 		ClusterServerAddress: addr,
 		ClientCertificate:    localpath.ClientCert(cc.Name),
 		ClientKey:            localpath.ClientKey(cc.Name),
-		CertificateAuthority: localpath.CACert(),
+		CertificateAuthority: bootstrapper.CACertPath(cc.KubernetesConfig),
 		KeepContext:          cc.KeepContext,
 		EmbedCerts:           cc.EmbedCerts,
 	}
+	if mode := cc.KubernetesConfig.Auth.Mode; mode != "" {
+		user, err := auth.KubeconfigUser(cc.KubernetesConfig)
+		if err != nil {
+			exit.Error(reason.Usage, "Unable to configure the kubeconfig user of the authentication mode", err)
+		}
+		kcs.AuthUser = auth.UserName(clusterName, mode)
+		kcs.AuthInfo = user
+	}
 
 	kcs.SetPath(kubeconfig.PathFromEnv())
 	return kcs
 }
 
 func apiServerURL(h host.Host, cc config.ClusterConfig, n config.Node) (string, error) {
-	hostname, _, port, err := driver.ControlPlaneEndpoint(&cc, &n, h.DriverName)
+	hostname, _, port, err := driver.APIServerEndpoint(&cc, &n, h.DriverName)
 	if err != nil {
 		return "", err
 	}
@@ -438,7 +477,8 @@ func validateNetwork(h *host.Host, r command.Runner, imageRepository string) (st
 		}
 	}
 
-	if !driver.BareMetal(h.Driver.DriverName()) && !driver.IsKIC(h.Driver.DriverName()) {
+	// The ssh driver checks that its machine is reachable on its own port
+	if driver.IsVM(h.Driver.DriverName()) {
 		if err := trySSH(h, ip); err != nil {
 			return ip, err
 		}
@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/etcd"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// upgradeTimeout is how long an upgraded cluster has to become healthy
+const upgradeTimeout = 6 * time.Minute
+
+// member is a node of a cluster being upgraded, along with the means to manage it
+type member struct {
+	node   config.Node
+	runner command.Runner
+	cr     cruntime.Manager
+}
+
+// Upgrade upgrades the Kubernetes version of a running cluster in place: control planes first, then workers, which are drained beforehand.
+// A snapshot of etcd is saved before anything changes, and the cluster is rolled back to it if the upgrade fails.
+func Upgrade(api libmachine.API, cc *config.ClusterConfig, version string) error {
+	members, err := clusterMembers(api, cc)
+	if err != nil {
+		return err
+	}
+	cs, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "client")
+	}
+
+	out.T(style.HealthCheck, "Verifying that {{.cluster}} is healthy before upgrading ...", out.V{"cluster": cc.Name})
+	if err := kverify.WaitForNodeReady(cs, 30*time.Second); err != nil {
+		return errors.Wrap(err, "pre-flight")
+	}
+	if err := kverify.NodePressure(cs); err != nil {
+		return errors.Wrap(err, "pre-flight")
+	}
+
+	etcdMembers, err := etcd.Members(api, *cc)
+	if err != nil {
+		return errors.Wrap(err, "etcd members")
+	}
+	snapshot := etcdSnapshotPath(cc.Name, cc.KubernetesConfig.KubernetesVersion)
+	out.T(style.Caching, "Saving a snapshot of etcd on {{.name}} to {{.path}} ...", out.V{"name": etcdMembers[0].Machine, "path": snapshot})
+	if err := saveSnapshot(etcdMembers[0], snapshot); err != nil {
+		return errors.Wrap(err, "backing up etcd")
+	}
+
+	upgraded := *cc
+	upgraded.KubernetesConfig.KubernetesVersion = version
+	upgraded.Nodes = nil
+	for _, n := range cc.Nodes {
+		n.KubernetesVersion = version
+		upgraded.Nodes = append(upgraded.Nodes, n)
+	}
+
+	if err := upgradeMembers(api, &upgraded, members); err != nil {
+		out.ErrT(style.Embarrassed, "Upgrade failed, rolling back to Kubernetes {{.version}}: {{.error}}", out.V{"version": cc.KubernetesConfig.KubernetesVersion, "error": err})
+		if rerr := rollback(api, cc, members, etcdMembers, snapshot); rerr != nil {
+			return errors.Wrapf(rerr, "rollback after upgrade failure (%v)", err)
+		}
+		return errors.Wrap(err, "upgrade was rolled back")
+	}
+
+	*cc = upgraded
+	return config.SaveProfile(cc.Name, cc)
+}
+
+// clusterMembers returns the nodes of a cluster in upgrade order: the primary control plane, the other control planes, then workers
+func clusterMembers(api libmachine.API, cc *config.ClusterConfig) ([]member, error) {
+	nodes, err := orderedNodes(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []member
+	for _, n := range nodes {
+		h, err := machine.LoadHost(api, driver.MachineName(*cc, n))
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading host %s", n.Name)
+		}
+		r, err := machine.CommandRunner(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "command runner for %s", n.Name)
+		}
+		cr, err := cruntime.ForCluster(cc.KubernetesConfig, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "runtime")
+		}
+		members = append(members, member{node: n, runner: r, cr: cr})
+	}
+	return members, nil
+}
+
+// orderedNodes returns the nodes of a cluster: the primary control plane first, then the other control planes, then the workers
+func orderedNodes(cc *config.ClusterConfig) ([]config.Node, error) {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "primary control plane")
+	}
+	nodes := []config.Node{cp}
+	for _, ctrl := range []bool{true, false} {
+		for _, n := range cc.Nodes {
+			if n.ControlPlane == ctrl && n.Name != cp.Name {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// upgradeMembers upgrades each node in turn to the version of the given cluster config, then verifies the cluster
+func upgradeMembers(api libmachine.API, cc *config.ClusterConfig, members []member) error {
+	primary := members[0]
+	for _, m := range members {
+		name := driver.MachineName(*cc, m.node)
+		bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, m.runner)
+		if err != nil {
+			return errors.Wrap(err, "bootstrapper")
+		}
+
+		if !m.node.ControlPlane {
+			out.T(style.Waiting, "Draining node {{.name}} ...", out.V{"name": name})
+			if err := Drain(*cc, m.node, true, upgradeTimeout); err != nil {
+				return errors.Wrapf(err, "draining %s", name)
+			}
+		}
+
+		out.T(style.Launch, "Upgrading {{.name}} to Kubernetes {{.version}} ...", out.V{"name": name, "version": cc.KubernetesConfig.KubernetesVersion})
+		if err := bs.UpgradeNode(*cc, m.node, m.cr); err != nil {
+			return errors.Wrapf(err, "upgrading %s", name)
+		}
+
+		if !m.node.ControlPlane {
+			if err := Uncordon(*cc, m.node); err != nil {
+				return errors.Wrapf(err, "uncordoning %s", name)
+			}
+		}
+	}
+
+	out.T(style.Verifying, "Verifying the upgraded cluster ...")
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, primary.runner)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	return verifyVersion(bs, cc, primary)
+}
+
+// rollback restores the etcd snapshot on every control plane, and returns every node to the version of the given cluster config
+func rollback(api libmachine.API, cc *config.ClusterConfig, members []member, etcdMembers []etcd.Member, snapshot string) error {
+	if err := etcd.RestoreSnapshot(etcdMembers, snapshot); err != nil {
+		return errors.Wrap(err, "restoring etcd")
+	}
+
+	for _, m := range members {
+		bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, m.runner)
+		if err != nil {
+			return errors.Wrap(err, "bootstrapper")
+		}
+		if err := bs.RollbackNode(*cc, m.node, m.cr); err != nil {
+			return errors.Wrapf(err, "rolling back %s", m.node.Name)
+		}
+		if !m.node.ControlPlane {
+			if err := Uncordon(*cc, m.node); err != nil {
+				klog.Warningf("unable to uncordon %s: %v", m.node.Name, err)
+			}
+		}
+	}
+
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, members[0].runner)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	return verifyVersion(bs, cc, members[0])
+}
+
+// verifyVersion waits for the API server and every kubelet to run the version of the given cluster config, and for system pods to be healthy
+func verifyVersion(bs bootstrapper.Bootstrapper, cc *config.ClusterConfig, primary member) error {
+	start := time.Now()
+	version := cc.KubernetesConfig.KubernetesVersion
+	cs, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "client")
+	}
+
+	versionsMatch := func() error {
+		if err := kverify.APIServerVersionMatch(cs, version); err != nil {
+			return err
+		}
+		return kubeletVersionsMatch(cs, version)
+	}
+	if err := retry.Expo(versionsMatch, time.Second, upgradeTimeout); err != nil {
+		return err
+	}
+	return waitHealthy(bs, cc, primary, cs, start)
+}
+
+// waitHealthy waits for every node to be ready, and for system pods to be healthy
+func waitHealthy(bs bootstrapper.Bootstrapper, cc *config.ClusterConfig, primary member, cs *kubernetes.Clientset, start time.Time) error {
+	if err := kverify.WaitForNodeReady(cs, upgradeTimeout); err != nil {
+		return err
+	}
+	return kverify.WaitForSystemPods(primary.cr, bs, *cc, primary.runner, cs, start, upgradeTimeout)
+}
+
+// kubeletVersionsMatch checks that every node runs the expected version of the kubelet
+func kubeletVersionsMatch(cs *kubernetes.Clientset, expected string) error {
+	nodes, err := cs.CoreV1().Nodes().List(meta.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing nodes")
+	}
+	for _, n := range nodes.Items {
+		if v := n.Status.NodeInfo.KubeletVersion; v != expected {
+			return fmt.Errorf("kubelet of %s = %q, expected: %q", n.Name, v, expected)
+		}
+	}
+	return nil
+}
+
+// saveSnapshot saves a snapshot of etcd to a file on the host
+func saveSnapshot(m etcd.Member, dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	err = etcd.SaveSnapshot(m, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// etcdSnapshotPath is where etcd of a cluster is saved to before upgrading from a version
+func etcdSnapshotPath(cluster string, version string) string {
+	return filepath.Join(localpath.Profile(cluster), fmt.Sprintf("etcd-%s.db", version))
+}
@@ -87,6 +87,17 @@ func T(st style.Enum, format string, a ...V) {
 	String(outStyled)
 }
 
+// Step writes a stylized and templated message to stdout for a step reached by one of the nodes being started in parallel
+func Step(node string, step register.RegStep, st style.Enum, format string, a ...V) {
+	outStyled := stylized(st, useColor, format, a...)
+	if JSON {
+		register.PrintNodeStep(node, step, outStyled)
+		return
+	}
+	register.RecordNodeStep(node, step, outStyled)
+	String(outStyled)
+}
+
 // Infof is used for informational logs (options, env variables, etc)
 func Infof(format string, a ...V) {
 	outStyled := stylized(style.Option, useColor, format, a...)
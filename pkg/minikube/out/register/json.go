@@ -22,18 +22,36 @@ func PrintStep(message string) {
 	printAndRecordCloudEvent(s, s.data)
 }
 
+// PrintNodeStep prints a Step type reached by a single node in JSON format
+func PrintNodeStep(node string, step RegStep, message string) {
+	s := NewNodeStep(node, step, message)
+	printAndRecordCloudEvent(s, s.data)
+}
+
 // RecordStep records a Step type in JSON format
 func RecordStep(message string) {
 	s := NewStep(message)
 	recordCloudEvent(s, s.data)
 }
 
+// RecordNodeStep records a Step type reached by a single node in JSON format
+func RecordNodeStep(node string, step RegStep, message string) {
+	s := NewNodeStep(node, step, message)
+	recordCloudEvent(s, s.data)
+}
+
 // PrintInfo prints an Info type in JSON format
 func PrintInfo(message string) {
 	s := NewInfo(message)
 	printAsCloudEvent(s, s.data)
 }
 
+// MarshalInfo returns an Info type in JSON format, for writers other than the configured one
+func MarshalInfo(message string) ([]byte, error) {
+	s := NewInfo(message)
+	return cloudEvent(s, s.data).MarshalJSON()
+}
+
 // PrintDownload prints a Download type in JSON format
 func PrintDownload(artifact string) {
 	s := NewDownload(artifact)
@@ -43,7 +43,18 @@ func NewStep(message string) *Step {
 		"totalsteps":  Reg.totalSteps(),
 		"currentstep": Reg.currentStep(),
 		"message":     strings.TrimSpace(message),
-		"name":        string(Reg.current),
+		"name":        Reg.currentName(),
+	}}
+}
+
+// NewNodeStep returns a new step type for a step reached by one of the nodes being started in parallel
+func NewNodeStep(node string, step RegStep, message string) *Step {
+	return &Step{data: map[string]string{
+		"totalsteps":  Reg.totalSteps(),
+		"currentstep": Reg.nodeStep(step),
+		"message":     strings.TrimSpace(message),
+		"name":        string(step),
+		"node":        node,
 	}}
 }
 
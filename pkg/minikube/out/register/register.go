@@ -19,6 +19,7 @@ package register
 
 import (
 	"fmt"
+	"sync"
 
 	"k8s.io/klog/v2"
 )
@@ -38,10 +39,11 @@ const (
 	EnablingAddons       RegStep = "Enabling Addons"
 	Done                 RegStep = "Done"
 
-	Stopping  RegStep = "Stopping"
-	Deleting  RegStep = "Deleting"
-	Pausing   RegStep = "Pausing"
-	Unpausing RegStep = "Unpausing"
+	Stopping      RegStep = "Stopping"
+	ScheduledStop RegStep = "Scheduled Stop"
+	Deleting      RegStep = "Deleting"
+	Pausing       RegStep = "Pausing"
+	Unpausing     RegStep = "Unpausing"
 )
 
 // RegStep is a type representing a distinct step of `minikube start`
@@ -50,6 +52,8 @@ type RegStep string
 // Register holds all of the steps we could see in `minikube start`
 // and keeps track of the current step
 type Register struct {
+	// mu guards first and current, which nodes started in parallel update concurrently
+	mu      sync.Mutex
 	steps   map[RegStep][]RegStep
 	first   RegStep
 	current RegStep
@@ -78,21 +82,38 @@ func init() {
 				Done,
 			},
 
-			Stopping:  {Stopping, Done},
-			Pausing:   {Pausing, Done},
-			Unpausing: {Unpausing, Done},
-			Deleting:  {Deleting, Stopping, Deleting, Done},
+			Stopping:      {Stopping, Done},
+			ScheduledStop: {ScheduledStop, Done},
+			Pausing:       {Pausing, Done},
+			Unpausing:     {Unpausing, Done},
+			Deleting:      {Deleting, Stopping, Deleting, Done},
 		},
 	}
 }
 
 // totalSteps returns the total number of steps in the register
 func (r *Register) totalSteps() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return fmt.Sprintf("%d", len(r.steps[r.first])-1)
 }
 
 // currentStep returns the current step we are on
 func (r *Register) currentStep() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stepIndex(r.current)
+}
+
+// currentName returns the name of the current step
+func (r *Register) currentName() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return string(r.current)
+}
+
+// stepIndex returns the position of a step within the registered steps
+func (r *Register) stepIndex(step RegStep) string {
 	if r.first == RegStep("") {
 		return ""
 	}
@@ -103,18 +124,27 @@ func (r *Register) currentStep() string {
 	}
 
 	for i, s := range r.steps[r.first] {
-		if r.current == s {
+		if step == s {
 			return fmt.Sprintf("%d", i)
 		}
 	}
 
 	// Warn, as sometimes detours happen: "start" may cause "stopping" and "deleting"
-	klog.Warningf("%q was not found within the registered steps for %q: %v", r.current, r.first, steps)
+	klog.Warningf("%q was not found within the registered steps for %q: %v", step, r.first, steps)
 	return ""
 }
 
+// nodeStep returns the position of a step reached by a single node, without changing the current step
+func (r *Register) nodeStep(s RegStep) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stepIndex(s)
+}
+
 // SetStep sets the current step
 func (r *Register) SetStep(s RegStep) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if r.first == RegStep("") {
 		_, ok := r.steps[s]
 		if ok {
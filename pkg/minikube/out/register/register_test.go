@@ -48,3 +48,30 @@ func TestSetCurrentStep(t *testing.T) {
 		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
 	}
 }
+
+func TestPrintNodeStep(t *testing.T) {
+	Reg.SetStep(InitialSetup)
+	Reg.SetStep(PreparingKubernetes)
+
+	expected := `{"data":{"currentstep":"3","message":"message","name":"%s","node":"m02","totalsteps":"%v"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.step"}`
+	expected = fmt.Sprintf(expected, StartingNode, Reg.totalSteps())
+	expected += "\n"
+
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	defer func() { SetOutputFile(os.Stdout) }()
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	PrintNodeStep("m02", StartingNode, "message")
+	actual := buf.String()
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+	if Reg.currentName() != string(PreparingKubernetes) {
+		t.Errorf("PrintNodeStep changed the current step to %q", Reg.currentName())
+	}
+}
@@ -200,7 +200,11 @@ var (
 		Issues:   []int{9165},
 	}
 
+	HostBundleImport        = Kind{ID: "HOST_BUNDLE_IMPORT", ExitCode: ExHostError}
 	HostCurrentUser         = Kind{ID: "HOST_CURRENT_USER", ExitCode: ExHostConfig}
+	HostLogBundle           = Kind{ID: "HOST_LOG_BUNDLE", ExitCode: ExHostError}
+	HostDaemon              = Kind{ID: "HOST_DAEMON", ExitCode: ExHostError}
+	HostDNS                 = Kind{ID: "HOST_DNS", ExitCode: ExHostError}
 	HostDelCache            = Kind{ID: "HOST_DEL_CACHE", ExitCode: ExHostError}
 	HostKillMountProc       = Kind{ID: "HOST_KILL_MOUNT_PROC", ExitCode: ExHostError}
 	HostKubeconfigUnset     = Kind{ID: "HOST_KUBECNOFIG_UNSET", ExitCode: ExHostConfig}
@@ -234,22 +238,34 @@ var (
 
 	GuestCacheLoad        = Kind{ID: "GUEST_CACHE_LOAD", ExitCode: ExGuestError}
 	GuestCert             = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
+	GuestCopy             = Kind{ID: "GUEST_COPY", ExitCode: ExGuestError}
 	GuestCpConfig         = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
 	GuestDeletion         = Kind{ID: "GUEST_DELETION", ExitCode: ExGuestError}
+	GuestImageBuild       = Kind{ID: "GUEST_IMAGE_BUILD", ExitCode: ExGuestError}
+	GuestImageList        = Kind{ID: "GUEST_IMAGE_LIST", ExitCode: ExGuestError}
+	GuestImageLoad        = Kind{ID: "GUEST_IMAGE_LOAD", ExitCode: ExGuestError}
+	GuestImagePull        = Kind{ID: "GUEST_IMAGE_PULL", ExitCode: ExGuestError}
+	GuestImageRemove      = Kind{ID: "GUEST_IMAGE_REMOVE", ExitCode: ExGuestError}
 	GuestLoadHost         = Kind{ID: "GUEST_LOAD_HOST", ExitCode: ExGuestError}
 	GuestMount            = Kind{ID: "GUEST_MOUNT", ExitCode: ExGuestError}
 	GuestMountConflict    = Kind{ID: "GUEST_MOUNT_CONFLICT", ExitCode: ExGuestConflict}
 	GuestNodeAdd          = Kind{ID: "GUEST_NODE_ADD", ExitCode: ExGuestError}
 	GuestNodeDelete       = Kind{ID: "GUEST_NODE_DELETE", ExitCode: ExGuestError}
+	GuestNodeDrain        = Kind{ID: "GUEST_NODE_DRAIN", ExitCode: ExGuestError}
+	GuestNodeFail         = Kind{ID: "GUEST_NODE_FAIL", ExitCode: ExGuestError}
 	GuestNodeProvision    = Kind{ID: "GUEST_NODE_PROVISION", ExitCode: ExGuestError}
 	GuestNodeRetrieve     = Kind{ID: "GUEST_NODE_RETRIEVE", ExitCode: ExGuestNotFound}
 	GuestNodeStart        = Kind{ID: "GUEST_NODE_START", ExitCode: ExGuestError}
 	GuestPause            = Kind{ID: "GUEST_PAUSE", ExitCode: ExGuestError}
 	GuestProfileDeletion  = Kind{ID: "GUEST_PROFILE_DELETION", ExitCode: ExGuestError}
 	GuestProvision        = Kind{ID: "GUEST_PROVISION", ExitCode: ExGuestError}
+	GuestSnapshotList     = Kind{ID: "GUEST_SNAPSHOT_LIST", ExitCode: ExGuestError}
+	GuestSnapshotRestore  = Kind{ID: "GUEST_SNAPSHOT_RESTORE", ExitCode: ExGuestError}
+	GuestSnapshotSave     = Kind{ID: "GUEST_SNAPSHOT_SAVE", ExitCode: ExGuestError}
 	GuestStart            = Kind{ID: "GUEST_START", ExitCode: ExGuestError}
 	GuestStatus           = Kind{ID: "GUEST_STATUS", ExitCode: ExGuestError}
 	GuestStopTimeout      = Kind{ID: "GUEST_STOP_TIMEOUT", ExitCode: ExGuestTimeout}
+	GuestStopSchedule     = Kind{ID: "GUEST_STOP_SCHEDULE", ExitCode: ExGuestError}
 	GuestUnpause          = Kind{ID: "GUEST_UNPAUSE", ExitCode: ExGuestError}
 	GuestDrvMismatch      = Kind{ID: "GUEST_DRIVER_MISMATCH", ExitCode: ExGuestConflict, Style: style.Conflict}
 	GuestMissingConntrack = Kind{ID: "GUEST_MISSING_CONNTRACK", ExitCode: ExGuestUnsupported}
@@ -259,6 +275,7 @@ var (
 	IfMountPort = Kind{ID: "IF_MOUNT_PORT", ExitCode: ExLocalNetworkError}
 	IfSSHClient = Kind{ID: "IF_SSH_CLIENT", ExitCode: ExLocalNetworkError}
 
+	InetBundleCreate       = Kind{ID: "INET_BUNDLE_CREATE", ExitCode: ExInternetError}
 	InetCacheBinaries      = Kind{ID: "INET_CACHE_BINARIES", ExitCode: ExInternetError}
 	InetCacheKubectl       = Kind{ID: "INET_CACHE_KUBECTL", ExitCode: ExInternetError}
 	InetCacheTar           = Kind{ID: "INET_CACHE_TAR", ExitCode: ExInternetError}
@@ -290,6 +307,7 @@ var (
 
 	KubernetesInstallFailed = Kind{ID: "K8S_INSTALL_FAILED", ExitCode: ExControlPlaneError}
 	KubernetesTooOld        = Kind{ID: "K8S_OLD_UNSUPPORTED", ExitCode: ExControlPlaneUnsupported}
+	KubernetesUpgradeFailed = Kind{ID: "K8S_UPGRADE_FAILED", ExitCode: ExControlPlaneError}
 	KubernetesDowngrade     = Kind{
 		ID:       "K8S_DOWNGRADE_UNSUPPORTED",
 		ExitCode: ExControlPlaneUnsupported,
@@ -308,4 +326,13 @@ var (
 		`,
 		Style: style.SeeNoEvil,
 	}
+
+	EtcdBackup  = Kind{ID: "K8S_ETCD_BACKUP", ExitCode: ExControlPlaneError}
+	EtcdRestore = Kind{ID: "K8S_ETCD_RESTORE", ExitCode: ExControlPlaneError}
+	EtcdStatus  = Kind{ID: "K8S_ETCD_STATUS", ExitCode: ExControlPlaneUnavailable}
+
+	CertsStatus = Kind{ID: "K8S_CERTS_STATUS", ExitCode: ExControlPlaneUnavailable}
+	CertsRotate = Kind{ID: "K8S_CERTS_ROTATE", ExitCode: ExControlPlaneError}
+
+	AuditLog = Kind{ID: "K8S_AUDIT_LOG", ExitCode: ExControlPlaneError}
 )
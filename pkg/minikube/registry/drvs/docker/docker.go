@@ -51,6 +51,7 @@ func init() {
 }
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, _ := config.NodeResources(cc, n)
 	mounts := make([]oci.Mount, len(cc.ContainerVolumeMounts))
 	for i, spec := range cc.ContainerVolumeMounts {
 		var err error
@@ -72,12 +73,13 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		StorePath:         localpath.MiniPath(),
 		ImageDigest:       cc.KicBaseImage,
 		Mounts:            mounts,
-		CPU:               cc.CPUs,
-		Memory:            cc.Memory,
+		CPU:               cpus,
+		Memory:            memory,
 		OCIBinary:         oci.Docker,
 		APIServerPort:     cc.Nodes[0].Port,
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+		CRISocket:         cc.KubernetesConfig.CRISocket,
 		ExtraArgs:         extraArgs,
 	}), nil
 }
@@ -59,6 +59,7 @@ func init() {
 }
 
 func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, diskSize := config.NodeResources(cfg, n)
 	u := cfg.UUID
 	if u == "" {
 		u = uuid.NewUUID().String()
@@ -71,9 +72,9 @@ func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
 			SSHUser:     "docker",
 		},
 		Boot2DockerURL: download.LocalISOResource(cfg.MinikubeISO),
-		DiskSize:       cfg.DiskSize,
-		Memory:         cfg.Memory,
-		CPU:            cfg.CPUs,
+		DiskSize:       diskSize,
+		Memory:         memory,
+		CPU:            cpus,
 		NFSShares:      cfg.NFSShare,
 		NFSSharesRoot:  cfg.NFSSharesRoot,
 		UUID:           u,
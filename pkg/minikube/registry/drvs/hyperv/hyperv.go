@@ -54,6 +54,7 @@ func init() {
 }
 
 func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, diskSize := config.NodeResources(cfg, n)
 	d := hyperv.NewDriver(driver.MachineName(cfg, n), localpath.MiniPath())
 	d.Boot2DockerURL = download.LocalISOResource(cfg.MinikubeISO)
 	d.VSwitch = cfg.HypervVirtualSwitch
@@ -72,9 +73,9 @@ func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
 		}
 		d.VSwitch = switchName
 	}
-	d.MemSize = cfg.Memory
-	d.CPU = cfg.CPUs
-	d.DiskSize = cfg.DiskSize
+	d.MemSize = memory
+	d.CPU = cpus
+	d.DiskSize = diskSize
 	d.SSHUser = "docker"
 	d.DisableDynamicMemory = true // default to disable dynamic memory as minikube is unlikely to work properly with dynamic memory
 	return d, nil
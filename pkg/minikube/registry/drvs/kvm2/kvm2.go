@@ -69,6 +69,7 @@ type kvmDriver struct {
 }
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, diskSize := config.NodeResources(cc, n)
 	name := driver.MachineName(cc, n)
 	return kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
@@ -76,12 +77,12 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 			StorePath:   localpath.MiniPath(),
 			SSHUser:     "docker",
 		},
-		Memory:         cc.Memory,
-		CPU:            cc.CPUs,
+		Memory:         memory,
+		CPU:            cpus,
 		Network:        cc.KVMNetwork,
 		PrivateNetwork: "minikube-net",
 		Boot2DockerURL: download.LocalISOResource(cc.MinikubeISO),
-		DiskSize:       cc.DiskSize,
+		DiskSize:       diskSize,
 		DiskPath:       filepath.Join(localpath.MiniPath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
 		ISO:            filepath.Join(localpath.MiniPath(), "machines", name, "boot2docker.iso"),
 		GPU:            cc.KVMGPU,
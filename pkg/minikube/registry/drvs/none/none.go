@@ -48,6 +48,7 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		MachineName:      driver.MachineName(cc, n),
 		StorePath:        localpath.MiniPath(),
 		ContainerRuntime: cc.KubernetesConfig.ContainerRuntime,
+		CRISocket:        cc.KubernetesConfig.CRISocket,
 	}), nil
 }
 
@@ -46,11 +46,12 @@ func init() {
 }
 
 func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, diskSize := config.NodeResources(cfg, n)
 	d := parallels.NewDriver(driver.MachineName(cfg, n), localpath.MiniPath()).(*parallels.Driver)
 	d.Boot2DockerURL = download.LocalISOResource(cfg.MinikubeISO)
-	d.Memory = cfg.Memory
-	d.CPU = cfg.CPUs
-	d.DiskSize = cfg.DiskSize
+	d.Memory = memory
+	d.CPU = cpus
+	d.DiskSize = diskSize
 	return d, nil
 }
 
@@ -63,6 +63,7 @@ func init() {
 }
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, _ := config.NodeResources(cc, n)
 	mounts := make([]oci.Mount, len(cc.ContainerVolumeMounts))
 	for i, spec := range cc.ContainerVolumeMounts {
 		var err error
@@ -84,12 +85,13 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		StorePath:         localpath.MiniPath(),
 		ImageDigest:       strings.Split(cc.KicBaseImage, "@")[0], // for podman does not support docker images references with both a tag and digest.
 		Mounts:            mounts,
-		CPU:               cc.CPUs,
-		Memory:            cc.Memory,
+		CPU:               cpus,
+		Memory:            memory,
 		OCIBinary:         oci.Podman,
 		APIServerPort:     cc.Nodes[0].Port,
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+		CRISocket:         cc.KubernetesConfig.CRISocket,
 		ExtraArgs:         extraArgs,
 	}), nil
 }
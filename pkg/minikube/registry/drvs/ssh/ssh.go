@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/ssh"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:     driver.SSH,
+		Config:   configure,
+		Init:     func() drivers.Driver { return ssh.NewDriver(ssh.Config{}) },
+		Status:   status,
+		Priority: registry.Discouraged, // requires existing machines
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	d := ssh.NewDriver(ssh.Config{
+		MachineName:      driver.MachineName(cc, n),
+		StorePath:        localpath.MiniPath(),
+		ContainerRuntime: cc.KubernetesConfig.ContainerRuntime,
+		CRISocket:        cc.KubernetesConfig.CRISocket,
+	})
+	if n.SSH.IPAddress == "" {
+		return nil, errors.Errorf("the ssh driver requires the IP address of node %q", n.Name)
+	}
+	d.IPAddress = n.SSH.IPAddress
+	d.SSHUser = n.SSH.User
+	d.SSHKey = n.SSH.Key
+	d.SSHPort = n.SSH.Port
+	return d, nil
+}
+
+func status() registry.State {
+	return registry.State{Installed: true, Healthy: true}
+}
@@ -52,11 +52,12 @@ func init() {
 }
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, diskSize := config.NodeResources(cc, n)
 	d := virtualbox.NewDriver(driver.MachineName(cc, n), localpath.MiniPath())
 	d.Boot2DockerURL = download.LocalISOResource(cc.MinikubeISO)
-	d.Memory = cc.Memory
-	d.CPU = cc.CPUs
-	d.DiskSize = cc.DiskSize
+	d.Memory = memory
+	d.CPU = cpus
+	d.DiskSize = diskSize
 	d.HostOnlyCIDR = cc.HostOnlyCIDR
 	d.NoShare = cc.DisableDriverMounts
 	d.NoVTXCheck = cc.NoVTXCheck
@@ -41,11 +41,12 @@ func init() {
 }
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	cpus, memory, diskSize := config.NodeResources(cc, n)
 	d := vmwcfg.NewConfig(driver.MachineName(cc, n), localpath.MiniPath())
 	d.Boot2DockerURL = download.LocalISOResource(cc.MinikubeISO)
-	d.Memory = cc.Memory
-	d.CPU = cc.CPUs
-	d.DiskSize = cc.DiskSize
+	d.Memory = memory
+	d.CPU = cpus
+	d.DiskSize = diskSize
 
 	// TODO(frapposelli): push these defaults upstream to fixup this driver
 	d.SSHPort = 22
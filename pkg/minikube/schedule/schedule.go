@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule arms and cancels scheduled stops of minikube clusters
+package schedule
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+)
+
+// unitName is the name of the service which stops the node when the timer fires
+const unitName = "minikube-scheduled-stop"
+
+// unitPath is where the scheduled stop unit lives within the node
+var unitPath = path.Join("/etc/systemd/system", unitName+".service")
+
+// scriptPath is the script run by the unit, which sleeps and then powers off the node
+var scriptPath = path.Join("/usr/local/bin", unitName)
+
+// Daemonize arms a scheduled stop of every running node of each profile after duration.
+// The timer runs inside the nodes, so it survives the minikube process exiting.
+func Daemonize(profiles []string, duration time.Duration) error {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "api client")
+	}
+	defer api.Close()
+
+	for _, profile := range profiles {
+		if err := daemonize(api, profile, duration); err != nil {
+			return errors.Wrapf(err, "scheduling stop for %s", profile)
+		}
+	}
+	return nil
+}
+
+// Supported returns whether the nodes of a driver may be powered off by a scheduled stop: the none driver runs on
+// the host itself, and the ssh driver on machines minikube does not own
+func Supported(drv string) bool {
+	return !driver.BareMetal(drv) && !driver.IsSSH(drv)
+}
+
+func daemonize(api libmachine.API, profile string, duration time.Duration) error {
+	cc, err := config.Load(profile)
+	if err != nil {
+		return errors.Wrap(err, "loading config")
+	}
+	if !Supported(cc.Driver) {
+		return errors.Errorf("the %s driver does not support scheduled stops", cc.Driver)
+	}
+
+	err = machine.ForEachRunningNode(api, cc, func(_ config.Node, runner command.Runner, _ cruntime.Manager) error {
+		sm := sysinit.New(runner)
+		// Replace any timer that is already running
+		if sm.Active(unitName) {
+			if err := sm.Stop(unitName); err != nil {
+				klog.Warningf("unable to stop existing scheduled stop: %v", err)
+			}
+		}
+
+		files := []assets.CopyableFile{
+			assets.NewMemoryAssetTarget(script(duration), scriptPath, "0755"),
+			assets.NewMemoryAssetTarget(unitFile(), unitPath, "0644"),
+		}
+		shims, err := sm.GenerateInitShim(unitName, scriptPath, unitPath)
+		if err != nil {
+			return errors.Wrap(err, "shim")
+		}
+		files = append(files, shims...)
+		for _, f := range files {
+			if err := runner.Copy(f); err != nil {
+				return errors.Wrapf(err, "copy %s", f.GetTargetName())
+			}
+		}
+		return sm.Restart(unitName)
+	})
+	if err != nil {
+		return err
+	}
+
+	cc.ScheduledStop = &config.ScheduledStopConfig{
+		InitiationTime: time.Now().Unix(),
+		Duration:       duration,
+	}
+	return config.SaveProfile(profile, cc)
+}
+
+// KillExisting cancels any scheduled stop armed for the given profiles
+func KillExisting(profiles []string) error {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "api client")
+	}
+	defer api.Close()
+
+	for _, profile := range profiles {
+		if err := killExisting(api, profile); err != nil {
+			return errors.Wrapf(err, "cancelling scheduled stop for %s", profile)
+		}
+	}
+	return nil
+}
+
+func killExisting(api libmachine.API, profile string) error {
+	cc, err := config.Load(profile)
+	if err != nil {
+		return errors.Wrap(err, "loading config")
+	}
+
+	err = machine.ForEachRunningNode(api, cc, func(_ config.Node, runner command.Runner, _ cruntime.Manager) error {
+		sm := sysinit.New(runner)
+		if !sm.Active(unitName) {
+			return nil
+		}
+		return sm.Stop(unitName)
+	})
+	if err != nil {
+		return err
+	}
+
+	if cc.ScheduledStop == nil {
+		return nil
+	}
+	cc.ScheduledStop = nil
+	return config.SaveProfile(profile, cc)
+}
+
+// Remaining returns how long until the scheduled stop of the cluster, or 0 if none is pending
+func Remaining(cc *config.ClusterConfig) time.Duration {
+	return remaining(cc.ScheduledStop, time.Now())
+}
+
+func remaining(ss *config.ScheduledStopConfig, now time.Time) time.Duration {
+	if ss == nil {
+		return 0
+	}
+	left := time.Unix(ss.InitiationTime, 0).Add(ss.Duration).Sub(now)
+	if left < 0 {
+		return 0
+	}
+	return left.Round(time.Second)
+}
+
+// Expire records that the scheduled stop of a cluster has powered it off:
+// the schedule is cleared and the kubeconfig context removed, as a manual stop would.
+func Expire(cc *config.ClusterConfig) error {
+	if cc.ScheduledStop == nil {
+		return nil
+	}
+	if err := kubeconfig.DeleteContext(cc.Name, kubeconfig.PathFromEnv()); err != nil {
+		return errors.Wrap(err, "delete context")
+	}
+	cc.ScheduledStop = nil
+	return config.SaveProfile(cc.Name, cc)
+}
+
+// script returns the command which powers off the node once duration has elapsed
+func script(duration time.Duration) []byte {
+	return []byte(fmt.Sprintf(`#!/bin/bash
+sleep %d && /sbin/poweroff
+`, int64(duration.Seconds())))
+}
+
+// unitFile returns a unit which runs the scheduled stop script
+func unitFile() []byte {
+	return []byte(fmt.Sprintf(`[Unit]
+Description=minikube scheduled stop
+
+[Service]
+Type=simple
+ExecStart=%s
+`, scriptPath))
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+func TestRemaining(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := []struct {
+		name string
+		ss   *config.ScheduledStopConfig
+		want time.Duration
+	}{
+		{"none", nil, 0},
+		{"pending", &config.ScheduledStopConfig{InitiationTime: now.Add(-10 * time.Minute).Unix(), Duration: 30 * time.Minute}, 20 * time.Minute},
+		{"elapsed", &config.ScheduledStopConfig{InitiationTime: now.Add(-time.Hour).Unix(), Duration: 30 * time.Minute}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := remaining(tc.ss, now); got != tc.want {
+				t.Errorf("remaining() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnitFile(t *testing.T) {
+	got := string(unitFile())
+	want := "ExecStart=/usr/local/bin/minikube-scheduled-stop"
+	if !strings.Contains(got, want) {
+		t.Errorf("unitFile() = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestScript(t *testing.T) {
+	got := string(script(90 * time.Second))
+	want := "sleep 90 && /sbin/poweroff"
+	if !strings.Contains(got, want) {
+		t.Errorf("script() = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := map[string]bool{
+		driver.Docker: true,
+		driver.KVM2:   true,
+		driver.None:   false,
+		driver.SSH:    false,
+	}
+	for drv, want := range tests {
+		if got := Supported(drv); got != want {
+			t.Errorf("Supported(%s) = %t, want %t", drv, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// imageName returns the image holding the filesystem of a node container at the time of a snapshot
+func imageName(machineName string, snapshot string) string {
+	return fmt.Sprintf("minikube-snapshot/%s:%s", machineName, snapshot)
+}
+
+// volumeTarball returns the path of the archive holding the /var volume of a node container at the time of a snapshot
+func volumeTarball(dir string, machineName string) string {
+	return filepath.Join(dir, machineName+"-var.tar")
+}
+
+// saveKIC commits the node containers, and archives their /var volumes which commits leave out
+func saveKIC(ociBin string, s *Snapshot, dir string) error {
+	for _, m := range s.Machines {
+		img := imageName(m, s.Name)
+		klog.Infof("saving %s as %s", m, img)
+		if err := oci.CommitContainer(ociBin, m, img); err != nil {
+			return err
+		}
+		if err := oci.SaveVolume(ociBin, m, volumeTarball(dir, m), img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreKIC recreates the node containers from their snapshot images, then restores their /var volumes
+func restoreKIC(api libmachine.API, ociBin string, s *Snapshot, dir string) error {
+	for _, m := range s.Machines {
+		h, err := machine.LoadHost(api, m)
+		if err != nil {
+			return err
+		}
+		d, ok := h.Driver.(*kic.Driver)
+		if !ok {
+			return errors.Errorf("unexpected driver for %s: %T", m, h.Driver)
+		}
+
+		// Create replaces the existing container, and starts the new one with the same settings
+		d.NodeConfig.ImageDigest = imageName(m, s.Name)
+		klog.Infof("recreating %s from %s", m, d.NodeConfig.ImageDigest)
+		if err := d.Create(); err != nil {
+			return errors.Wrapf(err, "recreating %s", m)
+		}
+		if err := api.Save(h); err != nil {
+			return errors.Wrapf(err, "saving %s", m)
+		}
+		if err := d.Stop(); err != nil {
+			return errors.Wrapf(err, "stopping %s", m)
+		}
+
+		if err := oci.RestoreVolume(ociBin, m, volumeTarball(dir, m), d.NodeConfig.ImageDigest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteKIC removes the snapshot images of the node containers
+func deleteKIC(ociBin string, s *Snapshot) {
+	for _, m := range s.Machines {
+		if err := oci.RemoveImage(ociBin, imageName(m, s.Name)); err != nil {
+			klog.Warningf("unable to remove snapshot image: %v", err)
+		}
+	}
+}
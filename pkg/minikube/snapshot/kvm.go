@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// diskTarget is the target of the disk of minikube kvm2 domains, see pkg/drivers/kvm/domain.go
+const diskTarget = "hda"
+
+// domainSnapshot is the part of the libvirt snapshot XML describing the disk of the domain at the time of the snapshot
+type domainSnapshot struct {
+	Disks []struct {
+		Device string `xml:"device,attr"`
+		Driver struct {
+			Type string `xml:"type,attr"`
+		} `xml:"driver"`
+		Source struct {
+			File string `xml:"file,attr"`
+		} `xml:"source"`
+		Target struct {
+			Dev string `xml:"dev,attr"`
+		} `xml:"target"`
+	} `xml:"domain>devices>disk"`
+}
+
+// saveKVM takes libvirt external disk snapshots of the domains: their current disk images become
+// read-only, and writes go to new overlay images from then on.
+func saveKVM(cc *config.ClusterConfig, s *Snapshot) error {
+	for _, m := range s.Machines {
+		overlay := filepath.Join(localpath.MachinePath(m), fmt.Sprintf("%s-%s.qcow2", m, s.Name))
+		if _, err := virsh(cc, "snapshot-create-as", "--domain", m, "--name", s.Name, "--disk-only", "--atomic",
+			"--diskspec", fmt.Sprintf("%s,snapshot=external,file=%s", diskTarget, overlay)); err != nil {
+			return errors.Wrapf(err, "snapshot %s", m)
+		}
+	}
+	return nil
+}
+
+// restoreKVM points the domains to new overlays on top of the disk images which were made read-only by the snapshot.
+// libvirt cannot revert to external snapshots by itself.
+func restoreKVM(cc *config.ClusterConfig, s *Snapshot) error {
+	for _, m := range s.Machines {
+		desc, err := virsh(cc, "snapshot-dumpxml", "--domain", m, "--snapshotname", s.Name)
+		if err != nil {
+			return errors.Wrapf(err, "snapshot %s", m)
+		}
+		base, format, err := snapshotDisk(desc)
+		if err != nil {
+			return errors.Wrapf(err, "snapshot %s", m)
+		}
+
+		overlay := filepath.Join(localpath.MachinePath(m), fmt.Sprintf("%s-%s-%d.qcow2", m, s.Name, time.Now().Unix()))
+		klog.Infof("creating %s on top of %s", overlay, base)
+		c := exec.Command("qemu-img", "create", "-f", "qcow2", "-F", format, "-b", base, overlay)
+		if out, err := c.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "%s: %s", strings.Join(c.Args, " "), out)
+		}
+
+		if _, err := virsh(cc, "detach-disk", "--domain", m, "--target", diskTarget, "--config"); err != nil {
+			return errors.Wrapf(err, "detaching disk of %s", m)
+		}
+		if _, err := virsh(cc, "attach-disk", "--domain", m, "--source", overlay, "--target", diskTarget, "--config",
+			"--driver", "qemu", "--subdriver", "qcow2", "--targetbus", "virtio", "--cache", "default", "--io", "threads"); err != nil {
+			return errors.Wrapf(err, "attaching %s to %s", overlay, m)
+		}
+	}
+	return nil
+}
+
+// snapshotDisk returns the disk image of the domain at the time of a snapshot, and its format
+func snapshotDisk(desc []byte) (string, string, error) {
+	var ds domainSnapshot
+	if err := xml.Unmarshal(desc, &ds); err != nil {
+		return "", "", errors.Wrap(err, "parsing snapshot")
+	}
+	for _, d := range ds.Disks {
+		if d.Device != "disk" || d.Target.Dev != diskTarget {
+			continue
+		}
+		if d.Source.File == "" {
+			break
+		}
+		format := d.Driver.Type
+		if format == "" {
+			format = "raw"
+		}
+		return d.Source.File, format, nil
+	}
+	return "", "", errors.Errorf("no %s disk in snapshot", diskTarget)
+}
+
+// virsh runs a virsh command against the libvirt connection of the cluster
+func virsh(cc *config.ClusterConfig, args ...string) ([]byte, error) {
+	if cc.KVMQemuURI != "" {
+		args = append([]string{"--connect", cc.KVMQemuURI}, args...)
+	}
+	c := exec.Command("virsh", args...)
+	klog.Infof("Run: %s", strings.Join(c.Args, " "))
+	out, err := c.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return out, errors.Wrapf(err, "%s: %s", strings.Join(c.Args, " "), ee.Stderr)
+		}
+		return out, errors.Wrap(err, strings.Join(c.Args, " "))
+	}
+	return out, nil
+}
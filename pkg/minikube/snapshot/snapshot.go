@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot saves and restores named snapshots of stopped minikube clusters
+package snapshot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const (
+	// metadataFile describes a snapshot
+	metadataFile = "snapshot.json"
+	// configFile is the cluster config at the time of a snapshot
+	configFile = "config.json"
+)
+
+// validName is restricted so that snapshot names can be used as image tags and file names
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,127}$`)
+
+// Snapshot describes a snapshot of a cluster
+type Snapshot struct {
+	Name              string
+	Profile           string
+	Driver            string
+	KubernetesVersion string
+	Machines          []string
+	Created           time.Time
+}
+
+// Dir returns the directory holding the snapshots of a profile
+func Dir(profile string) string {
+	return localpath.MakeMiniPath("snapshots", profile)
+}
+
+// Supported returns whether snapshots are supported by a driver
+func Supported(name string) bool {
+	return driver.IsKIC(name) || name == driver.KVM2
+}
+
+// Save takes a snapshot of a stopped cluster
+func Save(api libmachine.API, cc *config.ClusterConfig, name string) (*Snapshot, error) {
+	if !validName.MatchString(name) {
+		return nil, errors.Errorf("invalid snapshot name %q: must consist of lower case alphanumeric characters, '-', '_' or '.'", name)
+	}
+	if !Supported(cc.Driver) {
+		return nil, errors.Errorf("the %s driver does not support snapshots", cc.Driver)
+	}
+
+	dir := filepath.Join(Dir(cc.Name), name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, errors.Errorf("snapshot %q already exists", name)
+	}
+
+	var machines []string
+	for _, n := range cc.Nodes {
+		machines = append(machines, driver.MachineName(*cc, n))
+	}
+	if err := ensureStopped(api, machines); err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Name:              name,
+		Profile:           cc.Name,
+		Driver:            cc.Driver,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		Machines:          machines,
+		Created:           time.Now(),
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	if err := save(cc, s, dir); err != nil {
+		if rerr := os.RemoveAll(dir); rerr != nil {
+			klog.Warningf("unable to remove %s: %v", dir, rerr)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func save(cc *config.ClusterConfig, s *Snapshot, dir string) error {
+	var err error
+	if driver.IsKIC(cc.Driver) {
+		err = saveKIC(cc.Driver, s, dir)
+	} else {
+		err = saveKVM(cc, s)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeJSON(filepath.Join(dir, configFile), cc); err != nil {
+		return errors.Wrap(err, "saving cluster config")
+	}
+	return writeJSON(filepath.Join(dir, metadataFile), s)
+}
+
+// Restore brings a stopped cluster back to the state of a snapshot, so that it resumes from there on the next start
+func Restore(api libmachine.API, cc *config.ClusterConfig, name string) (*Snapshot, error) {
+	s, err := load(cc.Name, name)
+	if err != nil {
+		return nil, err
+	}
+	if s.Driver != cc.Driver {
+		return nil, errors.Errorf("snapshot %q was taken with the %s driver, but the cluster uses the %s driver", name, s.Driver, cc.Driver)
+	}
+
+	dir := filepath.Join(Dir(cc.Name), name)
+	var saved config.ClusterConfig
+	if err := readJSON(filepath.Join(dir, configFile), &saved); err != nil {
+		return nil, errors.Wrap(err, "reading cluster config")
+	}
+
+	var machines []string
+	for _, n := range cc.Nodes {
+		machines = append(machines, driver.MachineName(*cc, n))
+	}
+	if err := ensureStopped(api, machines); err != nil {
+		return nil, err
+	}
+	for _, m := range s.Machines {
+		exists, err := api.Exists(m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s exists", m)
+		}
+		if !exists {
+			return nil, errors.Errorf("machine %q of snapshot %q no longer exists", m, name)
+		}
+	}
+
+	// Nodes added since the snapshot was taken have no place in the restored cluster
+	for _, m := range machines {
+		if contains(s.Machines, m) {
+			continue
+		}
+		klog.Infof("deleting %s, which was added after snapshot %q", m, name)
+		if err := machine.DeleteHost(api, m); err != nil {
+			return nil, errors.Wrapf(err, "deleting %s", m)
+		}
+	}
+
+	if driver.IsKIC(cc.Driver) {
+		err = restoreKIC(api, cc.Driver, s, dir)
+	} else {
+		err = restoreKVM(cc, s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A stop scheduled before the snapshot has long expired
+	saved.ScheduledStop = nil
+	if err := config.SaveProfile(cc.Name, &saved); err != nil {
+		return nil, errors.Wrap(err, "saving cluster config")
+	}
+	return s, nil
+}
+
+// List returns the snapshots of a profile, oldest first
+func List(profile string) ([]Snapshot, error) {
+	entries, err := ioutil.ReadDir(Dir(profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := load(profile, e.Name())
+		if err != nil {
+			klog.Warningf("skipping %s: %v", e.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, *s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// DeleteAll removes all of the snapshots of a profile
+func DeleteAll(profile string) error {
+	snapshots, err := List(profile)
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		if driver.IsKIC(s.Driver) {
+			deleteKIC(s.Driver, &s)
+		}
+	}
+	return os.RemoveAll(Dir(profile))
+}
+
+// load reads the metadata of a snapshot
+func load(profile string, name string) (*Snapshot, error) {
+	var s Snapshot
+	if err := readJSON(filepath.Join(Dir(profile), name, metadataFile), &s); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("snapshot %q does not exist", name)
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ensureStopped returns an error unless all of the machines are stopped
+func ensureStopped(api libmachine.API, machines []string) error {
+	for _, m := range machines {
+		st, err := machine.Status(api, m)
+		if err != nil {
+			return errors.Wrapf(err, "%s status", m)
+		}
+		if st != state.Stopped.String() {
+			return errors.Errorf("%s is %s, the cluster must be stopped first", m, st)
+		}
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, v), "parsing %s", path)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
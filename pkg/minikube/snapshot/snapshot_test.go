@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"seeded":       true,
+		"v1.19-crds_2": true,
+		"":             false,
+		"Seeded":       false,
+		"-seeded":      false,
+		"with space":   false,
+		"../escape":    false,
+	} {
+		if got := validName.MatchString(name); got != want {
+			t.Errorf("validName(%q) = %t, want %t", name, got, want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	home, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, home)
+
+	got, err := List("minikube")
+	if err != nil || len(got) != 0 {
+		t.Fatalf("List() without snapshots = %v, %v", got, err)
+	}
+
+	now := time.Now()
+	for _, s := range []Snapshot{
+		{Name: "second", Profile: "minikube", Created: now},
+		{Name: "first", Profile: "minikube", Created: now.Add(-time.Hour)},
+	} {
+		dir := filepath.Join(Dir("minikube"), s.Name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := writeJSON(filepath.Join(dir, metadataFile), s); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	// Partially written snapshots are skipped
+	if err := os.MkdirAll(filepath.Join(Dir("minikube"), "broken"), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	got, err = List("minikube")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "first" || got[1].Name != "second" {
+		t.Errorf("List() = %+v, want first and second", got)
+	}
+
+	if _, err := load("minikube", "missing"); err == nil {
+		t.Errorf("expected an error loading a missing snapshot")
+	}
+}
+
+func TestSnapshotDisk(t *testing.T) {
+	desc := `<domainsnapshot>
+  <name>seeded</name>
+  <state>shutoff</state>
+  <disks>
+    <disk name='hda' snapshot='external' type='file'>
+      <driver type='qcow2'/>
+      <source file='/home/docker/.minikube/machines/minikube/minikube-seeded.qcow2'/>
+    </disk>
+  </disks>
+  <domain type='kvm'>
+    <name>minikube</name>
+    <devices>
+      <disk type='file' device='cdrom'>
+        <source file='/home/docker/.minikube/machines/minikube/boot2docker.iso'/>
+        <target dev='hdc' bus='scsi'/>
+      </disk>
+      <disk type='file' device='disk'>
+        <driver name='qemu' type='raw' cache='default' io='threads'/>
+        <source file='/home/docker/.minikube/machines/minikube/minikube.rawdisk'/>
+        <target dev='hda' bus='virtio'/>
+      </disk>
+    </devices>
+  </domain>
+</domainsnapshot>`
+
+	file, format, err := snapshotDisk([]byte(desc))
+	if err != nil {
+		t.Fatalf("snapshotDisk: %v", err)
+	}
+	if file != "/home/docker/.minikube/machines/minikube/minikube.rawdisk" || format != "raw" {
+		t.Errorf("snapshotDisk() = %q, %q", file, format)
+	}
+
+	if _, _, err := snapshotDisk([]byte("<domainsnapshot/>")); err == nil {
+		t.Errorf("expected an error for a snapshot without disks")
+	}
+}
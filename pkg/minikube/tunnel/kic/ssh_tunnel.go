@@ -23,7 +23,6 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	"k8s.io/klog/v2"
@@ -33,7 +32,7 @@ import (
 // SSHTunnel ...
 type SSHTunnel struct {
 	ctx                  context.Context
-	sshPort              string
+	sshPort              func() (string, error) // changes when the cluster restarts
 	sshKey               string
 	v1Core               typed_core.CoreV1Interface
 	LoadBalancerEmulator tunnel.LoadBalancerEmulator
@@ -42,13 +41,13 @@ type SSHTunnel struct {
 }
 
 // NewSSHTunnel ...
-func NewSSHTunnel(ctx context.Context, sshPort, sshKey string, v1Core typed_core.CoreV1Interface) *SSHTunnel {
+func NewSSHTunnel(ctx context.Context, sshPort func() (string, error), sshKey string, v1Core typed_core.CoreV1Interface, filter tunnel.ServiceFilter) *SSHTunnel {
 	return &SSHTunnel{
 		ctx:                  ctx,
 		sshPort:              sshPort,
 		sshKey:               sshKey,
 		v1Core:               v1Core,
-		LoadBalancerEmulator: tunnel.NewLoadBalancerEmulator(v1Core),
+		LoadBalancerEmulator: tunnel.NewFilteredLoadBalancerEmulator(v1Core, filter),
 		conns:                make(map[string]*sshConn),
 		connsToStop:          make(map[string]*sshConn),
 	}
@@ -67,15 +66,13 @@ func (t *SSHTunnel) Start() error {
 		default:
 		}
 
-		services, err := t.v1Core.Services("").List(metav1.ListOptions{})
+		services, err := t.LoadBalancerEmulator.Services()
+		t.markConnectionsToBeStopped()
 		if err != nil {
+			// The cluster is stopped or restarting: its connections are re-established once it runs again
 			klog.Errorf("error listing services: %v", err)
-		}
-
-		t.markConnectionsToBeStopped()
-
-		for _, svc := range services.Items {
-			if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		} else {
+			for _, svc := range services {
 				t.startConnection(svc)
 			}
 		}
@@ -103,8 +100,14 @@ func (t *SSHTunnel) startConnection(svc v1.Service) {
 		return
 	}
 
+	sshPort, err := t.sshPort()
+	if err != nil {
+		klog.Errorf("error getting ssh port: %v", err)
+		return
+	}
+
 	// create new ssh conn
-	newSSHConn := createSSHConn(uniqName, t.sshPort, t.sshKey, &svc)
+	newSSHConn := createSSHConn(uniqName, sshPort, t.sshKey, &svc)
 	t.conns[newSSHConn.name] = newSSHConn
 
 	go func() {
@@ -114,7 +117,7 @@ func (t *SSHTunnel) startConnection(svc v1.Service) {
 		}
 	}()
 
-	err := t.LoadBalancerEmulator.PatchServiceIP(t.v1Core.RESTClient(), svc, "127.0.0.1")
+	err = t.LoadBalancerEmulator.PatchServiceIP(t.v1Core.RESTClient(), svc, "127.0.0.1")
 	if err != nil {
 		klog.Errorf("error patching service: %v", err)
 	}
@@ -37,11 +37,43 @@ type patchConverter interface {
 	convert(restClient rest.Interface, patch *Patch) *rest.Request
 }
 
+// ServiceFilter limits the LoadBalancer services which are tunnelled. An empty filter matches every service.
+type ServiceFilter struct {
+	Namespaces []string `json:",omitempty"`
+	// Services are the names of services, or namespace/name
+	Services []string `json:",omitempty"`
+}
+
+// Match returns whether a service passes the filter
+func (f ServiceFilter) Match(svc core.Service) bool {
+	if len(f.Namespaces) > 0 {
+		found := false
+		for _, ns := range f.Namespaces {
+			if ns == svc.Namespace {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Services) == 0 {
+		return true
+	}
+	for _, s := range f.Services {
+		if s == svc.Name || s == svc.Namespace+"/"+svc.Name {
+			return true
+		}
+	}
+	return false
+}
+
 // LoadBalancerEmulator is the main struct for emulating the loadbalancer behavior. it sets the ingress to the cluster IP
 type LoadBalancerEmulator struct {
 	coreV1Client   typed_core.CoreV1Interface
 	requestSender  requestSender
 	patchConverter patchConverter
+	filter         ServiceFilter
 }
 
 // PatchServices will update all load balancer services
@@ -61,21 +93,39 @@ func (l *LoadBalancerEmulator) Cleanup() ([]string, error) {
 	return l.applyOnLBServices(l.cleanupService)
 }
 
-func (l *LoadBalancerEmulator) applyOnLBServices(action func(restClient rest.Interface, svc core.Service) ([]byte, error)) ([]string, error) {
+// Services returns the LoadBalancer services which pass the filter of the emulator
+func (l *LoadBalancerEmulator) Services() ([]core.Service, error) {
 	services := l.coreV1Client.Services("")
 	serviceList, err := services.List(meta.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
-	restClient := l.coreV1Client.RESTClient()
-
-	var managedServices []string
 
+	var lbs []core.Service
 	for _, svc := range serviceList.Items {
 		if svc.Spec.Type != "LoadBalancer" {
 			klog.V(3).Infof("%s is not type LoadBalancer, skipping.", svc.Name)
 			continue
 		}
+		if !l.filter.Match(svc) {
+			klog.V(3).Infof("%s/%s does not match the tunnel filter, skipping.", svc.Namespace, svc.Name)
+			continue
+		}
+		lbs = append(lbs, svc)
+	}
+	return lbs, nil
+}
+
+func (l *LoadBalancerEmulator) applyOnLBServices(action func(restClient rest.Interface, svc core.Service) ([]byte, error)) ([]string, error) {
+	lbs, err := l.Services()
+	if err != nil {
+		return nil, err
+	}
+	restClient := l.coreV1Client.RESTClient()
+
+	var managedServices []string
+
+	for _, svc := range lbs {
 		klog.Infof("%s is type LoadBalancer.", svc.Name)
 		managedServices = append(managedServices, svc.Name)
 		result, err := action(restClient, svc)
@@ -148,10 +198,16 @@ func (l *LoadBalancerEmulator) cleanupService(restClient rest.Interface, svc cor
 
 // NewLoadBalancerEmulator creates a new LoadBalancerEmulator
 func NewLoadBalancerEmulator(corev1Client typed_core.CoreV1Interface) LoadBalancerEmulator {
+	return NewFilteredLoadBalancerEmulator(corev1Client, ServiceFilter{})
+}
+
+// NewFilteredLoadBalancerEmulator creates a new LoadBalancerEmulator which only manages the services passing a filter
+func NewFilteredLoadBalancerEmulator(corev1Client typed_core.CoreV1Interface, filter ServiceFilter) LoadBalancerEmulator {
 	return LoadBalancerEmulator{
 		coreV1Client:   corev1Client,
 		requestSender:  &defaultRequestSender{},
 		patchConverter: &defaultPatchConverter{},
+		filter:         filter,
 	}
 }
 
@@ -345,3 +345,48 @@ func TestCleanupPatchedIPs(t *testing.T) {
 		t.Errorf("error in number of requests sent.\nExpected: %v, <nil>\nGot: %v", 2, requestSender.requests)
 	}
 }
+
+func TestServiceFilter(t *testing.T) {
+	svc := func(ns, name string) core.Service {
+		return core.Service{ObjectMeta: meta.ObjectMeta{Namespace: ns, Name: name}}
+	}
+	tests := []struct {
+		name   string
+		filter ServiceFilter
+		svc    core.Service
+		want   bool
+	}{
+		{"empty", ServiceFilter{}, svc("default", "web"), true},
+		{"namespace", ServiceFilter{Namespaces: []string{"shop", "default"}}, svc("default", "web"), true},
+		{"other namespace", ServiceFilter{Namespaces: []string{"shop"}}, svc("default", "web"), false},
+		{"name", ServiceFilter{Services: []string{"web"}}, svc("default", "web"), true},
+		{"namespaced name", ServiceFilter{Services: []string{"default/web"}}, svc("default", "web"), true},
+		{"other namespaced name", ServiceFilter{Services: []string{"shop/web"}}, svc("default", "web"), false},
+		{"name outside namespaces", ServiceFilter{Namespaces: []string{"shop"}, Services: []string{"web"}}, svc("default", "web"), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Match(tc.svc); got != tc.want {
+				t.Errorf("%+v.Match(%s/%s) = %t, want %t", tc.filter, tc.svc.Namespace, tc.svc.Name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilteredServices(t *testing.T) {
+	client := newStubCoreClient(&core.ServiceList{
+		Items: []core.Service{
+			{ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: "web"}, Spec: core.ServiceSpec{Type: "LoadBalancer"}},
+			{ObjectMeta: meta.ObjectMeta{Namespace: "shop", Name: "cart"}, Spec: core.ServiceSpec{Type: "LoadBalancer"}},
+			{ObjectMeta: meta.ObjectMeta{Namespace: "shop", Name: "db"}, Spec: core.ServiceSpec{Type: "ClusterIP"}},
+		},
+	})
+	lb := NewFilteredLoadBalancerEmulator(client, ServiceFilter{Namespaces: []string{"shop"}})
+	svcs, err := lb.Services()
+	if err != nil {
+		t.Fatalf("Services: %v", err)
+	}
+	if len(svcs) != 1 || svcs[0].Name != "cart" {
+		t.Errorf("Services() = %+v, want only shop/cart", svcs)
+	}
+}
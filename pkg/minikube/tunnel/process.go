@@ -17,39 +17,19 @@ limitations under the License.
 package tunnel
 
 import (
-	"fmt"
 	"os"
-	"runtime"
-	"syscall"
+
+	"k8s.io/minikube/pkg/minikube/background"
 )
 
 var checkIfRunning func(pid int) (bool, error)
 var getPid func() int
 
 func init() {
-	checkIfRunning = osCheckIfRunning
+	checkIfRunning = background.CheckIfRunning
 	getPid = osGetPid
 }
 
 func osGetPid() int {
 	return os.Getpid()
 }
-
-// TODO(balintp): this is vulnerable to pid reuse we should include process name in the check
-func osCheckIfRunning(pid int) (bool, error) {
-	p, err := os.FindProcess(pid)
-	if runtime.GOOS == "windows" {
-		return err == nil, nil
-	}
-	// on unix systems further checking is required, as findProcess is noop
-	if err != nil {
-		return false, fmt.Errorf("error finding process %d: %s", pid, err)
-	}
-	if err := p.Signal(syscall.Signal(0)); err != nil {
-		return false, nil
-	}
-	if p == nil {
-		return false, nil
-	}
-	return true, nil
-}
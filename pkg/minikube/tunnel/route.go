@@ -18,11 +18,32 @@ package tunnel
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 )
 
+// sudo returns a command running args as root. Without a terminal, as in background tunnels, sudo fails rather than
+// waiting for a password nobody can enter.
+func sudo(args ...string) *exec.Cmd {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		args = append([]string{"-n"}, args...)
+	}
+	return exec.Command("sudo", args...)
+}
+
+// explainRouteError explains why a route could not be added without a terminal, once the credentials of sudo expired
+func explainRouteError(err error) error {
+	if err == nil || isatty.IsTerminal(os.Stdin.Fd()) {
+		return err
+	}
+	return errors.Wrap(err, "sudo can not ask for a password without a terminal: restart the tunnel, or allow the route commands without a password")
+}
+
 // router manages the routing table on the host, implementations should cater for OS specific methods
 type router interface {
 	// Inspect checks if the given route exists or not in the routing table
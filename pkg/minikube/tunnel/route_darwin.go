@@ -47,7 +47,7 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	gatewayIP := route.Gateway.String()
 
 	klog.Infof("Adding route for CIDR %s to gateway %s", serviceCIDR, gatewayIP)
-	command := exec.Command("sudo", "route", "-n", "add", serviceCIDR, gatewayIP)
+	command := sudo("route", "-n", "add", serviceCIDR, gatewayIP)
 	klog.Infof("About to run command: %s", command.Args)
 	stdInAndOut, err := command.CombinedOutput()
 	message := fmt.Sprintf("%s", stdInAndOut)
@@ -159,7 +159,7 @@ func (router *osRouter) Cleanup(route *Route) error {
 	if !exists {
 		return nil
 	}
-	cmd := exec.Command("sudo", "route", "-n", "delete", route.DestCIDR.String())
+	cmd := sudo("route", "-n", "delete", route.DestCIDR.String())
 	stdInAndOut, err := cmd.CombinedOutput()
 	if err != nil {
 		return err
@@ -172,7 +172,7 @@ func (router *osRouter) Cleanup(route *Route) error {
 	}
 	// idempotent removal of cluster domain dns
 	resolverFile := fmt.Sprintf("/etc/resolver/%s", route.ClusterDomain)
-	cmd = exec.Command("sudo", "rm", "-f", resolverFile)
+	cmd = sudo("rm", "-f", resolverFile)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("could not remove %s: %s", resolverFile, err)
 	}
@@ -205,7 +205,7 @@ func writeResolverFile(route *Route) error {
 		return errors.Wrap(err, "chmod")
 	}
 
-	cmd := exec.Command("sudo", "mkdir", "-p", filepath.Dir(resolverFile))
+	cmd := sudo("mkdir", "-p", filepath.Dir(resolverFile))
 	_, err = cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -214,7 +214,7 @@ func writeResolverFile(route *Route) error {
 		return errors.Wrap(err, "mkdir")
 	}
 
-	cmd = exec.Command("sudo", "cp", "-fp", tf.Name(), resolverFile)
+	cmd = sudo("cp", "-fp", tf.Name(), resolverFile)
 
 	_, err = cmd.Output()
 	if err != nil {
@@ -39,7 +39,7 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	gatewayIP := route.Gateway.String()
 
 	klog.Infof("Adding route for CIDR %s to gateway %s", serviceCIDR, gatewayIP)
-	command := exec.Command("sudo", "route", "-n", "add", serviceCIDR, gatewayIP)
+	command := sudo("route", "-n", "add", serviceCIDR, gatewayIP)
 	klog.Infof("About to run command: %s", command.Args)
 	stdInAndOut, err := command.CombinedOutput()
 	message := fmt.Sprintf("%s", stdInAndOut)
@@ -151,7 +151,7 @@ func (router *osRouter) Cleanup(route *Route) error {
 	if !exists {
 		return nil
 	}
-	cmd := exec.Command("sudo", "route", "-n", "delete", route.DestCIDR.String())
+	cmd := sudo("route", "-n", "delete", route.DestCIDR.String())
 	stdInAndOut, err := cmd.CombinedOutput()
 	if err != nil {
 		return err
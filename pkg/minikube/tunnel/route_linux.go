@@ -38,7 +38,7 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	gatewayIP := route.Gateway.String()
 
 	klog.Infof("Adding route for CIDR %s to gateway %s", serviceCIDR, gatewayIP)
-	command := exec.Command("sudo", "ip", "route", "add", serviceCIDR, "via", gatewayIP)
+	command := sudo("ip", "route", "add", serviceCIDR, "via", gatewayIP)
 	klog.Infof("About to run command: %s", command.Args)
 	stdInAndOut, err := command.CombinedOutput()
 	message := string(stdInAndOut)
@@ -127,7 +127,7 @@ func (router *osRouter) Cleanup(route *Route) error {
 	gatewayIP := route.Gateway.String()
 
 	klog.Infof("Cleaning up route for CIDR %s to gateway %s\n", serviceCIDR, gatewayIP)
-	command := exec.Command("sudo", "ip", "route", "delete", serviceCIDR)
+	command := sudo("ip", "route", "delete", serviceCIDR)
 	stdInAndOut, err := command.CombinedOutput()
 	message := fmt.Sprintf("%s", stdInAndOut)
 	klog.Infof("%s", message)
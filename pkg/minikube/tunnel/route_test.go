@@ -17,10 +17,14 @@ limitations under the License.
 package tunnel
 
 import (
+	"errors"
 	"net"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/mattn/go-isatty"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -141,3 +145,17 @@ func unsafeParseRoute(gatewayIP string, destCIDR string) *Route {
 	}
 	return expectedRoute
 }
+
+func TestSudoWithoutTerminal(t *testing.T) {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		t.Skip("stdin is a terminal")
+	}
+	got := sudo("ip", "route", "add", "10.96.0.0/12", "via", "192.168.49.2").Args
+	want := []string{"sudo", "-n", "ip", "route", "add", "10.96.0.0/12", "via", "192.168.49.2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sudo() = %v, want %v", got, want)
+	}
+	if err := explainRouteError(errors.New("sudo: a password is required")); !strings.Contains(err.Error(), "without a terminal") {
+		t.Errorf("explainRouteError() = %v, want an explanation of the missing terminal", err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/background"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// State is the state of the running tunnel of a profile, kept so that other minikube commands can find it
+type State struct {
+	Pid        int
+	Started    time.Time
+	Background bool
+	Filter     ServiceFilter
+}
+
+// StatePath returns the path of the state of the tunnel of a profile
+func StatePath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "tunnel.json")
+}
+
+// LogPath returns the path of the log of the background tunnel of a profile
+func LogPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "tunnel.log")
+}
+
+// SaveState records the state of the running tunnel of a profile
+func SaveState(profile string, s State) error {
+	return background.SaveState(StatePath(profile), s)
+}
+
+// RemoveState removes the state of the tunnel of a profile, once it exits
+func RemoveState(profile string) error {
+	return background.RemoveState(StatePath(profile))
+}
+
+// LoadState returns the state of the running tunnel of a profile, or nil if none is running
+func LoadState(profile string) (*State, error) {
+	var s State
+	running, err := background.LoadState(StatePath(profile), &s)
+	if err != nil || !running {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Routes returns the routes held by the running tunnels of a machine
+func Routes(machineName string) ([]*Route, error) {
+	registry := &persistentRegistry{path: RegistryPath()}
+	tunnels, err := registry.List()
+	if err != nil {
+		return nil, err
+	}
+	var routes []*Route
+	for _, t := range tunnels {
+		if t.MachineName != machineName {
+			continue
+		}
+		running, err := checkIfRunning(t.Pid)
+		if err != nil {
+			return nil, err
+		}
+		if running {
+			routes = append(routes, t.Route)
+		}
+	}
+	return routes, nil
+}
+
+// Stop stops the running tunnel of a profile, waiting up to timeout for it to clean up its routes and services
+func Stop(profile string, timeout time.Duration) error {
+	return errors.Wrap(background.Stop(StatePath(profile), timeout), "tunnel")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/background"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestState(t *testing.T) {
+	home, err := ioutil.TempDir("", "minikube-tunnel")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, home)
+
+	origPidChecker := background.CheckIfRunning
+	background.CheckIfRunning = mockPidChecker
+	defer func() { background.CheckIfRunning = origPidChecker }()
+
+	if st, err := LoadState("p1"); err != nil || st != nil {
+		t.Fatalf("LoadState() without a tunnel = %+v, %v, want nil", st, err)
+	}
+
+	want := State{Pid: RunningPid1, Started: time.Now().Round(time.Second), Background: true, Filter: ServiceFilter{Namespaces: []string{"shop"}}}
+	if err := SaveState("p1", want); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	st, err := LoadState("p1")
+	if err != nil || st == nil {
+		t.Fatalf("LoadState() = %+v, %v, want the running tunnel", st, err)
+	}
+	if st.Pid != want.Pid || !st.Started.Equal(want.Started) || !st.Background || len(st.Filter.Namespaces) != 1 {
+		t.Errorf("LoadState() = %+v, want %+v", st, want)
+	}
+
+	// The state of a tunnel which exited without cleaning up is removed
+	if err := SaveState("p1", State{Pid: NotRunningPid}); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	if st, err := LoadState("p1"); err != nil || st != nil {
+		t.Errorf("LoadState() of an exited tunnel = %+v, %v, want nil", st, err)
+	}
+	if _, err := os.Stat(StatePath("p1")); !os.IsNotExist(err) {
+		t.Errorf("state of an exited tunnel was not removed: %v", err)
+	}
+}
@@ -93,10 +93,18 @@ type tunnel struct {
 	registry             *persistentRegistry
 
 	status *Status
+	// reconnect removes the route while minikube is stopped, and restores it once minikube runs again
+	reconnect bool
+	// disconnected is set once the route was removed because minikube stopped
+	disconnected bool
 }
 
 func (t *tunnel) cleanup() *Status {
 	klog.V(3).Infof("cleaning up %s", t.status.TunnelID.Route)
+	if t.disconnected {
+		// the route was already removed when minikube stopped
+		return t.status
+	}
 	err := t.router.Cleanup(t.status.TunnelID.Route)
 	if err != nil {
 		t.status.RouteError = errors.Errorf("error cleaning up route: %v", err)
@@ -118,7 +126,13 @@ func (t *tunnel) update() *Status {
 	var h *host.Host
 	t.status.MinikubeState, h, t.status.MinikubeError = t.clusterInspector.getStateAndHost()
 	defer t.clusterInspector.machineAPI.Close()
-	if t.status.MinikubeState == Running {
+	if t.reconnect && t.status.MinikubeState == Stopped && !t.disconnected {
+		t.disconnect()
+	}
+	if t.status.MinikubeState == Running && t.disconnected {
+		t.reconnectRoute()
+	}
+	if t.status.MinikubeState == Running && !t.disconnected {
 		klog.V(3).Infof("minikube is running, trying to add route%s", t.status.TunnelID.Route)
 		setupRoute(t, h)
 		if t.status.RouteError == nil {
@@ -130,6 +144,31 @@ func (t *tunnel) update() *Status {
 	return t.status
 }
 
+// disconnect removes the route of a stopped minikube, whose IP may change once it runs again
+func (t *tunnel) disconnect() {
+	klog.Infof("minikube stopped, removing route %s until it runs again", t.status.TunnelID.Route)
+	if err := t.router.Cleanup(t.status.TunnelID.Route); err != nil {
+		klog.Warningf("error cleaning up route: %v", err)
+	}
+	if err := t.registry.Remove(t.status.TunnelID.Route); err != nil {
+		klog.V(3).Infof("error removing route from registry: %v", err)
+	}
+	t.disconnected = true
+}
+
+// reconnectRoute looks up the route of minikube once it runs again
+func (t *tunnel) reconnectRoute() {
+	_, route, err := t.clusterInspector.getStateAndRoute()
+	if err != nil {
+		t.status.RouteError = errors.Wrap(err, "reconnecting")
+		return
+	}
+	klog.Infof("minikube runs again, restoring route %s", route)
+	t.status.TunnelID.Route = route
+	t.status.RouteError = nil
+	t.disconnected = false
+}
+
 func setupRoute(t *tunnel, h *host.Host) {
 	exists, conflict, _, err := t.router.Inspect(t.status.TunnelID.Route)
 	if err != nil {
@@ -138,7 +177,7 @@ func setupRoute(t *tunnel, h *host.Host) {
 	}
 
 	if !exists && len(conflict) == 0 {
-		t.status.RouteError = t.router.EnsureRouteIsAdded(t.status.TunnelID.Route)
+		t.status.RouteError = explainRouteError(t.router.EnsureRouteIsAdded(t.status.TunnelID.Route))
 		if t.status.RouteError != nil {
 			return
 		}
@@ -208,7 +247,7 @@ func setupBridge(t *tunnel) {
 	}
 
 	member := submatch[1]
-	command = exec.Command("sudo", "ifconfig", "bridge100", "deletem", member)
+	command = sudo("ifconfig", "bridge100", "deletem", member)
 	klog.Infof("About to run command: %s\n", command.Args)
 	response, err = command.CombinedOutput()
 	klog.Infof(string(response))
@@ -217,7 +256,7 @@ func setupBridge(t *tunnel) {
 		return
 	}
 
-	command = exec.Command("sudo", "ifconfig", "bridge100", "addm", member)
+	command = sudo("ifconfig", "bridge100", "addm", member)
 	klog.Infof("About to run command: %s\n", command.Args)
 	response, err = command.CombinedOutput()
 	klog.Infof(string(response))
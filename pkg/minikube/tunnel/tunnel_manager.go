@@ -37,6 +37,16 @@ type Manager struct {
 	delay    time.Duration
 	registry *persistentRegistry
 	router   router
+	// reconnect keeps the tunnel running while minikube is stopped
+	reconnect bool
+}
+
+// Options configures a tunnel
+type Options struct {
+	// Filter limits the LoadBalancer services which are tunnelled
+	Filter ServiceFilter
+	// Reconnect keeps the tunnel running while the cluster is stopped, and restores its route once it runs again
+	Reconnect bool
 }
 
 // stateCheckInterval defines how frequently the cluster and route states are checked
@@ -59,11 +69,14 @@ func NewManager() *Manager {
 }
 
 // StartTunnel starts the tunnel
-func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, v1Core typed_core.CoreV1Interface) (done chan bool, err error) {
+func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, v1Core typed_core.CoreV1Interface, opts Options) (done chan bool, err error) {
 	tunnel, err := newTunnel(machineName, machineAPI, configLoader, v1Core, mgr.registry, mgr.router)
 	if err != nil {
 		return nil, fmt.Errorf("error creating tunnel: %s", err)
 	}
+	tunnel.LoadBalancerEmulator = NewFilteredLoadBalancerEmulator(v1Core, opts.Filter)
+	tunnel.reconnect = opts.Reconnect
+	mgr.reconnect = opts.Reconnect
 	return mgr.startTunnel(ctx, tunnel)
 
 }
@@ -113,9 +126,12 @@ func (mgr *Manager) run(ctx context.Context, t controller, ready, check, done ch
 			status := t.update()
 			klog.V(4).Infof("minikube status: %s", status)
 			if status.MinikubeState != Running {
-				klog.Infof("minikube status: %s, cleaning up and quitting...", status.MinikubeState)
-				mgr.cleanup(t)
-				return
+				if !mgr.reconnect || status.MinikubeState == Unknown {
+					klog.Infof("minikube status: %s, cleaning up and quitting...", status.MinikubeState)
+					mgr.cleanup(t)
+					return
+				}
+				klog.Infof("minikube status: %s, waiting for it to run again...", status.MinikubeState)
 			}
 			ready <- true
 		}
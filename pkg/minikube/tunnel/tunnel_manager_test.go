@@ -31,9 +31,10 @@ import (
 func TestTunnelManagerEventHandling(t *testing.T) {
 	tcs := []struct {
 		// tunnel inputs
-		name   string
-		repeat int
-		test   func(tunnel *tunnelStub, cancel context.CancelFunc, ready, check, done chan bool) error
+		name      string
+		repeat    int
+		reconnect bool
+		test      func(tunnel *tunnelStub, cancel context.CancelFunc, ready, check, done chan bool) error
 	}{
 		{
 			name:   "tunnel quits on stopped minikube",
@@ -60,6 +61,33 @@ func TestTunnelManagerEventHandling(t *testing.T) {
 			},
 		},
 
+		{
+			name:      "tunnel waits for stopped minikube when reconnecting",
+			repeat:    1,
+			reconnect: true,
+			test: func(tunnel *tunnelStub, cancel context.CancelFunc, ready, check, done chan bool) error {
+				tunnel.mockClusterInfo = &Status{
+					MinikubeState: Stopped,
+				}
+				<-ready
+				check <- true
+				select {
+				case <-done:
+					t.Error("tunnel stopped on stopped minikube")
+				case <-ready:
+				case <-time.After(1 * time.Second):
+					t.Error("tunnel is not ready for the next check")
+				}
+				cancel()
+				check <- true
+				select {
+				case <-done:
+				case <-time.After(1 * time.Second):
+					t.Error("tunnel did not stop on ctrl c")
+				}
+				return nil
+			},
+		},
 		{
 			name:   "tunnel quits on ctrlc before doing a check",
 			repeat: 1,
@@ -126,7 +154,7 @@ func TestTunnelManagerEventHandling(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			var err error
 			for i := 1; i <= tc.repeat && err == nil; i++ {
-				tunnelManager := &Manager{}
+				tunnelManager := &Manager{reconnect: tc.reconnect}
 				tunnel := &tunnelStub{}
 
 				ready := make(chan bool, 1)
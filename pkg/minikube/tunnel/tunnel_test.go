@@ -500,3 +500,64 @@ func TestErrorCreatingTunnel(t *testing.T) {
 		t.Errorf("expected error containing 'error loading machine', got %s", err)
 	}
 }
+
+func TestTunnelReconnect(t *testing.T) {
+	machineName := "testmachine"
+	d := &tests.MockDriver{CurrentState: state.Running, IP: "1.2.3.4"}
+	machineAPI := &tests.MockAPI{
+		FakeStore: tests.FakeStore{
+			Hosts: map[string]*host.Host{machineName: {Driver: d}},
+		},
+	}
+	configLoader := &stubConfigLoader{
+		c: &config.ClusterConfig{
+			KubernetesConfig: config.KubernetesConfig{
+				ServiceCIDR: "10.96.0.0/12",
+			}},
+	}
+	registry, cleanup := createTestRegistry(t)
+	defer cleanup()
+
+	router := &fakeRouter{}
+	tunnel, err := newTunnel(machineName, machineAPI, configLoader, newStubCoreClient(nil), registry, router)
+	if err != nil {
+		t.Fatalf("error creating tunnel: %s", err)
+	}
+	tunnel.reporter = &recordingReporter{}
+	tunnel.reconnect = true
+
+	gateways := func() []string {
+		var gws []string
+		for _, r := range router.rt {
+			gws = append(gws, r.route.Gateway.String())
+		}
+		return gws
+	}
+
+	tunnel.update()
+	if got := gateways(); !reflect.DeepEqual(got, []string{"1.2.3.4"}) {
+		t.Fatalf("routes of running minikube = %v, want [1.2.3.4]", got)
+	}
+
+	d.CurrentState = state.Stopped
+	if st := tunnel.update(); st.MinikubeState != Stopped {
+		t.Fatalf("state = %s, want Stopped", st.MinikubeState)
+	}
+	if got := gateways(); len(got) != 0 {
+		t.Errorf("routes of stopped minikube = %v, want none", got)
+	}
+	if tunnels, _ := registry.List(); len(tunnels) != 0 {
+		t.Errorf("registry of stopped minikube = %v, want empty", tunnels)
+	}
+
+	// minikube comes back with another IP
+	d.CurrentState = state.Running
+	d.IP = "1.2.3.5"
+	tunnel.update()
+	if got := gateways(); !reflect.DeepEqual(got, []string{"1.2.3.5"}) {
+		t.Errorf("routes of restarted minikube = %v, want [1.2.3.5]", got)
+	}
+	if tunnels, _ := registry.List(); len(tunnels) != 1 || tunnels[0].Route.Gateway.String() != "1.2.3.5" {
+		t.Errorf("registry of restarted minikube = %v, want the new route", tunnels)
+	}
+}
@@ -18,11 +18,13 @@ package util
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"math/big"
 	"net"
@@ -67,33 +69,17 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 // GenerateSignedCert generates a signed certificate and key
 func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string) error {
 	klog.Infof("Generating cert %s with IP's: %s", certPath, ips)
-	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
+	signerCert, signerKey, err := loadSigner(signerCertPath, signerKeyPath)
 	if err != nil {
-		return errors.Wrap(err, "Error reading file: signerCertPath")
+		return err
 	}
-	decodedSignerCert, _ := pem.Decode(signerCertBytes)
-	if decodedSignerCert == nil {
-		return errors.New("Unable to decode certificate")
-	}
-	signerCert, err := x509.ParseCertificate(decodedSignerCert.Bytes)
-	if err != nil {
-		return errors.Wrap(err, "Error parsing certificate: decodedSignerCert.Bytes")
-	}
-	signerKeyBytes, err := ioutil.ReadFile(signerKeyPath)
-	if err != nil {
-		return errors.Wrap(err, "Error reading file: signerKeyPath")
-	}
-	decodedSignerKey, _ := pem.Decode(signerKeyBytes)
-	if decodedSignerKey == nil {
-		return errors.New("Unable to decode key")
-	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+	serial, err := randomSerial()
 	if err != nil {
-		return errors.Wrap(err, "Error parsing private key: decodedSignerKey.Bytes")
+		return err
 	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(2),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName:   cn,
 			Organization: []string{"system:masters"},
@@ -117,6 +103,148 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
+// GenerateClientCert generates a client certificate and key for a user in the given groups
+func GenerateClientCert(certPath, keyPath, cn string, groups []string, signerCertPath, signerKeyPath string) error {
+	klog.Infof("Generating client cert %s for %s", certPath, cn)
+	signerCert, signerKey, err := loadSigner(signerCertPath, signerKeyPath)
+	if err != nil {
+		return err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   cn,
+			Organization: groups,
+		},
+		NotBefore: time.Now().Add(time.Hour * -24),
+		NotAfter:  time.Now().Add(time.Hour * 24 * 365),
+
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	priv, err := loadOrGeneratePrivateKey(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "Error loading or generating private key: keyPath")
+	}
+
+	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
+}
+
+// ValidateCA returns an error unless certPath is a CA certificate which is valid now, and keyPath its private key
+func ValidateCA(certPath, keyPath string) error {
+	cert, key, err := loadSigner(certPath, keyPath)
+	if err != nil {
+		return err
+	}
+	if !cert.IsCA {
+		return fmt.Errorf("%s is not a CA certificate", certPath)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return fmt.Errorf("%s is only valid from %s until %s", certPath, cert.NotBefore, cert.NotAfter)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return errors.Wrap(err, "marshal public key")
+	}
+	certPub, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "marshal public key of certificate")
+	}
+	if !bytes.Equal(pub, certPub) {
+		return fmt.Errorf("%s is not the private key of %s", keyPath, certPath)
+	}
+	return nil
+}
+
+// VerifyCert returns an error unless certPath is signed by caCertPath, and remains valid for at least the given duration
+func VerifyCert(certPath, caCertPath string, validFor time.Duration) error {
+	cert, err := ReadCert(certPath)
+	if err != nil {
+		return err
+	}
+	ca, err := ReadCert(caCertPath)
+	if err != nil {
+		return err
+	}
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		return errors.Wrapf(err, "%s is not signed by %s", certPath, caCertPath)
+	}
+	if time.Now().Add(validFor).After(cert.NotAfter) {
+		return fmt.Errorf("%s expires at %s", certPath, cert.NotAfter)
+	}
+	return nil
+}
+
+// ReadCert reads a PEM encoded certificate
+func ReadCert(certPath string) (*x509.Certificate, error) {
+	b, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading file: %s", certPath)
+	}
+	return ParseCert(b)
+}
+
+// ParseCert parses the first certificate of PEM encoded data
+func ParseCert(b []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return nil, errors.New("Unable to decode certificate")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// loadSigner reads a PEM encoded certificate and its PKCS1, PKCS8 or EC private key
+func loadSigner(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	cert, err := ReadCert(certPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing certificate: signerCertPath")
+	}
+	keyBytes, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error reading file: signerKeyPath")
+	}
+	decodedKey, _ := pem.Decode(keyBytes)
+	if decodedKey == nil {
+		return nil, nil, errors.New("Unable to decode key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(decodedKey.Bytes); err == nil {
+		return cert, key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(decodedKey.Bytes); err == nil {
+		return cert, key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(decodedKey.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing private key: decodedSignerKey.Bytes")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a signing key", keyPath)
+	}
+	return cert, signer, nil
+}
+
+// randomSerial returns a random serial number, so that certificates signed by a shared CA are unique
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating serial number")
+	}
+	return serial, nil
+}
+
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	keyBytes, err := ioutil.ReadFile(keyPath)
 	if err == nil {
@@ -135,7 +263,7 @@ func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	return priv, nil
 }
 
-func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey *rsa.PrivateKey) error {
+func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey crypto.Signer) error {
 	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &signeeKey.PublicKey, signingKey)
 	if err != nil {
 		return errors.Wrap(err, "Error creating certificate")
@@ -17,13 +17,19 @@ limitations under the License.
 package util
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -163,3 +169,126 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+// writeECCA writes a self-signed ECDSA CA with a PKCS8 encoded key, as corporate CAs often are
+func writeECCA(t *testing.T, certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Corporate CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour * 365 * 5),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	kb, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: kb}), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestValidateCA(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	p := func(name string) string { return filepath.Join(tmpDir, name) }
+	if err := GenerateCACert(p("ca.crt"), p("ca.key"), "minikubeCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	if err := GenerateCACert(p("other.crt"), p("other.key"), "otherCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	if err := GenerateSignedCert(p("signed.crt"), p("signed.key"), "minikube", nil, nil, p("ca.crt"), p("ca.key")); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+	writeECCA(t, p("ec.crt"), p("ec.key"))
+
+	var tests = []struct {
+		description string
+		certPath    string
+		keyPath     string
+		err         bool
+	}{
+		{"rsa CA", p("ca.crt"), p("ca.key"), false},
+		{"pkcs8 ecdsa CA", p("ec.crt"), p("ec.key"), false},
+		{"not a CA", p("signed.crt"), p("signed.key"), true},
+		{"key of another CA", p("ca.crt"), p("other.key"), true},
+		{"missing key", p("ca.crt"), p("missing.key"), true},
+	}
+	for _, test := range tests {
+		err := ValidateCA(test.certPath, test.keyPath)
+		if (err != nil) != test.err {
+			t.Errorf("%s: ValidateCA() error = %v, expected error: %v", test.description, err, test.err)
+		}
+	}
+
+	// certificates signed by a PKCS8 encoded key verify against its CA
+	if err := GenerateSignedCert(p("ec-signed.crt"), p("ec-signed.key"), "minikube", nil, nil, p("ec.crt"), p("ec.key")); err != nil {
+		t.Fatalf("GenerateSignedCert() with an ecdsa CA error = %v", err)
+	}
+	if err := VerifyCert(p("ec-signed.crt"), p("ec.crt"), 0); err != nil {
+		t.Errorf("VerifyCert() error = %v", err)
+	}
+}
+
+func TestVerifyCert(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	p := func(name string) string { return filepath.Join(tmpDir, name) }
+	if err := GenerateCACert(p("ca.crt"), p("ca.key"), "minikubeCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	if err := GenerateCACert(p("other.crt"), p("other.key"), "otherCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	if err := GenerateClientCert(p("client.crt"), p("client.key"), "system:node:minikube", []string{"system:nodes"}, p("ca.crt"), p("ca.key")); err != nil {
+		t.Fatalf("GenerateClientCert() error = %v", err)
+	}
+
+	var tests = []struct {
+		description string
+		caCertPath  string
+		validFor    time.Duration
+		err         bool
+	}{
+		{"valid", p("ca.crt"), 30 * 24 * time.Hour, false},
+		{"expires within the duration", p("ca.crt"), 2 * 365 * 24 * time.Hour, true},
+		{"signed by another CA", p("other.crt"), 0, true},
+	}
+	for _, test := range tests {
+		err := VerifyCert(p("client.crt"), test.caCertPath, test.validFor)
+		if (err != nil) != test.err {
+			t.Errorf("%s: VerifyCert() error = %v, expected error: %v", test.description, err, test.err)
+		}
+	}
+
+	c, err := ReadCert(p("client.crt"))
+	if err != nil {
+		t.Fatalf("ReadCert() error = %v", err)
+	}
+	if c.Subject.CommonName != "system:node:minikube" || len(c.Subject.Organization) != 1 || c.Subject.Organization[0] != "system:nodes" {
+		t.Errorf("client cert subject = %v, expected system:node:minikube in system:nodes", c.Subject)
+	}
+}
@@ -37,6 +37,9 @@ var isoVersion = "v0.0.0-unset"
 // storageProvisionerVersion is a private field and should be set when compiling with --ldflags="-X k8s.io/minikube/pkg/version.storageProvisionerVersion=<storage-provisioner-version>"
 var storageProvisionerVersion = ""
 
+// loadBalancerControllerVersion is a private field and should be set when compiling with --ldflags="-X k8s.io/minikube/pkg/version.loadBalancerControllerVersion=<loadbalancer-controller-version>"
+var loadBalancerControllerVersion = ""
+
 // GetVersion returns the current minikube version
 func GetVersion() string {
 	return version
@@ -61,3 +64,8 @@ func GetSemverVersion() (semver.Version, error) {
 func GetStorageProvisionerVersion() string {
 	return storageProvisionerVersion
 }
+
+// GetLoadBalancerControllerVersion returns the load balancer controller version
+func GetLoadBalancerControllerVersion() string {
+	return loadBalancerControllerVersion
+}